@@ -42,8 +42,11 @@ func createMeetupDir(gs meetup.GroupStrategy) error {
 		Editor:  []string{"true"}, // noop
 		DefaultMetadata: meetup.Metadata{
 			GroupBy: gs,
-			DomainTemplates: map[string]string{
-				"meetup.test": "simple.md",
+			TemplateRules: []meetup.TemplateRule{
+				{
+					Domain:   "meetup.test",
+					Template: "simple.md",
+				},
 			},
 		},
 	}
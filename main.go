@@ -1,11 +1,21 @@
 package main
 
 import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path"
+	"slices"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
+	"unicode"
 
 	"github.com/gobwas/glob"
 	meetup "github.com/joshmeranda/meetup/pkg"
@@ -13,43 +23,55 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-const (
-	DateFormat = "2006-01-02"
-)
-
 var Version string
 
-func LoadManagerConfig() (meetup.Config, error) {
-	config, err := meetup.DefaultConfig()
+// LoadConfigFile loads the user's config file, returning it along with its path.
+func LoadConfigFile() (meetup.ConfigFile, string, error) {
+	configPath, err := meetup.ConfigFilePath()
 	if err != nil {
-		return meetup.Config{}, fmt.Errorf("could not create default config: %w", err)
+		return meetup.ConfigFile{}, "", err
 	}
 
-	configDir, err := os.UserConfigDir()
+	file, err := meetup.LoadConfigFile(configPath)
 	if err != nil {
-		return meetup.Config{}, fmt.Errorf("could not find user config dir: %w", err)
+		return meetup.ConfigFile{}, "", err
 	}
 
-	configPath := path.Join(configDir, "meetup", "config.yaml")
+	return file, configPath, nil
+}
 
-	bytes, err := os.ReadFile(configPath)
+// LoadEffectiveConfig resolves the configuration for the profile selected by the --profile flag (or the
+// MEETUP_PROFILE environment variable), falling back to the profile selected in the config file. Values are applied in
+// order of increasing precedence: defaults, the config file, the selected profile, MEETUP_* environment variables, and
+// finally the global --root and --editor flags.
+func LoadEffectiveConfig(ctx *cli.Context) (meetup.EffectiveConfig, error) {
+	file, _, err := LoadConfigFile()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return config, nil
-		}
+		return meetup.EffectiveConfig{}, err
+	}
 
-		return meetup.Config{}, fmt.Errorf("could not read config file: %w", err)
+	flagLayer := meetup.ConfigLayer{
+		Source: meetup.SourceFlag,
+		Config: meetup.Config{
+			RootDir: ctx.String("root"),
+			Editor:  strings.Fields(ctx.String("editor")),
+		},
 	}
 
-	if err := yaml.Unmarshal(bytes, &config); err != nil {
-		return meetup.Config{}, fmt.Errorf("could not parse config file: %w", err)
+	return file.Resolve(file.ActiveProfile(ctx.String("profile")), meetup.EnvLayer(), flagLayer)
+}
+
+func LoadManagerConfig(ctx *cli.Context) (meetup.Config, error) {
+	effective, err := LoadEffectiveConfig(ctx)
+	if err != nil {
+		return meetup.Config{}, err
 	}
 
-	return config, nil
+	return effective.Config, nil
 }
 
-func GetManager() (meetup.Manager, error) {
-	config, err := LoadManagerConfig()
+func GetManager(ctx *cli.Context) (meetup.Manager, error) {
+	config, err := LoadManagerConfig(ctx)
 	if err != nil {
 		return meetup.Manager{}, fmt.Errorf("could not create manager: %w", err)
 	}
@@ -57,232 +79,2593 @@ func GetManager() (meetup.Manager, error) {
 	return meetup.NewManager(config)
 }
 
-func MeetingOpen(ctx *cli.Context) error {
-	if ctx.NArg() > 1 {
-		return fmt.Errorf("too many arguments")
+// ProfileManager is a manager for a named profile.
+type ProfileManager struct {
+	meetup.Manager
+
+	// Profile is the name of the manager's profile, or empty when only a single profile is in use.
+	Profile string
+}
+
+// GetManagers returns a manager for each profile when --all-profiles is set, or only for the active profile otherwise.
+func GetManagers(ctx *cli.Context) ([]ProfileManager, error) {
+	if !ctx.Bool("all-profiles") {
+		manager, err := GetManager(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return []ProfileManager{{Manager: manager}}, nil
 	}
 
-	if ctx.NArg() < 1 {
-		return fmt.Errorf("missing required arguments")
+	file, _, err := LoadConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	var managers []ProfileManager
+
+	for _, name := range file.ProfileNames() {
+		config, err := file.ProfileConfig(name)
+		if err != nil {
+			return nil, err
+		}
+
+		manager, err := meetup.NewManager(config)
+		if err != nil {
+			return nil, fmt.Errorf("could not create manager for profile '%s': %w", name, err)
+		}
+
+		managers = append(managers, ProfileManager{Manager: manager, Profile: name})
+	}
+
+	return managers, nil
+}
+
+// profilePrefix returns the prefix used to mark output with the profile it came from.
+func profilePrefix(profile string) string {
+	if profile == "" {
+		return ""
 	}
 
-	rawDomain := ctx.Args().First()
+	return fmt.Sprintf("(%s) ", profile)
+}
+
+// ParseMeetingName splits a meeting name in the form <domain>.<name> into its domain and name.
+func ParseMeetingName(rawDomain string) (string, string, error) {
 	lastSep := strings.LastIndex(rawDomain, ".")
 
 	if lastSep == -1 {
-		return fmt.Errorf("invalid domain: '%s'", rawDomain)
+		return "", "", fmt.Errorf("invalid domain: '%s'", rawDomain)
 	}
 
 	domain, name := rawDomain[:lastSep], rawDomain[lastSep+1:]
 
 	if domain == "" {
-		return fmt.Errorf("invalid domain: '%s'", domain)
+		return "", "", fmt.Errorf("invalid domain: '%s'", domain)
 	}
 
 	if name == "" {
-		return fmt.Errorf("invalid name: '%s'", name)
+		return "", "", fmt.Errorf("invalid name: '%s'", name)
 	}
 
-	manager, err := GetManager()
+	return domain, name, nil
+}
+
+// parseMeeting builds a meeting from a date and a name in the form <domain>.<name>[@<time>][+<sequence>].
+func parseMeeting(rawDate string, rawName string) (meetup.Meeting, error) {
+	domain, name, err := ParseMeetingName(rawName)
 	if err != nil {
-		return err
+		return meetup.Meeting{}, err
 	}
 
-	manager.OpenMeeting(meetup.Meeting{
-		Name:     name,
-		Domain:   domain,
-		Date:     ctx.String("date"),
-		Template: ctx.String("template"),
-	})
+	date, err := parseDate(rawDate)
+	if err != nil {
+		return meetup.Meeting{}, err
+	}
 
-	return nil
+	meeting := meetup.Meeting{
+		Domain: domain,
+		Date:   date,
+	}
+
+	meeting.Name, meeting.Time, meeting.Sequence = meetup.SplitMeetingName(name)
+
+	return meeting, nil
 }
 
-func MeetingList(ctx *cli.Context) error {
-	manager, err := GetManager()
-	if err != nil {
-		return err
+// meetingQueryFlags returns the flags used to filter meetings.
+func meetingQueryFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "date",
+			Usage: "date of the meeting as a wildcard",
+			Value: "*",
+		},
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "the name of the meeting as a wildcard",
+			Value: "*",
+		},
+		&cli.StringFlag{
+			Name:  "domain",
+			Usage: "the domain of the meeting as a wildcard",
+			Value: "*",
+		},
+		&cli.StringFlag{
+			Name:  "since",
+			Usage: "only match meetings on or after this date (eg 2024-01-31, yesterday, last monday, -2w)",
+		},
+		&cli.StringFlag{
+			Name:  "until",
+			Usage: "only match meetings on or before this date",
+		},
+		&cli.BoolFlag{
+			Name:  "this-week",
+			Usage: "only match meetings from this week",
+		},
+		&cli.BoolFlag{
+			Name:  "last-week",
+			Usage: "only match meetings from last week",
+		},
+		&cli.BoolFlag{
+			Name:  "this-month",
+			Usage: "only match meetings from this month",
+		},
+		&cli.BoolFlag{
+			Name:  "last-month",
+			Usage: "only match meetings from last month",
+		},
 	}
+}
 
-	meetings, err := manager.ListMeetings(meetup.MeetingQuery{
-		Name:   glob.MustCompile(ctx.String("name")),
-		Date:   glob.MustCompile(ctx.String("date")),
-		Domain: glob.MustCompile(ctx.String("domain")),
-	})
+// viewFilterFlags returns the meeting and task filters accepted by view save, which are stored as query terms.
+func viewFilterFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "the name of the meeting as a wildcard, saved as name:<name>",
+		},
+		&cli.StringFlag{
+			Name:  "domain",
+			Usage: "the domain of the meeting as a wildcard, saved as domain:<domain>",
+		},
+		&cli.StringFlag{
+			Name:  "date",
+			Usage: "date of the meeting as a wildcard, saved as date:<date>",
+		},
+		&cli.StringFlag{
+			Name:  "since",
+			Usage: "only match meetings on or after this date, saved as date>=<since> so relative dates stay relative",
+		},
+		&cli.StringFlag{
+			Name:  "until",
+			Usage: "only match meetings on or before this date, saved as date<=<until>",
+		},
+		&cli.BoolFlag{
+			Name:  "complete",
+			Usage: "only match completed tasks, saved as is:complete",
+		},
+		&cli.BoolFlag{
+			Name:  "incomplete",
+			Usage: "only match incomplete tasks, saved as is:incomplete",
+		},
+		&cli.StringSliceFlag{
+			Name:  "state",
+			Usage: "only match tasks in the named states, saved as state=<state>",
+		},
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "the description of the task as a wildcard, saved as desc:<description>",
+		},
+	}
+}
+
+// listFlags returns the flags used to sort and paginate listings.
+func listFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "sort",
+			Usage: "comma separated fields to sort by, prefix a field with - to sort descending (date, domain, name, modified, tasks, description)",
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "show at most this many results",
+		},
+		&cli.IntFlag{
+			Name:  "offset",
+			Usage: "skip this many results",
+		},
+		&cli.BoolFlag{
+			Name:  "latest",
+			Usage: "only include the most recent meeting for each domain and name",
+		},
+	}
+}
+
+// taskQueryFlags returns the flags used to filter tasks, in addition to those returned by meetingQueryFlags.
+func taskQueryFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "complete",
+			Usage: "show only completed tasks",
+		},
+		&cli.BoolFlag{
+			Name:  "incomplete",
+			Usage: "show only incomplete tasks",
+		},
+		&cli.StringSliceFlag{
+			Name:  "state",
+			Usage: "show only tasks in the named states (eg cancelled)",
+		},
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "the description of the task as a wildcard",
+			Value: "*",
+		},
+		&cli.BoolFlag{
+			Name:  "age",
+			Usage: "show how long tasks have been open, counting from the first meeting in their domain containing the same task",
+		},
+		&cli.StringFlag{
+			Name:  "stale",
+			Usage: "show only incomplete tasks which have been open longer than this (eg 14d), implies --age",
+		},
+	}
+}
 
+// listOptionsFromFlags builds list options from the flags returned by listFlags.
+func listOptionsFromFlags(ctx *cli.Context) (meetup.ListOptions, error) {
+	keys, err := meetup.ParseSortKeys(ctx.String("sort"))
 	if err != nil {
-		return err
+		return meetup.ListOptions{}, err
 	}
 
-	for _, meeting := range meetings {
-		fmt.Println(meeting)
+	opts := meetup.ListOptions{
+		Sort:   keys,
+		Limit:  ctx.Int("limit"),
+		Offset: ctx.Int("offset"),
+		Latest: ctx.Bool("latest"),
 	}
 
-	return nil
+	return opts, opts.Validate()
 }
 
-func MeetingRemove(ctx *cli.Context) error {
-	if ctx.NArg() < 3 {
-		return fmt.Errorf("missing required arguments")
+// queryFlag returns the --query flag, described by usage.
+func queryFlag(usage string) cli.Flag {
+	return &cli.StringFlag{
+		Name:    "query",
+		Aliases: []string{"q"},
+		Usage:   usage,
 	}
+}
 
-	if ctx.NArg() > 3 {
-		return fmt.Errorf("too many arguments")
+// queryFromFlags parses the --query flag with parse, pointing out where in the query any syntax error was found.
+func queryFromFlags(ctx *cli.Context, parse func(string) (*meetup.Query, error)) (*meetup.Query, error) {
+	raw := ctx.String("query")
+	if raw == "" {
+		return nil, nil
 	}
 
-	manager, err := GetManager()
-	if err != nil {
-		return err
+	query, err := parse(raw)
+
+	var syntaxErr *meetup.QuerySyntaxError
+	if errors.As(err, &syntaxErr) {
+		return nil, fmt.Errorf("%w\n  %s\n  %s^", err, raw, strings.Repeat(" ", syntaxErr.Pos))
 	}
 
-	err = manager.RemoveMeeting(meetup.Meeting{
-		Name:   ctx.Args().Get(2),
-		Domain: ctx.Args().Get(1),
-		Date:   ctx.Args().Get(0),
-	})
-	if err != nil {
-		return err
+	return query, err
+}
+
+// viewFlag returns the --view flag.
+func viewFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "view",
+		Usage: "start from a saved view, which the other flags narrow further",
 	}
+}
 
-	return nil
+// formatFlag returns the --format flag.
+func formatFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "format",
+		Usage: "output format, one of text or json",
+		Value: string(meetup.FormatText),
+	}
 }
 
-func UpdateGroupBy(ctx *cli.Context) error {
-	if ctx.NArg() > 1 {
-		return fmt.Errorf("too many arguments")
+// treeFlag returns the --tree flag.
+func treeFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "tree",
+		Usage: "show subtasks nested under their parent tasks",
 	}
+}
 
-	if ctx.NArg() < 1 {
-		return fmt.Errorf("missing required arguments")
+// viewFromFlags returns the view named by the --view flag, or nil if no view was given.
+func viewFromFlags(ctx *cli.Context, kind meetup.ViewKind) (*meetup.View, error) {
+	name := ctx.String("view")
+	if name == "" {
+		return nil, nil
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := manager.View(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if view.Kind != kind {
+		return nil, fmt.Errorf("view '%s' lists %s, not %s", name, view.Kind, kind)
 	}
 
-	manager, err := GetManager()
+	return &view, nil
+}
+
+// applyViewOptions replaces the list options and output format with the view's, except for those given as flags.
+func applyViewOptions(ctx *cli.Context, view meetup.View, opts *meetup.ListOptions, format *meetup.OutputFormat) error {
+	viewOpts, err := view.ListOptions()
 	if err != nil {
 		return err
 	}
 
-	newGs := meetup.GroupStrategy(ctx.Args().First())
+	if !ctx.IsSet("sort") {
+		opts.Sort = viewOpts.Sort
+	}
 
-	switch newGs {
-	case meetup.GroupByDomain, meetup.GroupByDate:
-	default:
-		return fmt.Errorf("invalid group by strategy: %s", newGs)
+	if !ctx.IsSet("limit") {
+		opts.Limit = viewOpts.Limit
 	}
 
-	if err := manager.UpdateMeetingGroupBy(newGs); err != nil {
-		return fmt.Errorf("could not update group by strategy: %w", err)
+	if !ctx.IsSet("latest") {
+		opts.Latest = viewOpts.Latest
+	}
+
+	if !ctx.IsSet("format") && view.Format != "" {
+		*format = view.Format
 	}
 
 	return nil
 }
 
-func TemplateAdd(ctx *cli.Context) error {
-	templates := ctx.Args().Slice()
-	if len(templates) == 0 {
-		return fmt.Errorf("expected template paths, but found none")
+type meetingOutput struct {
+	Profile string `json:"profile,omitempty"`
+	meetup.Meeting
+}
+
+type taskOutput struct {
+	Profile string `json:"profile,omitempty"`
+	meetup.Task
+}
+
+type taskTreeOutput struct {
+	Profile string `json:"profile,omitempty"`
+	*meetup.TaskNode
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("could not write json: %w", err)
 	}
 
-	manager, err := GetManager()
+	return nil
+}
+
+// parseDate parses an absolute or relative date (see meetup.ParseDate) into DateFormat.
+func parseDate(raw string) (string, error) {
+	date, err := meetup.ParseDate(raw, time.Now())
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	for _, template := range templates {
-		if err := manager.AddTemplate(template); err != nil {
-			return fmt.Errorf("could not add template: %w", err)
+	return date.Format(meetup.DateFormat), nil
+}
+
+// dateRangeFromFlags returns the date range selected by the --since, --until, and --this-week style flags.
+func dateRangeFromFlags(ctx *cli.Context) (time.Time, time.Time, error) {
+	now := time.Now()
+
+	var since, until time.Time
+	var selected []string
+
+	for _, flag := range meetup.DateRanges {
+		if ctx.Bool(flag) {
+			var err error
+			if since, until, err = meetup.ParseDateRange(flag, now); err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+
+			selected = append(selected, "--"+flag)
 		}
 	}
 
-	return nil
+	if raw := ctx.String("since"); raw != "" {
+		date, err := meetup.ParseDate(raw, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --since: %w", err)
+		}
+
+		since = date
+		selected = append(selected, "--since")
+	}
+
+	if raw := ctx.String("until"); raw != "" {
+		date, err := meetup.ParseDate(raw, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --until: %w", err)
+		}
+
+		until = date
+		selected = append(selected, "--until")
+	}
+
+	if len(selected) > 1 && !(len(selected) == 2 && selected[0] == "--since" && selected[1] == "--until") {
+		return time.Time{}, time.Time{}, fmt.Errorf("cannot combine %s", strings.Join(selected, ", "))
+	}
+
+	return since, until, nil
 }
 
-func TemplateList(ctx *cli.Context) error {
-	manager, err := GetManager()
+// meetingQueryFromFlags builds a meeting query from the flags returned by meetingQueryFlags.
+func meetingQueryFromFlags(ctx *cli.Context) (meetup.MeetingQuery, error) {
+	var query meetup.MeetingQuery
+	var err error
+
+	if query.Name, err = glob.Compile(ctx.String("name")); err != nil {
+		return meetup.MeetingQuery{}, fmt.Errorf("invalid name pattern: %w", err)
+	}
+
+	if query.Domain, err = glob.Compile(ctx.String("domain")); err != nil {
+		return meetup.MeetingQuery{}, fmt.Errorf("invalid domain pattern: %w", err)
+	}
+
+	if query.Date, err = glob.Compile(ctx.String("date")); err != nil {
+		return meetup.MeetingQuery{}, fmt.Errorf("invalid date pattern: %w", err)
+	}
+
+	if query.Since, query.Until, err = dateRangeFromFlags(ctx); err != nil {
+		return meetup.MeetingQuery{}, err
+	}
+
+	query.IncludeArchived = ctx.Bool("include-archived")
+
+	return query, nil
+}
+
+// selectMeetings lists the meetings matching the query flags. With --expired only meetings older than their domain's
+// retention are selected.
+func selectMeetings(ctx *cli.Context, manager *meetup.Manager) ([]meetup.Meeting, error) {
+	query, err := meetingQueryFromFlags(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	templates, err := manager.ListTemplates()
+	if !ctx.Bool("expired") {
+		return manager.ListMeetings(query)
+	}
+
+	expired, err := manager.ExpiredMeetings(time.Now())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, template := range templates {
-		fmt.Println(template)
+	return slices.DeleteFunc(expired, func(meeting meetup.Meeting) bool {
+		return !query.Match(meeting)
+	}), nil
+}
+
+// confirm asks the user to confirm an action, unless --yes was given.
+func confirm(ctx *cli.Context, prompt string) (bool, error) {
+	if ctx.Bool("yes") {
+		return true, nil
 	}
 
-	return nil
+	fmt.Printf("%s [y/N] ", prompt)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("could not read confirmation: %w", err)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes", nil
 }
 
-func TemplateRemove(ctx *cli.Context) error {
-	templates := ctx.Args().Slice()
-	if len(templates) == 0 {
-		return fmt.Errorf("expected template names, but found none")
+func MeetingOpen(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
 	}
 
-	manager, err := GetManager()
+	meeting, err := parseMeeting(ctx.String("date"), ctx.Args().First())
 	if err != nil {
 		return err
 	}
 
-	if err := manager.RemoveTemplate(templates...); err != nil {
+	meeting.Template = ctx.String("template")
+
+	if raw := ctx.String("time"); raw != "" {
+		if meeting.Time, err = meetup.ParseTime(raw); err != nil {
+			return err
+		}
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	if ctx.Bool("new") {
+		meeting = manager.NextSequence(meeting)
+	}
+
+	return manager.OpenMeeting(meeting)
 }
 
-func TaskList(ctx *cli.Context) error {
-	manager, err := GetManager()
+func MeetingList(ctx *cli.Context) error {
+	view, err := viewFromFlags(ctx, meetup.ViewMeetings)
 	if err != nil {
 		return err
 	}
 
-	var complete *bool
+	return listMeetings(ctx, view)
+}
 
-	switch {
-	case ctx.Bool("complete"):
-		complete = new(bool)
-		*complete = true
-	case ctx.Bool("incomplete"):
-		complete = new(bool)
-		*complete = false
+// listMeetings lists the meetings matching the flags, narrowed by the view if it is not nil.
+func listMeetings(ctx *cli.Context, view *meetup.View) error {
+	managers, err := GetManagers(ctx)
+	if err != nil {
+		return err
 	}
 
-	query := meetup.TaskQuery{
-		Meeting: meetup.MeetingQuery{
-			Name:   glob.MustCompile(ctx.String("name")),
-			Domain: glob.MustCompile(ctx.String("domain")),
-			Date:   glob.MustCompile(ctx.String("date")),
-		},
-		Complete:    complete,
-		Description: glob.MustCompile(ctx.String("description")),
+	query, err := meetingQueryFromFlags(ctx)
+	if err != nil {
+		return err
 	}
 
-	tasks, err := manager.Tasks(query)
+	if query.Query, err = queryFromFlags(ctx, meetup.ParseMeetingQuery); err != nil {
+		return err
+	}
+
+	opts, err := listOptionsFromFlags(ctx)
 	if err != nil {
-		return nil
+		return err
+	}
+
+	format := meetup.OutputFormat(ctx.String("format"))
+
+	if view != nil {
+		if query, err = view.NarrowMeetings(query, time.Now()); err != nil {
+			return err
+		}
+
+		if err := applyViewOptions(ctx, *view, &opts, &format); err != nil {
+			return err
+		}
 	}
 
-	for _, task := range tasks {
-		checkBox := "❌"
-		if task.Complete {
-			checkBox = "✅"
+	if err := format.Validate(); err != nil {
+		return err
+	}
+
+	output := []meetingOutput{}
+
+	for _, manager := range managers {
+		meetings, err := manager.ListMeetingsWithOptions(query, opts)
+		if err != nil {
+			return err
 		}
 
-		fmt.Printf("[%s] %s %s\n", task.Meeting, checkBox, task.Description)
+		for _, meeting := range meetings {
+			switch {
+			case format == meetup.FormatJSON:
+				output = append(output, meetingOutput{Profile: manager.Profile, Meeting: meeting})
+			case meeting.Archived:
+				fmt.Printf("%s%s (archived)\n", profilePrefix(manager.Profile), meeting)
+			default:
+				fmt.Printf("%s%s\n", profilePrefix(manager.Profile), meeting)
+			}
+		}
+	}
+
+	if format == meetup.FormatJSON {
+		return printJSON(output)
 	}
 
 	return nil
 }
 
-// todo: add completion
-func Run(args []string) error {
-	// todo: duplicated meeting query flags
-	app := cli.App{
-		Name:    "meetup",
-		Version: Version,
+func MeetingRemove(ctx *cli.Context) error {
+	if ctx.NArg() < 3 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	if ctx.NArg() > 3 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	date, err := parseDate(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	meeting := meetup.Meeting{
+		Domain: ctx.Args().Get(1),
+		Date:   date,
+	}
+
+	meeting.Name, meeting.Time, meeting.Sequence = meetup.SplitMeetingName(ctx.Args().Get(2))
+
+	if err := manager.RemoveMeeting(meeting); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func MeetingMove(ctx *cli.Context) error {
+	if ctx.NArg() > 3 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 2 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	from, err := parseMeeting(ctx.Args().Get(0), ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	to := from
+
+	if ctx.NArg() == 3 {
+		if to, err = parseMeeting(from.Date, ctx.Args().Get(2)); err != nil {
+			return err
+		}
+	}
+
+	if raw := ctx.String("to-date"); raw != "" {
+		if to.Date, err = parseDate(raw); err != nil {
+			return err
+		}
+	}
+
+	if raw := ctx.String("to-time"); raw != "" {
+		if to.Time, err = meetup.ParseTime(raw); err != nil {
+			return err
+		}
+	}
+
+	if to == from {
+		return fmt.Errorf("meeting would not be moved, provide a new name, --to-date, or --to-time")
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	return manager.MoveMeetingWithOptions(from, to, meetup.MoveOptions{
+		Overwrite: ctx.Bool("force"),
+	})
+}
+
+// bulkAction applies fn to every meeting matching the query flags once the user has confirmed. With --dry-run the
+// affected meetings are only listed, each described by describe.
+func bulkAction(ctx *cli.Context, verb string, describe func(meetup.Meeting) string, fn func(*meetup.Manager, meetup.Meeting) error) error {
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	meetings, err := selectMeetings(ctx, &manager)
+	if err != nil {
+		return err
+	}
+
+	return applyBulk(ctx, &manager, meetings, verb, describe, fn)
+}
+
+// applyBulk applies fn to each of the given meetings once the user has confirmed, see bulkAction.
+func applyBulk(ctx *cli.Context, manager *meetup.Manager, meetings []meetup.Meeting, verb string, describe func(meetup.Meeting) string, fn func(*meetup.Manager, meetup.Meeting) error) error {
+	if ok, err := confirmBulk(ctx, meetings, verb, describe); !ok {
+		return err
+	}
+
+	for _, meeting := range meetings {
+		if err := fn(manager, meeting); err != nil {
+			return fmt.Errorf("could not %s '%s': %w", verb, meeting, err)
+		}
+	}
+
+	return nil
+}
+
+// confirmBulk lists the meetings and asks the user to confirm the bulk action, reporting whether it should go ahead.
+// Nothing is done for a dry run or when no meetings matched.
+func confirmBulk(ctx *cli.Context, meetings []meetup.Meeting, verb string, describe func(meetup.Meeting) string) (bool, error) {
+	if len(meetings) == 0 {
+		fmt.Println("no meetings matched")
+		return false, nil
+	}
+
+	for _, meeting := range meetings {
+		fmt.Println(describe(meeting))
+	}
+
+	if ctx.Bool("dry-run") {
+		return false, nil
+	}
+
+	if ok, err := confirm(ctx, fmt.Sprintf("%s %d meeting(s)?", verb, len(meetings))); err != nil {
+		return false, err
+	} else if !ok {
+		return false, fmt.Errorf("aborted")
+	}
+
+	return true, nil
+}
+
+// bulkMove moves every meeting matching the query flags using target to determine the new meeting. All targets are
+// checked for conflicts before any meeting is moved, and the meetings are only selected once.
+func bulkMove(ctx *cli.Context, verb string, target func(meetup.Meeting) meetup.Meeting) error {
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	meetings, err := selectMeetings(ctx, &manager)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]meetup.Meeting{}
+
+	for _, meeting := range meetings {
+		to := target(meeting)
+
+		if other, found := seen[to.String()]; found {
+			return fmt.Errorf("both '%s' and '%s' would be moved to '%s'", other, meeting, to)
+		}
+
+		seen[to.String()] = meeting
+
+		if to != meeting && manager.MeetingExists(to) {
+			return fmt.Errorf("cannot move '%s': '%s' already exists", meeting, to)
+		}
+	}
+
+	describe := func(meeting meetup.Meeting) string {
+		return fmt.Sprintf("%s -> %s", meeting, target(meeting))
+	}
+
+	// only the meetings which were checked are moved, even if the query would now match others
+	return applyBulk(ctx, &manager, meetings, verb, describe, func(manager *meetup.Manager, meeting meetup.Meeting) error {
+		if to := target(meeting); to != meeting {
+			return manager.MoveMeeting(meeting, to)
+		}
+
+		return nil
+	})
+}
+
+func BulkRemove(ctx *cli.Context) error {
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	meetings, err := selectMeetings(ctx, &manager)
+	if err != nil {
+		return err
+	}
+
+	if ok, err := confirmBulk(ctx, meetings, "remove", meetup.Meeting.String); !ok {
+		return err
+	}
+
+	return manager.RemoveMeetings(meetings)
+}
+
+func BulkMove(ctx *cli.Context) error {
+	domain := ctx.String("to")
+	if err := meetup.ValidateDomain(domain); err != nil {
+		return err
+	}
+
+	return bulkMove(ctx, "move", func(meeting meetup.Meeting) meetup.Meeting {
+		meeting.Domain = domain
+		return meeting
+	})
+}
+
+func BulkRedate(ctx *cli.Context) error {
+	date, err := parseDate(ctx.String("to"))
+	if err != nil {
+		return err
+	}
+
+	return bulkMove(ctx, "re-date", func(meeting meetup.Meeting) meetup.Meeting {
+		meeting.Date = date
+		return meeting
+	})
+}
+
+func BulkArchive(ctx *cli.Context) error {
+	return bulkAction(ctx, "archive", meetup.Meeting.String, (*meetup.Manager).ArchiveMeeting)
+}
+
+func ViewSave(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	if ctx.IsSet("offset") {
+		return fmt.Errorf("views cannot save an offset")
+	}
+
+	view := meetup.View{
+		Kind:            meetup.ViewMeetings,
+		Range:           ctx.String("range"),
+		Sort:            ctx.String("sort"),
+		Limit:           ctx.Int("limit"),
+		Latest:          ctx.Bool("latest"),
+		IncludeArchived: ctx.Bool("include-archived"),
+		Format:          meetup.OutputFormat(ctx.String("format")),
+	}
+
+	parse := meetup.ParseMeetingQuery
+
+	if ctx.Bool("tasks") {
+		view.Kind = meetup.ViewTasks
+		parse = meetup.ParseTaskQuery
+	}
+
+	// parse the query here so that syntax errors point out where they are
+	if _, err := queryFromFlags(ctx, parse); err != nil {
+		return err
+	}
+
+	var err error
+	if view.Query, err = viewQueryFromFlags(ctx, view.Kind == meetup.ViewTasks); err != nil {
+		return err
+	}
+
+	if view.Query != "" {
+		if _, err := parse(view.Query); err != nil {
+			return fmt.Errorf("invalid filters: %w", err)
+		}
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range ctx.StringSlice("state") {
+		if !manager.HasTaskState(name) {
+			return fmt.Errorf("unknown task state '%s'", name)
+		}
+	}
+
+	return manager.SaveView(ctx.Args().First(), view)
+}
+
+// viewQueryFromFlags returns the --query given to view save combined with the equivalent query terms of the filter
+// flags returned by viewFilterFlags, so that the filters are stored in the view's query.
+func viewQueryFromFlags(ctx *cli.Context, tasks bool) (string, error) {
+	if !tasks {
+		for _, flag := range []string{"complete", "incomplete", "state", "description"} {
+			if ctx.IsSet(flag) {
+				return "", fmt.Errorf("--%s requires --tasks", flag)
+			}
+		}
+	}
+
+	if err := exclusiveFlags(ctx, "complete", "incomplete"); err != nil {
+		return "", err
+	}
+
+	var terms []string
+
+	term := func(flag string, field string, op string) error {
+		value := ctx.String(flag)
+
+		if !ctx.IsSet(flag) || value == "*" {
+			return nil
+		}
+
+		if strings.Contains(value, `"`) {
+			return fmt.Errorf("--%s cannot contain '\"'", flag)
+		}
+
+		if value == "" || strings.ContainsFunc(value, func(r rune) bool { return unicode.IsSpace(r) || r == '(' || r == ')' }) {
+			value = `"` + value + `"`
+		}
+
+		terms = append(terms, field+op+value)
+
+		return nil
+	}
+
+	for _, filter := range []struct{ flag, field, op string }{
+		{"name", "name", ":"},
+		{"domain", "domain", ":"},
+		{"date", "date", ":"},
+		{"since", "date", ">="},
+		{"until", "date", "<="},
+		{"description", "desc", ":"},
+	} {
+		if err := term(filter.flag, filter.field, filter.op); err != nil {
+			return "", err
+		}
+	}
+
+	switch {
+	case ctx.Bool("complete"):
+		terms = append(terms, "is:complete")
+	case ctx.Bool("incomplete"):
+		terms = append(terms, "is:incomplete")
+	}
+
+	if states := ctx.StringSlice("state"); len(states) > 0 {
+		var alternatives []string
+		for _, state := range states {
+			alternatives = append(alternatives, "state="+state)
+		}
+
+		terms = append(terms, "("+strings.Join(alternatives, " OR ")+")")
+	}
+
+	raw := ctx.String("query")
+
+	if len(terms) == 0 {
+		return raw, nil
+	}
+
+	if raw != "" {
+		terms = append([]string{"(" + raw + ")"}, terms...)
+	}
+
+	return strings.Join(terms, " AND "), nil
+}
+
+// describeView summarizes the view's settings for view list.
+func describeView(view meetup.View) string {
+	var parts []string
+
+	if view.Query != "" {
+		parts = append(parts, fmt.Sprintf("query '%s'", view.Query))
+	}
+
+	if view.Range != "" {
+		parts = append(parts, "range "+view.Range)
+	}
+
+	if view.Sort != "" {
+		parts = append(parts, "sort "+view.Sort)
+	}
+
+	if view.Limit != 0 {
+		parts = append(parts, fmt.Sprintf("limit %d", view.Limit))
+	}
+
+	if view.Latest {
+		parts = append(parts, "latest")
+	}
+
+	if view.IncludeArchived {
+		parts = append(parts, "include archived")
+	}
+
+	if view.Format != "" {
+		parts = append(parts, "format "+string(view.Format))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func ViewList(ctx *cli.Context) error {
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range manager.Views() {
+		view, err := manager.View(name)
+		if err != nil {
+			return err
+		}
+
+		if description := describeView(view); description != "" {
+			fmt.Printf("%s (%s): %s\n", name, view.Kind, description)
+		} else {
+			fmt.Printf("%s (%s)\n", name, view.Kind)
+		}
+	}
+
+	return nil
+}
+
+func ViewRun(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	view, err := manager.View(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	if view.Kind == meetup.ViewTasks {
+		return listTasks(ctx, &view)
+	}
+
+	for _, flag := range []string{"complete", "incomplete", "state", "description", "age", "stale", "tree"} {
+		if ctx.IsSet(flag) {
+			return fmt.Errorf("--%s only applies to task views", flag)
+		}
+	}
+
+	return listMeetings(ctx, &view)
+}
+
+func ViewRemove(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range ctx.Args().Slice() {
+		if err := manager.RemoveView(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func ArchiveList(ctx *cli.Context) error {
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	query, err := meetingQueryFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	meetings, err := manager.ListArchived(query)
+	if err != nil {
+		return err
+	}
+
+	for _, meeting := range meetings {
+		fmt.Println(meeting)
+	}
+
+	return nil
+}
+
+func ArchiveRestore(ctx *cli.Context) error {
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	query, err := meetingQueryFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	meetings, err := manager.ListArchived(query)
+	if err != nil {
+		return err
+	}
+
+	return applyBulk(ctx, &manager, meetings, "restore", meetup.Meeting.String, (*meetup.Manager).RestoreArchived)
+}
+
+func BulkApplyTemplate(ctx *cli.Context) error {
+	template := ctx.String("template")
+
+	return bulkAction(ctx, "re-apply template to", meetup.Meeting.String, func(manager *meetup.Manager, meeting meetup.Meeting) error {
+		return manager.ApplyTemplate(meeting, template)
+	})
+}
+
+// bulkFlags returns the flags shared by all bulk subcommands along with any extra flags.
+func bulkFlags(extra ...cli.Flag) []cli.Flag {
+	return append(append(meetingQueryFlags(),
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "list the affected meetings without changing anything",
+		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "do not ask for confirmation",
+		},
+	), extra...)
+}
+
+// expiredFlag selects only meetings older than their domain's retention, see selectMeetings.
+func expiredFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "expired",
+		Usage: "only match meetings older than the retention configured for their domain",
+	}
+}
+
+func UpdateGroupBy(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	newGs := meetup.GroupStrategy(ctx.Args().First())
+
+	switch newGs {
+	case meetup.GroupByDomain, meetup.GroupByDate:
+	default:
+		return fmt.Errorf("invalid group by strategy: %s", newGs)
+	}
+
+	if err := manager.UpdateMeetingGroupBy(newGs); err != nil {
+		return fmt.Errorf("could not update group by strategy: %w", err)
+	}
+
+	return nil
+}
+
+func TemplateAdd(ctx *cli.Context) error {
+	templates := ctx.Args().Slice()
+	if len(templates) == 0 {
+		return fmt.Errorf("expected template paths, but found none")
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, template := range templates {
+		if err := manager.AddTemplate(template); err != nil {
+			return fmt.Errorf("could not add template: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func TemplateList(ctx *cli.Context) error {
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	templates, err := manager.ListTemplates()
+	if err != nil {
+		return err
+	}
+
+	for _, template := range templates {
+		fmt.Println(template)
+	}
+
+	return nil
+}
+
+func TemplateWhich(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	meeting, err := parseMeeting(ctx.String("date"), ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	matches, err := manager.MatchingTemplateRules(meeting)
+	if err != nil {
+		return err
+	}
+
+	if len(matches) == 0 {
+		fmt.Printf("no template rule applies to '%s'\n", meeting)
+		return nil
+	}
+
+	rules := manager.TemplateRules()
+
+	for i, match := range matches {
+		rule := rules[match]
+
+		status := "overridden"
+		if i == 0 {
+			status = "selected"
+		}
+
+		fmt.Printf("[%s] rule %d: %s\n", status, match, rule)
+
+		if !manager.TemplateExists(rule.Template) {
+			fmt.Printf("  warning: template '%s' does not exist\n", rule.Template)
+		}
+	}
+
+	return nil
+}
+
+func TemplateRemove(ctx *cli.Context) error {
+	templates := ctx.Args().Slice()
+	if len(templates) == 0 {
+		return fmt.Errorf("expected template names, but found none")
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := manager.RemoveTemplate(templates...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func TaskList(ctx *cli.Context) error {
+	view, err := viewFromFlags(ctx, meetup.ViewTasks)
+	if err != nil {
+		return err
+	}
+
+	return listTasks(ctx, view)
+}
+
+// exclusiveFlags returns an error if more than one of the given flags is set.
+func exclusiveFlags(ctx *cli.Context, flags ...string) error {
+	var set []string
+
+	for _, flag := range flags {
+		if ctx.IsSet(flag) {
+			set = append(set, "--"+flag)
+		}
+	}
+
+	if len(set) > 1 {
+		return fmt.Errorf("%s cannot be used together", strings.Join(set, " and "))
+	}
+
+	return nil
+}
+
+// taskQueryFromFlags builds a task query from the flags returned by meetingQueryFlags and taskQueryFlags, and the
+// --query flag.
+func taskQueryFromFlags(ctx *cli.Context) (meetup.TaskQuery, error) {
+	if err := exclusiveFlags(ctx, "complete", "incomplete"); err != nil {
+		return meetup.TaskQuery{}, err
+	}
+
+	var complete *bool
+
+	switch {
+	case ctx.Bool("complete"):
+		complete = new(bool)
+		*complete = true
+	case ctx.Bool("incomplete"):
+		complete = new(bool)
+		*complete = false
+	}
+
+	meetingQuery, err := meetingQueryFromFlags(ctx)
+	if err != nil {
+		return meetup.TaskQuery{}, err
+	}
+
+	description, err := glob.Compile(ctx.String("description"))
+	if err != nil {
+		return meetup.TaskQuery{}, fmt.Errorf("invalid description pattern: %w", err)
+	}
+
+	query := meetup.TaskQuery{
+		Meeting:     meetingQuery,
+		Complete:    complete,
+		States:      ctx.StringSlice("state"),
+		Description: description,
+		TrackAge:    ctx.Bool("age"),
+	}
+
+	if raw := ctx.String("stale"); raw != "" {
+		if query.Stale, err = meetup.ParseDuration(raw); err != nil {
+			return meetup.TaskQuery{}, fmt.Errorf("invalid --stale: %w", err)
+		}
+
+		if query.Stale <= 0 {
+			return meetup.TaskQuery{}, fmt.Errorf("invalid --stale: must be positive")
+		}
+	}
+
+	if query.Query, err = queryFromFlags(ctx, meetup.ParseTaskQuery); err != nil {
+		return meetup.TaskQuery{}, err
+	}
+
+	return query, nil
+}
+
+// checkTaskStates returns an error for the states in the query which are not used by any of the managers.
+func checkTaskStates(query meetup.TaskQuery, managers ...meetup.Manager) error {
+	for _, name := range query.States {
+		if !slices.ContainsFunc(managers, func(manager meetup.Manager) bool { return manager.HasTaskState(name) }) {
+			return fmt.Errorf("unknown task state '%s'", name)
+		}
+	}
+
+	return nil
+}
+
+// listTasks lists the tasks matching the flags, narrowed by the view if it is not nil.
+func listTasks(ctx *cli.Context, view *meetup.View) error {
+	managers, err := GetManagers(ctx)
+	if err != nil {
+		return err
+	}
+
+	query, err := taskQueryFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	var configured []meetup.Manager
+	for _, manager := range managers {
+		configured = append(configured, manager.Manager)
+	}
+
+	if err := checkTaskStates(query, configured...); err != nil {
+		return err
+	}
+
+	opts, err := listOptionsFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	format := meetup.OutputFormat(ctx.String("format"))
+
+	if view != nil {
+		if query, err = view.NarrowTasks(query, time.Now()); err != nil {
+			return err
+		}
+
+		if err := applyViewOptions(ctx, *view, &opts, &format); err != nil {
+			return err
+		}
+	}
+
+	if err := format.Validate(); err != nil {
+		return err
+	}
+
+	output := []taskOutput{}
+	treeOutput := []taskTreeOutput{}
+
+	for _, manager := range managers {
+		tasks, err := manager.TasksWithOptions(query, opts)
+		if err != nil {
+			return err
+		}
+
+		if ctx.Bool("tree") {
+			roots := meetup.TaskTree(tasks)
+
+			if format == meetup.FormatJSON {
+				for _, root := range roots {
+					treeOutput = append(treeOutput, taskTreeOutput{Profile: manager.Profile, TaskNode: root})
+				}
+			} else {
+				printTaskTree(manager.Profile, roots)
+			}
+
+			continue
+		}
+
+		for _, task := range tasks {
+			if format == meetup.FormatJSON {
+				output = append(output, taskOutput{Profile: manager.Profile, Task: task})
+				continue
+			}
+
+			fmt.Printf("%s[%s] %s %s\n", profilePrefix(manager.Profile), task.Meeting, checkBox(task), describeTask(task))
+		}
+	}
+
+	switch {
+	case format == meetup.FormatJSON && ctx.Bool("tree"):
+		return printJSON(treeOutput)
+	case format == meetup.FormatJSON:
+		return printJSON(output)
+	default:
+		return nil
+	}
+}
+
+func checkBox(task meetup.Task) string {
+	return task.State.String()
+}
+
+// describeTask returns the task's description, along with how long it has been open if its age is tracked.
+func describeTask(task meetup.Task) string {
+	if task.FirstSeen == "" || task.State.Done {
+		return task.Description
+	}
+
+	if task.Age == 0 {
+		return task.Description + " (new today)"
+	}
+
+	return fmt.Sprintf("%s (open %s, since %s)", task.Description, task.Age, task.FirstSeen)
+}
+
+// printTaskTree prints each root task and its subtasks indented under a header for its meeting.
+func printTaskTree(profile string, roots []*meetup.TaskNode) {
+	var printNode func(node *meetup.TaskNode, depth int)
+	printNode = func(node *meetup.TaskNode, depth int) {
+		fmt.Printf("%s%s %s\n", strings.Repeat("  ", depth), checkBox(node.Task), describeTask(node.Task))
+
+		for _, child := range node.Children {
+			printNode(child, depth+1)
+		}
+	}
+
+	for i, root := range roots {
+		if i == 0 || roots[i-1].Meeting != root.Meeting {
+			fmt.Printf("%s[%s]\n", profilePrefix(profile), root.Meeting)
+		}
+
+		printNode(root, 1)
+	}
+}
+
+func ProfileList(ctx *cli.Context) error {
+	file, _, err := LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	active := file.ActiveProfile(ctx.String("profile"))
+
+	for _, name := range file.ProfileNames() {
+		config, err := file.ProfileConfig(name)
+		if err != nil {
+			return err
+		}
+
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+
+		fmt.Printf("%s %s\t%s\n", marker, name, config.RootDir)
+	}
+
+	return nil
+}
+
+func ProfileAdd(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	name := ctx.Args().First()
+	if name == meetup.DefaultProfile {
+		return fmt.Errorf("cannot add reserved profile '%s'", name)
+	}
+
+	file, configPath, err := LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	if _, found := file.Profiles[name]; found {
+		return fmt.Errorf("profile '%s' already exists", name)
+	}
+
+	profile := meetup.Config{
+		RootDir: ctx.String("root"),
+		Editor:  strings.Fields(ctx.String("editor")),
+		DefaultMetadata: meetup.Metadata{
+			GroupBy: meetup.GroupStrategy(ctx.String("group-by")),
+		},
+	}
+
+	switch profile.DefaultMetadata.GroupBy {
+	case "", meetup.GroupByDomain, meetup.GroupByDate:
+	default:
+		return fmt.Errorf("invalid group by strategy: %s", profile.DefaultMetadata.GroupBy)
+	}
+
+	if file.Profiles == nil {
+		file.Profiles = map[string]meetup.Config{}
+	}
+
+	file.Profiles[name] = profile
+
+	return file.Save(configPath)
+}
+
+func ProfileUse(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	name := ctx.Args().First()
+
+	file, configPath, err := LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.ProfileConfig(name); err != nil {
+		return err
+	}
+
+	file.Profile = name
+	if name == meetup.DefaultProfile {
+		file.Profile = ""
+	}
+
+	return file.Save(configPath)
+}
+
+func ConfigGet(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	key := ctx.Args().First()
+
+	var value string
+
+	if metadataKey, found := strings.CutPrefix(key, "metadata."); found {
+		manager, err := GetManager(ctx)
+		if err != nil {
+			return err
+		}
+
+		if value, err = meetup.GetValue(manager.Metadata(), metadataKey); err != nil {
+			return err
+		}
+	} else {
+		config, err := LoadManagerConfig(ctx)
+		if err != nil {
+			return err
+		}
+
+		if value, err = meetup.GetValue(config, key); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println(value)
+
+	return nil
+}
+
+func ConfigSet(ctx *cli.Context) error {
+	if ctx.NArg() > 2 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 2 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	key, value := ctx.Args().Get(0), ctx.Args().Get(1)
+
+	if metadataKey, found := strings.CutPrefix(key, "metadata."); found {
+		manager, err := GetManager(ctx)
+		if err != nil {
+			return err
+		}
+
+		return manager.SetMetadataValue(metadataKey, value)
+	}
+
+	file, configPath, err := LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	return meetup.SetConfigValue(configPath, file.ActiveProfile(ctx.String("profile")), key, value)
+}
+
+func ConfigUnset(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	key := ctx.Args().First()
+
+	if metadataKey, found := strings.CutPrefix(key, "metadata."); found {
+		manager, err := GetManager(ctx)
+		if err != nil {
+			return err
+		}
+
+		return manager.UnsetMetadataValue(metadataKey)
+	}
+
+	file, configPath, err := LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	return meetup.UnsetConfigValue(configPath, file.ActiveProfile(ctx.String("profile")), key)
+}
+
+func ConfigShow(ctx *cli.Context) error {
+	effective, err := LoadEffectiveConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	manager, err := meetup.NewManager(effective.Config)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("# profile: %s\n", effective.Profile)
+
+	for _, key := range []string{"root_dir", "editor", "parallelism", "default_metadata.group_by", "default_metadata.template_rules", "default_metadata.domains", "default_metadata.trash_retention", "default_metadata.archive_format", "default_metadata.views", "default_metadata.task_states", "default_metadata.rollups"} {
+		source, found := effective.Sources[key]
+		if !found {
+			continue
+		}
+
+		value, err := meetup.GetValue(effective.Config, key)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s: %s # %s\n", key, value, source)
+	}
+
+	metadataFile := map[string]any{}
+	if data, err := os.ReadFile(manager.MetadataPath()); err == nil {
+		if err := yaml.Unmarshal(data, &metadataFile); err != nil {
+			return fmt.Errorf("could not parse metadata file: %w", err)
+		}
+	}
+
+	for _, key := range []string{"group_by", "template_rules", "domains", "trash_retention", "archive_format", "views", "task_states", "rollups"} {
+		value, err := meetup.GetValue(manager.Metadata(), key)
+		if err != nil {
+			continue
+		}
+
+		source := "default_metadata"
+		if _, found := metadataFile[key]; found {
+			source = meetup.MetadataFilename
+		}
+
+		fmt.Printf("metadata.%s: %s # %s\n", key, value, source)
+	}
+
+	return nil
+}
+
+func ConfigPath(ctx *cli.Context) error {
+	if ctx.Bool("metadata") {
+		manager, err := GetManager(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(manager.MetadataPath())
+
+		return nil
+	}
+
+	_, configPath, err := LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(configPath)
+
+	return nil
+}
+
+func ConfigEdit(ctx *cli.Context) error {
+	config, err := LoadManagerConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	manager, err := meetup.NewManager(config)
+	if err != nil {
+		return err
+	}
+
+	target := manager.MetadataPath()
+
+	if !ctx.Bool("metadata") {
+		if _, target, err = LoadConfigFile(); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(path.Dir(target), 0755); err != nil {
+		return fmt.Errorf("could not create directory: %w", err)
+	}
+
+	cmd := exec.Command(config.Editor[0], append(config.Editor[1:], target)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("could not open editor: %w", err)
+	}
+
+	if ctx.Bool("metadata") {
+		if _, err := meetup.NewManager(config); err != nil {
+			return fmt.Errorf("metadata is no longer valid: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := meetup.ValidateConfigFile(target); err != nil {
+		return fmt.Errorf("config is no longer valid: %w", err)
+	}
+
+	return nil
+}
+
+func Doctor(ctx *cli.Context) error {
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	problems, err := manager.Diagnose()
+	if err != nil {
+		return err
+	}
+
+	if len(problems) == 0 {
+		fmt.Println("no problems found")
+		return nil
+	}
+
+	if !ctx.Bool("fix") {
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+
+		return fmt.Errorf("found %d problem(s), run with --fix to repair them", len(problems))
+	}
+
+	unfixed := 0
+
+	for i, err := range manager.Repair(problems) {
+		if err != nil {
+			unfixed++
+			fmt.Printf("%s\n  could not fix: %s\n", problems[i], err)
+		} else {
+			fmt.Printf("%s\n  fixed\n", problems[i])
+		}
+	}
+
+	if unfixed > 0 {
+		return fmt.Errorf("could not fix %d problem(s)", unfixed)
+	}
+
+	return nil
+}
+
+func TrashList(ctx *cli.Context) error {
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	items, err := manager.ListTrash()
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		fmt.Printf("%s %s %s\n", item.ID, item.RemovedAt.Format(time.DateTime), item.Meeting)
+	}
+
+	return nil
+}
+
+func TrashRestore(ctx *cli.Context) error {
+	ids := ctx.Args().Slice()
+	if len(ids) == 0 {
+		return fmt.Errorf("expected trash ids, but found none")
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		meeting, err := manager.RestoreTrash(id)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("restored %s\n", meeting)
+	}
+
+	return nil
+}
+
+func TrashEmpty(ctx *cli.Context) error {
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	var olderThan meetup.Duration
+
+	if raw := ctx.String("older-than"); raw != "" {
+		if olderThan, err = meetup.ParseDuration(raw); err != nil {
+			return err
+		}
+	}
+
+	deleted, err := manager.EmptyTrash(olderThan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("permanently deleted %d meeting(s)\n", len(deleted))
+
+	return nil
+}
+
+func printDomainNode(node *meetup.DomainNode, prefix string, last bool) {
+	branch, indent := "├── ", "│   "
+	if last {
+		branch, indent = "└── ", "    "
+	}
+
+	fmt.Printf("%s%s%s (%d meeting(s), %s - %s)\n", prefix, branch, node.Name, node.Total, node.FirstDate, node.LastDate)
+
+	for i, child := range node.Children {
+		printDomainNode(child, prefix+indent, i == len(node.Children)-1)
+	}
+}
+
+func DomainList(ctx *cli.Context) error {
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	root, err := manager.DomainTree()
+	if err != nil {
+		return err
+	}
+
+	for _, node := range root.Children {
+		fmt.Printf("%s (%d meeting(s), %s - %s)\n", node.Name, node.Total, node.FirstDate, node.LastDate)
+
+		for i, child := range node.Children {
+			printDomainNode(child, "", i == len(node.Children)-1)
+		}
+	}
+
+	return nil
+}
+
+func domainMove(ctx *cli.Context, fn func(*meetup.Manager, string, string) ([]meetup.Meeting, error)) error {
+	if ctx.NArg() < 2 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	if ctx.NArg() > 2 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	moved, err := fn(&manager, ctx.Args().Get(0), ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("moved %d meeting(s) to '%s'\n", len(moved), ctx.Args().Get(1))
+
+	return nil
+}
+
+func DomainRename(ctx *cli.Context) error {
+	return domainMove(ctx, (*meetup.Manager).RenameDomain)
+}
+
+func DomainMerge(ctx *cli.Context) error {
+	return domainMove(ctx, (*meetup.Manager).MergeDomain)
+}
+
+func DomainRemove(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	domain := ctx.Args().Get(0)
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	meetings, err := manager.DomainMeetings(domain)
+	if err != nil {
+		return err
+	}
+
+	if len(meetings) == 0 {
+		return fmt.Errorf("domain '%s' does not contain any meetings", domain)
+	}
+
+	ok, err := confirm(ctx, fmt.Sprintf("move %d meeting(s) in '%s' to the trash?", len(meetings), domain))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("aborted")
+	}
+
+	removed, err := manager.RemoveDomain(domain, ctx.Bool("recursive"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("moved %d meeting(s) to the trash\n", len(removed))
+
+	return nil
+}
+
+// statsChartWidth is the width of the widest bar in the stats chart.
+const statsChartWidth = 40
+
+func TaskExport(ctx *cli.Context) error {
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	query, err := taskQueryFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := checkTaskStates(query, manager); err != nil {
+		return err
+	}
+
+	tasks, err := manager.Tasks(query)
+	if err != nil {
+		return err
+	}
+
+	switch format := ctx.String("format"); format {
+	case "todotxt":
+		return meetup.WriteTodoTxt(os.Stdout, tasks)
+	case "taskwarrior":
+		return meetup.WriteTaskwarrior(os.Stdout, tasks)
+	default:
+		return fmt.Errorf("invalid format '%s': expected one of todotxt or taskwarrior", format)
+	}
+}
+
+func TaskImport(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	var into *meetup.Meeting
+
+	if ctx.IsSet("into") {
+		meeting, err := parseMeeting(ctx.String("date"), ctx.String("into"))
+		if err != nil {
+			return err
+		}
+
+		into = &meeting
+	}
+
+	var r io.Reader = os.Stdin
+
+	if p := ctx.Args().First(); p != "-" {
+		file, err := os.Open(p)
+		if err != nil {
+			return fmt.Errorf("could not open taskwarrior export: %w", err)
+		}
+		defer file.Close()
+
+		r = file
+	}
+
+	tasks, err := meetup.ReadTaskwarrior(r)
+	if err != nil {
+		return err
+	}
+
+	result, err := manager.ImportTaskwarrior(tasks, into)
+
+	for _, task := range result.Updated {
+		fmt.Printf("updated [%s] %s %s\n", task.Meeting, checkBox(task), task.Description)
+	}
+
+	for _, task := range result.Added {
+		fmt.Printf("added [%s] %s %s\n", task.Meeting, checkBox(task), task.Description)
+	}
+
+	if len(result.Skipped) > 0 {
+		fmt.Printf("skipped %d unchanged task(s)\n", len(result.Skipped))
+	}
+
+	return err
+}
+
+func TaskSync(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	query, err := taskQueryFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := checkTaskStates(query, manager); err != nil {
+		return err
+	}
+
+	sync, err := manager.SyncTodoTxt(ctx.Args().First(), query)
+
+	for _, task := range sync.Completed {
+		fmt.Printf("completed [%s] %s %s\n", task.Meeting, checkBox(task), task.Description)
+	}
+
+	for _, line := range sync.Unmatched {
+		fmt.Printf("kept unmatched line: %s\n", line)
+	}
+
+	return err
+}
+
+func Stats(ctx *cli.Context) error {
+	if ctx.NArg() > 0 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	format := ctx.String("format")
+
+	switch format {
+	case "text", "json", "csv":
+	default:
+		return fmt.Errorf("invalid format '%s': expected one of text, json, or csv", format)
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	meetingQuery, err := meetingQueryFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := meetup.TaskQuery{Meeting: meetingQuery}
+
+	if query.Query, err = queryFromFlags(ctx, meetup.ParseTaskQuery); err != nil {
+		return err
+	}
+
+	stats, err := manager.Stats(query, meetup.StatsOptions{
+		Period: meetup.StatsPeriod(ctx.String("period")),
+		Oldest: ctx.Int("oldest"),
+	})
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		return printJSON(stats)
+	case "csv":
+		return printStatsCSV(stats)
+	default:
+		return printStats(stats, ctx.String("period"))
+	}
+}
+
+// printStatsCSV prints a row for every domain and period with meetings.
+func printStatsCSV(stats meetup.Stats) error {
+	writer := csv.NewWriter(os.Stdout)
+
+	records := [][]string{{"domain", "period", "meetings", "open_tasks", "closed_tasks", "average_age_days"}}
+
+	for _, row := range stats.Rows {
+		records = append(records, []string{
+			row.Domain,
+			row.Period,
+			strconv.Itoa(row.Meetings),
+			strconv.Itoa(row.OpenTasks),
+			strconv.Itoa(row.ClosedTasks),
+			strconv.FormatFloat(row.AverageAge, 'f', 1, 64),
+		})
+	}
+
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("could not write csv: %w", err)
+	}
+
+	return nil
+}
+
+// printStats prints the stats as tables, with a chart of the open and closed tasks in each period.
+func printStats(stats meetup.Stats, period string) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "DOMAIN\tMEETINGS\tOPEN\tCLOSED\tAVG AGE")
+
+	for _, row := range append(stats.Domains, stats.Total) {
+		domain := row.Domain
+		if domain == "" {
+			domain = "total"
+		}
+
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%d\t%.1fd\n", domain, row.Meetings, row.OpenTasks, row.ClosedTasks, row.AverageAge)
+	}
+
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	if len(stats.Periods) > 0 {
+		largest := 0
+		for _, row := range stats.Periods {
+			largest = max(largest, row.OpenTasks+row.ClosedTasks)
+		}
+
+		// scale the bars down only when they would not fit
+		scale := 1.0
+		if largest > statsChartWidth {
+			scale = float64(statsChartWidth) / float64(largest)
+		}
+
+		fmt.Printf("\n%s (█ closed, ░ open)\n", strings.ToUpper(period))
+
+		for _, row := range stats.Periods {
+			closed := int(float64(row.ClosedTasks)*scale + 0.5)
+			open := int(float64(row.OpenTasks)*scale + 0.5)
+
+			fmt.Printf("%-10s %s%s %d closed, %d open\n", row.Period, strings.Repeat("█", closed), strings.Repeat("░", open), row.ClosedTasks, row.OpenTasks)
+		}
+	}
+
+	if len(stats.Oldest) > 0 {
+		fmt.Println("\nOLDEST OPEN TASKS")
+
+		for _, task := range stats.Oldest {
+			fmt.Printf("[%s] %s %s\n", task.Meeting, checkBox(task), task.Description)
+		}
+	}
+
+	return nil
+}
+
+// agendaQueryFromFlags builds a meeting query from the --domain, --name, and --include-archived flags.
+func agendaQueryFromFlags(ctx *cli.Context) (meetup.MeetingQuery, error) {
+	var query meetup.MeetingQuery
+	var err error
+
+	if query.Name, err = glob.Compile(ctx.String("name")); err != nil {
+		return meetup.MeetingQuery{}, fmt.Errorf("invalid name pattern: %w", err)
+	}
+
+	if query.Domain, err = glob.Compile(ctx.String("domain")); err != nil {
+		return meetup.MeetingQuery{}, fmt.Errorf("invalid domain pattern: %w", err)
+	}
+
+	query.IncludeArchived = ctx.Bool("include-archived")
+
+	return query, nil
+}
+
+func Agenda(ctx *cli.Context) error {
+	format := meetup.OutputFormat(ctx.String("format"))
+	if err := format.Validate(); err != nil {
+		return err
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	query, err := agendaQueryFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	date, err := meetup.ParseDate(ctx.String("date"), time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid --date: %w", err)
+	}
+
+	dueWithin, err := meetup.ParseDuration(ctx.String("due-within"))
+	if err != nil {
+		return fmt.Errorf("invalid --due-within: %w", err)
+	}
+
+	agenda, err := manager.Agenda(query, meetup.AgendaOptions{
+		Date:      date,
+		Week:      ctx.Bool("week"),
+		DueWithin: dueWithin,
+	})
+	if err != nil {
+		return err
+	}
+
+	if format == meetup.FormatJSON {
+		return printJSON(agenda)
+	}
+
+	current, previous := "TODAY", "YESTERDAY"
+	if ctx.Bool("week") {
+		current, previous = "THIS WEEK", "LAST WEEK"
+	}
+
+	if agenda.Start == agenda.End {
+		fmt.Printf("Agenda for %s\n", agenda.Start)
+	} else {
+		fmt.Printf("Agenda for %s to %s\n", agenda.Start, agenda.End)
+	}
+
+	printMeetings := func(heading string, meetings []meetup.Meeting) {
+		fmt.Printf("\n%s\n", heading)
+
+		if len(meetings) == 0 {
+			fmt.Println("  no meetings")
+		}
+
+		for _, meeting := range meetings {
+			fmt.Printf("  %s\n", meeting)
+		}
+	}
+
+	printTasks := func(heading string, tasks []meetup.Task) {
+		if len(tasks) == 0 {
+			return
+		}
+
+		fmt.Printf("\n%s\n", heading)
+
+		for _, task := range tasks {
+			fmt.Printf("  %s %s %s [%s]\n", task.Due, checkBox(task), task.Description, task.Meeting)
+		}
+	}
+
+	printMeetings(current, agenda.Meetings)
+	printMeetings(previous, agenda.Previous)
+	printTasks("OVERDUE", agenda.Overdue)
+	printTasks("DUE SOON", agenda.Due)
+
+	return nil
+}
+
+func Digest(ctx *cli.Context) error {
+	format := ctx.String("format")
+
+	switch format {
+	case "markdown", "json":
+	default:
+		return fmt.Errorf("invalid format '%s': expected one of markdown or json", format)
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	query, err := agendaQueryFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	// a bare duration (ie 7d) counts back from today
+	since := ctx.String("since")
+	if d, err := meetup.ParseDuration(since); err == nil && d > 0 && !strings.HasPrefix(since, "+") {
+		since = "-" + since
+	}
+
+	opts := meetup.DigestOptions{}
+
+	if opts.Since, err = meetup.ParseDate(since, time.Now()); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+
+	if opts.Until, err = meetup.ParseDate(ctx.String("until"), time.Now()); err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	digest, err := manager.Digest(query, opts)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		return printJSON(digest)
+	}
+
+	return digest.WriteMarkdown(os.Stdout)
+}
+
+func Rollup(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	date, err := meetup.ParseDate(ctx.String("date"), time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid --date: %w", err)
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	period := meetup.StatsPeriod(ctx.Args().First())
+
+	if ctx.Bool("print") {
+		data, err := manager.Rollup(period, date)
+		if err != nil {
+			return err
+		}
+
+		return manager.RenderRollup(os.Stdout, data)
+	}
+
+	meeting, err := manager.CreateRollup(period, date, ctx.Bool("regenerate"))
+	if err != nil {
+		return err
+	}
+
+	return manager.OpenMeeting(meeting)
+}
+
+// describeLink returns a link as written in its meeting.
+func describeLink(link meetup.WikiLink) string {
+	if link.Markdown {
+		return "(" + link.Target + ")"
+	}
+
+	if link.Label != "" {
+		return "[[" + link.Target + "|" + link.Label + "]]"
+	}
+
+	return "[[" + link.Target + "]]"
+}
+
+func Links(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	format := meetup.OutputFormat(ctx.String("format"))
+	if err := format.Validate(); err != nil {
+		return err
+	}
+
+	manager, err := GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("broken") {
+		if ctx.NArg() > 0 {
+			return fmt.Errorf("too many arguments")
+		}
+
+		broken, err := manager.BrokenLinks()
+		if err != nil {
+			return err
+		}
+
+		if format == meetup.FormatJSON {
+			return printJSON(broken)
+		}
+
+		if len(broken) == 0 {
+			fmt.Println("no broken links found")
+			return nil
+		}
+
+		for _, link := range broken {
+			fmt.Printf("%s:%d %s: %s\n", link.Source, link.Line, describeLink(link), link.Error)
+		}
+
+		return fmt.Errorf("found %d broken link(s)", len(broken))
+	}
+
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing required arguments")
+	}
+
+	meeting, err := parseMeeting(ctx.String("date"), ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	links, err := manager.Links(meeting)
+	if err != nil {
+		return err
+	}
+
+	if format == meetup.FormatJSON {
+		return printJSON(links)
+	}
+
+	fmt.Println("OUTGOING")
+
+	if len(links.Outgoing) == 0 {
+		fmt.Println("  no links")
+	}
+
+	for _, link := range links.Outgoing {
+		if link.Broken() {
+			fmt.Printf("  %d: %s broken: %s\n", link.Line, describeLink(link), link.Error)
+		} else {
+			fmt.Printf("  %d: %s -> %s\n", link.Line, describeLink(link), link.Meeting)
+		}
+	}
+
+	fmt.Println("\nINCOMING")
+
+	if len(links.Incoming) == 0 {
+		fmt.Println("  no links")
+	}
+
+	for _, link := range links.Incoming {
+		fmt.Printf("  %s:%d %s\n", link.Source, link.Line, describeLink(link))
+	}
+
+	return nil
+}
+
+// todo: add completion
+func Run(args []string) error {
+	app := cli.App{
+		Name:    "meetup",
+		Version: Version,
 		Usage:   "meetup is a tool for managing meeting notes",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "profile",
+				Usage:   "the profile to use",
+				EnvVars: []string{"MEETUP_PROFILE"},
+			},
+			&cli.StringFlag{
+				Name:  "root",
+				Usage: "override the meetup directory",
+			},
+			&cli.StringFlag{
+				Name:  "editor",
+				Usage: "override the editor command",
+			},
+		},
 		Commands: []*cli.Command{
 			{
 				Name:  "meeting",
@@ -291,26 +2674,27 @@ func Run(args []string) error {
 					{
 						Name:      "open",
 						Usage:     "open an existing or create a new meeting",
-						UsageText: "meetup open <domain>.<name>",
+						UsageText: "meetup open <domain>.<name>[@<time>][+<sequence>]",
 						Action:    MeetingOpen,
 						Flags: []cli.Flag{
 							&cli.StringFlag{
 								Name:  "date",
-								Usage: "date of the meeting",
-								Value: cli.NewTimestamp(time.Now()).Value().Format(DateFormat),
-								Action: func(ctx *cli.Context, date string) error {
-									if _, err := time.Parse(DateFormat, date); err != nil {
-										return fmt.Errorf("invalid date format: %w", err)
-									}
-
-									return nil
-								},
+								Usage: "date of the meeting (eg 2024-01-31, yesterday, last monday, +3d)",
+								Value: "today",
 							},
 							&cli.StringFlag{
 								Name:    "template",
 								Usage:   "template to use for the meeting",
 								Aliases: []string{"t"},
 							},
+							&cli.StringFlag{
+								Name:  "time",
+								Usage: "start time of the meeting (eg 14:30 or 2:30pm), which is included in its name",
+							},
+							&cli.BoolFlag{
+								Name:  "new",
+								Usage: "create another meeting rather than reopening an existing one with the same name, date, and time",
+							},
 						},
 					},
 					{
@@ -319,30 +2703,98 @@ func Run(args []string) error {
 						Usage:     "list existing meeting",
 						UsageText: "meetup list",
 						Action:    MeetingList,
+						Flags: append(append(meetingQueryFlags(), listFlags()...),
+							&cli.BoolFlag{
+								Name:  "all-profiles",
+								Usage: "list meetings from every profile",
+							},
+							queryFlag("only list meetings matching the query (eg 'domain:work.* AND NOT name:standup')"),
+							&cli.BoolFlag{
+								Name:  "include-archived",
+								Usage: "include archived meetings",
+							},
+							viewFlag(),
+							formatFlag(),
+						),
+					},
+					{
+						Name:      "remove",
+						Aliases:   []string{"rm"},
+						Usage:     "move an existing meeting to the trash",
+						UsageText: "meetup remove <date> <domain> <name>",
+						Action:    MeetingRemove,
+					},
+					{
+						Name:      "move",
+						Aliases:   []string{"mv"},
+						Usage:     "move or rename a meeting, or change its date",
+						UsageText: "meetup meeting move [--to-date <date>] [--to-time <time>] <date> <domain>.<name> [<new-domain>.<new-name>]",
+						Action:    MeetingMove,
 						Flags: []cli.Flag{
 							&cli.StringFlag{
-								Name:  "date",
-								Usage: "date of the meeting as a wildcard",
-								Value: "*",
+								Name:  "to-date",
+								Usage: "the new date of the meeting",
 							},
 							&cli.StringFlag{
-								Name:  "name",
-								Usage: "the name of the meeting as a wildcard",
-								Value: "*",
+								Name:  "to-time",
+								Usage: "the new start time of the meeting",
 							},
-							&cli.StringFlag{
-								Name:  "domain",
-								Usage: "the domain of the meeting as a wildcard",
-								Value: "*",
+							&cli.BoolFlag{
+								Name:    "force",
+								Aliases: []string{"f"},
+								Usage:   "replace an existing meeting at the destination, moving it to the trash",
 							},
 						},
 					},
 					{
-						Name:      "remove",
-						Aliases:   []string{"rm"},
-						Usage:     "remove an existing meeting",
-						UsageText: "meetup remove <date> <domain> <name>",
-						Action:    MeetingRemove,
+						Name:  "bulk",
+						Usage: "apply an operation to every meeting matching a query",
+						Subcommands: []*cli.Command{
+							{
+								Name:    "remove",
+								Aliases: []string{"rm"},
+								Usage:   "move matching meetings to the trash",
+								Action:  BulkRemove,
+								Flags:   bulkFlags(),
+							},
+							{
+								Name:   "move",
+								Usage:  "move matching meetings to another domain",
+								Action: BulkMove,
+								Flags: bulkFlags(&cli.StringFlag{
+									Name:     "to",
+									Usage:    "the domain to move meetings to",
+									Required: true,
+								}),
+							},
+							{
+								Name:   "redate",
+								Usage:  "change the date of matching meetings",
+								Action: BulkRedate,
+								Flags: bulkFlags(&cli.StringFlag{
+									Name:     "to",
+									Usage:    "the new date for the meetings",
+									Required: true,
+								}),
+							},
+							{
+								Name:   "archive",
+								Usage:  "archive matching meetings",
+								Action: BulkArchive,
+								Flags:  bulkFlags(expiredFlag()),
+							},
+							{
+								Name:   "apply-template",
+								Usage:  "replace the contents of matching meetings with a template, moving the old contents to the trash",
+								Action: BulkApplyTemplate,
+								Flags: bulkFlags(&cli.StringFlag{
+									Name:     "template",
+									Aliases:  []string{"t"},
+									Usage:    "the template to apply",
+									Required: true,
+								}),
+							},
+						},
 					},
 					{
 						Name:    "group-by",
@@ -373,18 +2825,140 @@ func Run(args []string) error {
 						Usage:   "remove a template",
 						Action:  TemplateRemove,
 					},
+					{
+						Name:      "which",
+						Usage:     "explain which template rule applies to a meeting",
+						UsageText: "meetup template which <domain>.<name>",
+						Action:    TemplateWhich,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "date",
+								Usage: "date of the meeting (eg 2024-01-31, yesterday, last monday, +3d)",
+								Value: "today",
+							},
+						},
+					},
 				},
 			},
 			{
 				Name:    "task",
 				Aliases: []string{"todo"},
 				Usage:   "list tasks",
+				Flags: append(append(append(meetingQueryFlags(), listFlags()...), taskQueryFlags()...),
+					&cli.BoolFlag{
+						Name:  "all-profiles",
+						Usage: "list tasks from every profile",
+					},
+					queryFlag("only list tasks matching the query (eg 'is:incomplete AND (tag:urgent OR date>=\"last monday\")')"),
+					&cli.BoolFlag{
+						Name:  "include-archived",
+						Usage: "include archived meetings",
+					},
+					viewFlag(),
+					formatFlag(),
+					treeFlag(),
+				),
+				Action: TaskList,
+				Subcommands: []*cli.Command{
+					{
+						Name:  "export",
+						Usage: "export tasks to todo.txt or taskwarrior",
+						Flags: append(append(meetingQueryFlags(), taskQueryFlags()...),
+							queryFlag("only export tasks matching the query"),
+							&cli.BoolFlag{
+								Name:  "include-archived",
+								Usage: "include archived meetings",
+							},
+							&cli.StringFlag{
+								Name:  "format",
+								Usage: "output format, one of todotxt or taskwarrior",
+								Value: "todotxt",
+							},
+						),
+						Action: TaskExport,
+					},
+					{
+						Name:      "import",
+						Usage:     "apply a taskwarrior export to meeting tasks",
+						UsageText: "meetup task import [--into <domain>.<name> [--date <date>]] <file|->",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "into",
+								Usage: "add tasks which did not come from a meeting to this meeting",
+							},
+							&cli.StringFlag{
+								Name:  "date",
+								Usage: "date of the meeting to add tasks to",
+								Value: "today",
+							},
+						},
+						Action: TaskImport,
+					},
+					{
+						Name:      "sync",
+						Usage:     "complete tasks marked done in a todo.txt file, then export tasks to it",
+						UsageText: "meetup task sync [options] <todo.txt>",
+						Flags: append(append(meetingQueryFlags(), taskQueryFlags()...),
+							queryFlag("only export tasks matching the query"),
+							&cli.BoolFlag{
+								Name:  "include-archived",
+								Usage: "include archived meetings",
+							},
+						),
+						Action: TaskSync,
+					},
+				},
+			},
+			{
+				Name:  "agenda",
+				Usage: "show the meetings and tasks due for a day or week",
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:  "date",
-						Usage: "date of the meeting as a wildcard",
+						Usage: "show the agenda for this date",
+						Value: "today",
+					},
+					&cli.BoolFlag{
+						Name:  "week",
+						Usage: "show the agenda for the week containing the date",
+					},
+					&cli.StringFlag{
+						Name:  "due-within",
+						Usage: "include open tasks due this long after the agenda ends",
+						Value: "3d",
+					},
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "the name of the meeting as a wildcard",
+						Value: "*",
+					},
+					&cli.StringFlag{
+						Name:  "domain",
+						Usage: "the domain of the meeting as a wildcard",
 						Value: "*",
 					},
+					&cli.BoolFlag{
+						Name:  "include-archived",
+						Usage: "include archived meetings",
+					},
+					formatFlag(),
+				},
+				Action: Agenda,
+			},
+			{
+				Name:  "digest",
+				Usage: "summarize the meetings held and tasks opened and closed as markdown",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "since",
+						Usage: "the first day of the digest, as a date or a duration before today (eg 7d, 2w, last monday)",
+						Value: "7d",
+					},
+					&cli.StringFlag{
+						Name:  "until",
+						Usage: "the last day of the digest",
+						Value: "today",
+					},
 					&cli.StringFlag{
 						Name:  "name",
 						Usage: "the name of the meeting as a wildcard",
@@ -396,20 +2970,339 @@ func Run(args []string) error {
 						Value: "*",
 					},
 					&cli.BoolFlag{
-						Name:  "complete",
-						Usage: "show only completed tasks",
+						Name:  "include-archived",
+						Usage: "include archived meetings",
+					},
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "output format, one of markdown or json",
+						Value: "markdown",
+					},
+				},
+				Action: Digest,
+			},
+			{
+				Name:      "rollup",
+				Usage:     "generate and open the rollup note for a week or month",
+				UsageText: "meetup rollup [--date <date>] [--regenerate] week|month",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "date",
+						Usage: "roll up the week or month containing this date",
+						Value: "today",
+					},
+					&cli.BoolFlag{
+						Name:  "regenerate",
+						Usage: "regenerate an existing rollup note, moving the old note to the trash",
 					},
 					&cli.BoolFlag{
-						Name:  "incomplete",
-						Usage: "show only incomplete tasks",
+						Name:  "print",
+						Usage: "print the generated rollup rather than creating the note",
 					},
+				},
+				Action: Rollup,
+			},
+			{
+				Name:      "links",
+				Usage:     "show the links from a meeting and the links to it from other meetings",
+				UsageText: "meetup links [--date <date>] <domain>.<name>[@<time>][+<sequence>]\n   meetup links --broken",
+				Flags: []cli.Flag{
 					&cli.StringFlag{
-						Name:  "description",
-						Usage: "the description of the task as a wildcard",
-						Value: "*",
+						Name:  "date",
+						Usage: "date of the meeting (eg 2024-01-31, yesterday, last monday, +3d)",
+						Value: "today",
+					},
+					&cli.BoolFlag{
+						Name:  "broken",
+						Usage: "list the links in every meeting which do not refer to an existing meeting or file",
+					},
+					formatFlag(),
+				},
+				Action: Links,
+			},
+			{
+				Name:  "stats",
+				Usage: "report meeting and task counts by domain and period",
+				Flags: append(meetingQueryFlags(),
+					queryFlag("only count tasks matching the query"),
+					&cli.BoolFlag{
+						Name:  "include-archived",
+						Usage: "include archived meetings",
+					},
+					&cli.StringFlag{
+						Name:  "period",
+						Usage: "group meetings and tasks by day, week, month, or year",
+						Value: string(meetup.PeriodWeek),
+					},
+					&cli.IntFlag{
+						Name:  "oldest",
+						Usage: "show this many of the oldest open tasks",
+						Value: 5,
+					},
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "output format, one of text, json, or csv",
+						Value: "text",
+					},
+				),
+				Action: Stats,
+			},
+			{
+				Name:   "archive",
+				Usage:  "archive matching meetings, see the subcommands to view or restore them",
+				Action: BulkArchive,
+				Flags:  bulkFlags(expiredFlag()),
+				Subcommands: []*cli.Command{
+					{
+						Name:    "list",
+						Aliases: []string{"ls"},
+						Usage:   "list archived meetings",
+						Action:  ArchiveList,
+						Flags:   meetingQueryFlags(),
+					},
+					{
+						Name:   "restore",
+						Usage:  "restore matching archived meetings",
+						Action: ArchiveRestore,
+						Flags:  bulkFlags(),
+					},
+				},
+			},
+			{
+				Name:  "view",
+				Usage: "manage saved meeting and task listings",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "save",
+						Usage:     "save a listing as a named view, replacing any existing view with the same name",
+						UsageText: "meetup view save [--tasks] [--query <query>] [--range <range>] [filters] <name>",
+						Action:    ViewSave,
+						Flags: append(append(listFlags(), viewFilterFlags()...),
+							&cli.BoolFlag{
+								Name:  "tasks",
+								Usage: "list tasks rather than meetings",
+							},
+							queryFlag("the meeting query, or task query with --tasks"),
+							&cli.StringFlag{
+								Name:  "range",
+								Usage: "only match meetings in this date range (this-week, last-week, this-month, or last-month) relative to when the view is run",
+							},
+							&cli.BoolFlag{
+								Name:  "include-archived",
+								Usage: "include archived meetings",
+							},
+							&cli.StringFlag{
+								Name:  "format",
+								Usage: "output format, one of text or json",
+							},
+						),
+					},
+					{
+						Name:    "list",
+						Aliases: []string{"ls"},
+						Usage:   "list saved views",
+						Action:  ViewList,
+					},
+					{
+						Name:      "run",
+						Usage:     "list the meetings or tasks in a view, optionally narrowed further by flags",
+						UsageText: "meetup view run <name>",
+						Action:    ViewRun,
+						Flags: append(append(append(meetingQueryFlags(), listFlags()...), taskQueryFlags()...),
+							&cli.BoolFlag{
+								Name:  "all-profiles",
+								Usage: "list from every profile",
+							},
+							queryFlag("only list results also matching the query"),
+							&cli.BoolFlag{
+								Name:  "include-archived",
+								Usage: "include archived meetings",
+							},
+							formatFlag(),
+							treeFlag(),
+						),
+					},
+					{
+						Name:      "remove",
+						Aliases:   []string{"rm"},
+						Usage:     "remove saved views",
+						UsageText: "meetup view remove <name>...",
+						Action:    ViewRemove,
+					},
+				},
+			},
+			{
+				Name:  "domain",
+				Usage: "manage meeting domains",
+				Subcommands: []*cli.Command{
+					{
+						Name:    "list",
+						Aliases: []string{"ls"},
+						Usage:   "show the domain tree with meeting counts and date ranges",
+						Action:  DomainList,
+					},
+					{
+						Name:      "rename",
+						Usage:     "move every meeting in a domain and its subdomains to a new domain",
+						UsageText: "meetup domain rename <old> <new>",
+						Action:    DomainRename,
+					},
+					{
+						Name:      "merge",
+						Usage:     "move every meeting in a domain and its subdomains into an existing domain",
+						UsageText: "meetup domain merge <from> <into>",
+						Action:    DomainMerge,
+					},
+					{
+						Name:      "remove",
+						Aliases:   []string{"rm"},
+						Usage:     "move every meeting in a domain to the trash",
+						UsageText: "meetup domain remove [--recursive] <domain>",
+						Action:    DomainRemove,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:    "recursive",
+								Aliases: []string{"r"},
+								Usage:   "include meetings in subdomains",
+							},
+							&cli.BoolFlag{
+								Name:    "yes",
+								Aliases: []string{"y"},
+								Usage:   "do not ask for confirmation",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "trash",
+				Usage: "manage removed meetings",
+				Subcommands: []*cli.Command{
+					{
+						Name:    "list",
+						Aliases: []string{"ls"},
+						Usage:   "list meetings in the trash",
+						Action:  TrashList,
+					},
+					{
+						Name:      "restore",
+						Usage:     "restore meetings from the trash",
+						UsageText: "meetup trash restore <id>...",
+						Action:    TrashRestore,
+					},
+					{
+						Name:   "empty",
+						Usage:  "permanently delete meetings in the trash",
+						Action: TrashEmpty,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "older-than",
+								Usage: "only delete meetings removed longer ago than this duration (eg 30d)",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:   "doctor",
+				Usage:  "check the meetup directory for problems",
+				Action: Doctor,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "fix",
+						Usage: "repair or quarantine problematic files",
+					},
+				},
+			},
+			{
+				Name:  "config",
+				Usage: "manage configuration and metadata",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "get",
+						Usage:     "print the effective value of a key",
+						UsageText: "meetup config get <key>",
+						Action:    ConfigGet,
+					},
+					{
+						Name:      "set",
+						Usage:     "set the value of a key for the active profile",
+						UsageText: "meetup config set <key> <yaml-value>",
+						Action:    ConfigSet,
+					},
+					{
+						Name:      "unset",
+						Usage:     "remove a key from the active profile",
+						UsageText: "meetup config unset <key>",
+						Action:    ConfigUnset,
+					},
+					{
+						Name:   "show",
+						Usage:  "show the effective configuration and where each value came from",
+						Action: ConfigShow,
+					},
+					{
+						Name:   "path",
+						Usage:  "print the path to the config file",
+						Action: ConfigPath,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "metadata",
+								Usage: "print the path to the metadata file instead",
+							},
+						},
+					},
+					{
+						Name:   "edit",
+						Usage:  "open the config file in the editor",
+						Action: ConfigEdit,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "metadata",
+								Usage: "edit the metadata file instead",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "profile",
+				Usage: "manage profiles",
+				Subcommands: []*cli.Command{
+					{
+						Name:    "list",
+						Aliases: []string{"ls"},
+						Usage:   "list available profiles",
+						Action:  ProfileList,
+					},
+					{
+						Name:      "add",
+						Usage:     "add a new profile",
+						UsageText: "meetup profile add --root <dir> <name>",
+						Action:    ProfileAdd,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "root",
+								Usage:    "the meetup directory for the profile",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "editor",
+								Usage: "the editor command for the profile",
+							},
+							&cli.StringFlag{
+								Name:  "group-by",
+								Usage: "the group by strategy for new meetup directories in the profile",
+							},
+						},
+					},
+					{
+						Name:      "use",
+						Usage:     "set the profile to use by default",
+						UsageText: "meetup profile use <name>",
+						Action:    ProfileUse,
 					},
 				},
-				Action: TaskList,
 			},
 		},
 	}
@@ -0,0 +1,153 @@
+package meetup
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+var dueRegex = regexp.MustCompile(`(?:^|\s)due:(\S+)`)
+
+// findDue returns the due date in a task's description (ie "send the report due:friday"), or an empty string if there
+// is none. Dates may be absolute or relative to the meeting's date (see ParseDate), and invalid dates are ignored.
+func findDue(description string, meeting Meeting) string {
+	match := dueRegex.FindStringSubmatch(description)
+	if match == nil {
+		return ""
+	}
+
+	held, err := time.ParseInLocation(DateFormat, meeting.Date, time.Local)
+	if err != nil {
+		held = time.Now()
+	}
+
+	due, err := ParseDate(match[1], held)
+	if err != nil {
+		return ""
+	}
+
+	return due.Format(DateFormat)
+}
+
+type AgendaOptions struct {
+	// Date is any day in the agenda, defaulting to today.
+	Date time.Time
+
+	// Week makes the agenda cover the Monday to Sunday week containing Date, rather than only Date.
+	Week bool
+
+	// DueWithin includes open tasks due up to this long after the end of the agenda.
+	DueWithin Duration
+}
+
+// Agenda lists the meetings and open tasks around a day or week.
+type Agenda struct {
+	// Start and End are the first and last days of the agenda, in DateFormat.
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	// Meetings are the meetings between Start and End, and Previous are the meetings in the same length of time before
+	// Start (ie yesterday's meetings for a daily agenda).
+	Meetings []Meeting `json:"meetings"`
+	Previous []Meeting `json:"previous"`
+
+	// Overdue are the open tasks due before Start, and Due are the open tasks due from Start until DueWithin after End.
+	// Both are sorted by due date.
+	Overdue []Task `json:"overdue"`
+	Due     []Task `json:"due"`
+}
+
+// Agenda builds the agenda of the meetings matching the query. Tasks carried over between meetings are only included
+// once, from their latest meeting, and only if they are still open there.
+func (m *Manager) Agenda(query MeetingQuery, opts AgendaOptions) (Agenda, error) {
+	if opts.Date.IsZero() {
+		opts.Date = time.Now()
+	}
+
+	start, end := truncateDay(opts.Date), truncateDay(opts.Date)
+	if opts.Week {
+		start, end = Week(opts.Date)
+	}
+
+	length := int(end.Sub(start).Hours()/24+0.5) + 1
+
+	agenda := Agenda{
+		Start:    start.Format(DateFormat),
+		End:      end.Format(DateFormat),
+		Meetings: []Meeting{},
+		Previous: []Meeting{},
+		Overdue:  []Task{},
+		Due:      []Task{},
+	}
+
+	meetings, err := m.ListMeetings(query)
+	if err != nil {
+		return Agenda{}, err
+	}
+
+	previous := start.AddDate(0, 0, -length).Format(DateFormat)
+
+	for _, meeting := range meetings {
+		switch {
+		case meeting.Date >= agenda.Start && meeting.Date <= agenda.End:
+			agenda.Meetings = append(agenda.Meetings, meeting)
+		case meeting.Date >= previous && meeting.Date < agenda.Start:
+			agenda.Previous = append(agenda.Previous, meeting)
+		}
+	}
+
+	slices.SortStableFunc(agenda.Meetings, func(a, b Meeting) int {
+		if c := strings.Compare(a.Date, b.Date); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Time, b.Time)
+	})
+
+	// tasks closed in a later meeting are not open, so every task is read before keeping the latest
+	tasks, err := m.Tasks(TaskQuery{Meeting: query})
+	if err != nil {
+		return Agenda{}, err
+	}
+
+	dueBy := end.Add(time.Duration(opts.DueWithin)).Format(DateFormat)
+
+	for _, task := range latestTasks(tasks) {
+		switch {
+		case task.State.Done || task.Due == "" || task.Due > dueBy:
+		case task.Due < agenda.Start:
+			agenda.Overdue = append(agenda.Overdue, task)
+		default:
+			agenda.Due = append(agenda.Due, task)
+		}
+	}
+
+	byDue := func(a, b Task) int {
+		return strings.Compare(a.Due, b.Due)
+	}
+
+	slices.SortStableFunc(agenda.Overdue, byDue)
+	slices.SortStableFunc(agenda.Due, byDue)
+
+	return agenda, nil
+}
+
+// latestTasks keeps only the last of the tasks with the same identity, keeping the order of the tasks.
+func latestTasks(tasks []Task) []Task {
+	latest := map[taskIdentity]int{}
+	for i, task := range tasks {
+		if previous, found := latest[identify(task)]; !found || task.Meeting.Date >= tasks[previous].Meeting.Date {
+			latest[identify(task)] = i
+		}
+	}
+
+	var kept []Task
+	for i, task := range tasks {
+		if latest[identify(task)] == i {
+			kept = append(kept, task)
+		}
+	}
+
+	return kept
+}
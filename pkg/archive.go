@@ -0,0 +1,594 @@
+package meetup
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	ArchiveDirName = ".archive"
+)
+
+// ArchiveFormat determines how archived meetings are stored in the archive directory.
+type ArchiveFormat string
+
+const (
+	// ArchiveTree stores archived meetings as plain files which mirror the meetup directory's layout.
+	ArchiveTree ArchiveFormat = "tree"
+
+	// ArchiveTarGz stores each archived meeting and its assets as a gzipped tarball.
+	ArchiveTarGz ArchiveFormat = "tar.gz"
+
+	// ArchiveZip stores each archived meeting and its assets as a zip file.
+	ArchiveZip ArchiveFormat = "zip"
+)
+
+var archiveFormats = []ArchiveFormat{ArchiveTree, ArchiveTarGz, ArchiveZip}
+
+func (f ArchiveFormat) Validate() error {
+	switch f {
+	case "", ArchiveTree, ArchiveTarGz, ArchiveZip:
+		return nil
+	default:
+		return fmt.Errorf("invalid archive format: %s", f)
+	}
+}
+
+// ext returns the file extension appended to archived meetings in this format.
+func (f ArchiveFormat) ext() string {
+	if f == "" || f == ArchiveTree {
+		return ""
+	}
+
+	return "." + string(f)
+}
+
+func (m *Manager) archiveDir() string {
+	return path.Join(m.RootDir, ArchiveDirName)
+}
+
+// archivePath returns where the meeting is stored in the archive directory for the given format.
+func (m *Manager) archivePath(meeting Meeting, format ArchiveFormat) string {
+	base := path.Join(m.archiveDir(), strings.TrimPrefix(meeting.GetPath(m.RootDir, m.metadata.GroupBy), m.RootDir))
+	return withExtension(base, m.DomainConfig(meeting.Domain).Extension, format.ext())
+}
+
+// findArchive returns the path and format of the archived meeting, regardless of the currently configured format.
+func (m *Manager) findArchive(meeting Meeting) (string, ArchiveFormat, error) {
+	for _, format := range archiveFormats {
+		p := m.archivePath(meeting, format)
+
+		if _, err := os.Stat(p); err == nil {
+			return p, format, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("meeting '%s' is not archived", meeting)
+}
+
+// archiveFiles returns the meeting file and every file in its assets directory, relative to the meeting's directory.
+func (m *Manager) archiveFiles(meeting Meeting) ([]string, error) {
+	src := m.pathForMeeting(meeting)
+	dir := path.Dir(src)
+	files := []string{path.Base(src)}
+
+	err := filepath.WalkDir(m.assetsPath(meeting), func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if !entry.IsDir() {
+			files = append(files, strings.TrimPrefix(p, dir+"/"))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list meeting assets: %w", err)
+	}
+
+	return files, nil
+}
+
+func writeTarGz(dst string, dir string, files []string) error {
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+
+	for _, name := range files {
+		data, err := os.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: time.Now()}); err != nil {
+			return err
+		}
+
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	if err := gw.Close(); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+func writeZip(dst string, dir string, files []string) error {
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+
+	for _, name := range files {
+		data, err := os.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+// readArchive calls fn with the name and contents of every file in a compressed archive.
+func readArchive(src string, format ArchiveFormat, fn func(name string, r io.Reader) error) error {
+	switch format {
+	case ArchiveTarGz:
+		in, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		gr, err := gzip.NewReader(in)
+		if err != nil {
+			return err
+		}
+
+		tr := tar.NewReader(gr)
+
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err := fn(header.Name, tr); err != nil {
+				return err
+			}
+		}
+	case ArchiveZip:
+		zr, err := zip.OpenReader(src)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+
+		for _, file := range zr.File {
+			r, err := file.Open()
+			if err != nil {
+				return err
+			}
+
+			err = fn(file.Name, r)
+			r.Close()
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("'%s' is not a compressed archive format", format)
+	}
+}
+
+// ArchiveMeeting moves the meeting and its assets into the archive directory using the configured archive format.
+// Archived meetings are excluded when listing meetings unless explicitly included. The meeting is only removed from the
+// meetup directory once it and its assets have been archived.
+func (m *Manager) ArchiveMeeting(meeting Meeting) error {
+	src := m.pathForMeeting(meeting)
+	format := m.metadata.ArchiveFormat
+
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("could not archive meeting: %w", err)
+	}
+
+	if _, _, err := m.findArchive(meeting); err == nil {
+		return fmt.Errorf("could not archive meeting: '%s' is already archived", meeting)
+	}
+
+	dst := m.archivePath(meeting, format)
+
+	if err := os.MkdirAll(path.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("could not create archive directory: %w", err)
+	}
+
+	assets := m.assetsPath(meeting)
+	_, statErr := os.Stat(assets)
+	hasAssets := statErr == nil
+
+	switch format {
+	case "", ArchiveTree:
+		archivedAssets := path.Join(m.archiveDir(), strings.TrimPrefix(assets, m.RootDir))
+
+		if hasAssets {
+			if err := os.Rename(assets, archivedAssets); err != nil {
+				return fmt.Errorf("could not archive meeting assets: %w", err)
+			}
+		}
+
+		if err := os.Rename(src, dst); err != nil {
+			if hasAssets {
+				os.Rename(archivedAssets, assets)
+			}
+
+			return fmt.Errorf("could not archive meeting: %w", err)
+		}
+	default:
+		files, err := m.archiveFiles(meeting)
+		if err != nil {
+			return fmt.Errorf("could not archive meeting: %w", err)
+		}
+
+		write := writeTarGz
+		if format == ArchiveZip {
+			write = writeZip
+		}
+
+		// write the archive under a temporary name so that it is never found partially written
+		if err := write(dst+".tmp", path.Dir(src), files); err != nil {
+			os.Remove(dst + ".tmp")
+			return fmt.Errorf("could not write archive: %w", err)
+		}
+
+		if err := os.Rename(dst+".tmp", dst); err != nil {
+			os.Remove(dst + ".tmp")
+			return fmt.Errorf("could not write archive: %w", err)
+		}
+
+		// move the assets out of the way first so that they can be put back if the meeting cannot be removed
+		removedAssets := dst + AssetsDirSuffix + ".tmp"
+
+		if hasAssets {
+			if err := os.Rename(assets, removedAssets); err != nil {
+				os.Remove(dst)
+				return fmt.Errorf("could not archive meeting assets: %w", err)
+			}
+		}
+
+		if err := os.Remove(src); err != nil {
+			if hasAssets {
+				os.Rename(removedAssets, assets)
+			}
+
+			os.Remove(dst)
+
+			return fmt.Errorf("could not archive meeting: %w", err)
+		}
+
+		if err := os.RemoveAll(removedAssets); err != nil {
+			return fmt.Errorf("could not remove archived meeting assets: %w", err)
+		}
+	}
+
+	if _, err := m.pruneDirs(path.Dir(src)); err != nil {
+		return fmt.Errorf("could not archive meeting: %w", err)
+	}
+
+	return nil
+}
+
+// migrateArchive moves the archived meetings and their assets from the oldGs to the newGs layout within the archive
+// directory. It must be called before the metadata is updated to newGs.
+func (m *Manager) migrateArchive(oldGs GroupStrategy, newGs GroupStrategy) error {
+	archived, err := m.ListArchived(MeetingQuery{})
+	if err != nil {
+		return err
+	}
+
+	archivePath := func(meeting Meeting, gs GroupStrategy, suffix string) string {
+		return path.Join(m.archiveDir(), strings.TrimPrefix(meeting.GetPath(m.RootDir, gs), m.RootDir)) + suffix
+	}
+
+	for _, meeting := range archived {
+		src, format, err := m.findArchive(meeting)
+		if err != nil {
+			return err
+		}
+
+		// the archive keeps its name, which may not include the extension configured for the domain
+		dst := archivePath(meeting, newGs, strings.TrimPrefix(path.Base(src), meeting.Name))
+
+		if err := os.MkdirAll(path.Dir(dst), 0755); err != nil {
+			return fmt.Errorf("could not create archive directory: %w", err)
+		}
+
+		if err := os.Rename(src, dst); err != nil {
+			return fmt.Errorf("could not move archived meeting '%s': %w", meeting, err)
+		}
+
+		// compressed archives hold their assets, but tree archives keep them in a directory next to the meeting
+		if assets := archivePath(meeting, oldGs, AssetsDirSuffix); format.ext() == "" {
+			if _, err := os.Stat(assets); err == nil {
+				if err := os.Rename(assets, archivePath(meeting, newGs, AssetsDirSuffix)); err != nil {
+					return fmt.Errorf("could not move archived meeting assets for '%s': %w", meeting, err)
+				}
+			}
+		}
+
+		if _, err := m.pruneDirs(path.Dir(src)); err != nil {
+			return fmt.Errorf("could not move archived meeting '%s': %w", meeting, err)
+		}
+	}
+
+	return nil
+}
+
+// ArchiveExpired archives every meeting which is older than the retention configured for its domain, and returns the
+// archived meetings.
+func (m *Manager) ArchiveExpired(now time.Time) ([]Meeting, error) {
+	expired, err := m.ExpiredMeetings(now)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, meeting := range expired {
+		if err := m.ArchiveMeeting(meeting); err != nil {
+			return expired[:i], err
+		}
+	}
+
+	return expired, nil
+}
+
+// ListArchived returns the archived meetings matching the query.
+func (m *Manager) ListArchived(mw MeetingQuery) ([]Meeting, error) {
+	meetings := []Meeting{}
+	root := m.archiveDir()
+
+	err := filepath.WalkDir(root, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if entry.IsDir() {
+			if strings.HasSuffix(entry.Name(), AssetsDirSuffix) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		rel := strings.TrimPrefix(p, root)
+		for _, format := range archiveFormats {
+			if ext := format.ext(); ext != "" && strings.HasSuffix(rel, ext) {
+				rel = strings.TrimSuffix(rel, ext)
+				break
+			}
+		}
+
+		meeting, err := m.parseMeetingPath(m.metadata.GroupBy, rel)
+		if err != nil {
+			return nil
+		}
+
+		meeting.Archived = true
+
+		if mw.match(meeting, m.tagsFunc(meeting)) {
+			meetings = append(meetings, meeting)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("could not list archived meetings: %w", err)
+	}
+
+	return meetings, nil
+}
+
+// openMeeting opens the meeting file for reading, whether or not it is archived.
+func (m *Manager) openMeeting(meeting Meeting) (io.ReadCloser, error) {
+	if !meeting.Archived {
+		return os.Open(m.pathForMeeting(meeting))
+	}
+
+	p, format, err := m.findArchive(meeting)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == ArchiveTree {
+		return os.Open(p)
+	}
+
+	name := path.Base(strings.TrimSuffix(p, format.ext()))
+
+	var data []byte
+	found := false
+
+	err = readArchive(p, format, func(entry string, r io.Reader) error {
+		if entry != name {
+			return nil
+		}
+
+		found = true
+		data, err = io.ReadAll(r)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read archive: %w", err)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("archive '%s' does not contain '%s'", p, name)
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// RestoreArchived moves an archived meeting and its assets back into the meetup directory. Restoring will fail rather
+// than overwrite an existing meeting, and leaves the meeting archived if it cannot be restored completely.
+func (m *Manager) RestoreArchived(meeting Meeting) error {
+	meeting.Archived = false
+
+	src, format, err := m.findArchive(meeting)
+	if err != nil {
+		return err
+	}
+
+	dst := m.pathForMeeting(meeting)
+	assets := m.assetsPath(meeting)
+
+	for _, p := range []string{dst, assets} {
+		if _, err := os.Stat(p); err == nil {
+			return fmt.Errorf("could not restore meeting: '%s' already exists", strings.TrimPrefix(p, m.RootDir+"/"))
+		}
+	}
+
+	if err := os.MkdirAll(path.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("could not create meeting directory: %w", err)
+	}
+
+	if format == ArchiveTree {
+		archivedAssets := path.Join(m.archiveDir(), strings.TrimPrefix(assets, m.RootDir))
+
+		if err := restoreFiles(src, dst, archivedAssets, assets); err != nil {
+			return fmt.Errorf("could not restore meeting: %w", err)
+		}
+	} else {
+		// extract next to the archive and move the files into place once the whole archive has been read
+		tmp, err := os.MkdirTemp(m.archiveDir(), ".restore-")
+		if err != nil {
+			return fmt.Errorf("could not restore meeting: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+
+		if err := extractArchive(src, format, tmp); err != nil {
+			return fmt.Errorf("could not restore meeting: %w", err)
+		}
+
+		name := path.Base(strings.TrimSuffix(src, format.ext()))
+
+		if err := restoreFiles(path.Join(tmp, name), dst, path.Join(tmp, path.Base(assets)), assets); err != nil {
+			return fmt.Errorf("could not restore meeting: %w", err)
+		}
+
+		if err := os.Remove(src); err != nil {
+			return fmt.Errorf("could not remove archive: %w", err)
+		}
+
+		if err := os.RemoveAll(tmp); err != nil {
+			return fmt.Errorf("could not restore meeting: %w", err)
+		}
+	}
+
+	if _, err := m.pruneDirs(path.Dir(src)); err != nil {
+		return fmt.Errorf("could not restore meeting: %w", err)
+	}
+
+	return nil
+}
+
+// extractArchive writes every file in a compressed archive into dir.
+func extractArchive(src string, format ArchiveFormat, dir string) error {
+	return readArchive(src, format, func(name string, r io.Reader) error {
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("archive contains invalid path '%s'", name)
+		}
+
+		p := path.Join(dir, name)
+
+		if err := os.MkdirAll(path.Dir(p), 0755); err != nil {
+			return err
+		}
+
+		out, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+
+		if _, err := io.Copy(out, r); err != nil {
+			return err
+		}
+
+		return out.Close()
+	})
+}
+
+// restoreFiles moves the meeting and its assets, if there are any, into place. The assets are moved back if the
+// meeting cannot be.
+func restoreFiles(meetingSrc string, meetingDst string, assetsSrc string, assetsDst string) error {
+	_, err := os.Stat(assetsSrc)
+	hasAssets := err == nil
+
+	if hasAssets {
+		if err := os.Rename(assetsSrc, assetsDst); err != nil {
+			return fmt.Errorf("could not restore meeting assets: %w", err)
+		}
+	}
+
+	if err := os.Rename(meetingSrc, meetingDst); err != nil {
+		if hasAssets {
+			os.Rename(assetsDst, assetsSrc)
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,556 @@
+package meetup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigSource describes where an effective configuration value came from.
+type ConfigSource string
+
+// Configuration sources, listed from lowest to highest precedence.
+const (
+	SourceDefault ConfigSource = "default"
+	SourceFile    ConfigSource = "file"
+	SourceProfile ConfigSource = "profile"
+	SourceEnv     ConfigSource = "env"
+	SourceFlag    ConfigSource = "flag"
+)
+
+const (
+	EnvRootDir = "MEETUP_ROOT_DIR"
+	EnvEditor  = "MEETUP_EDITOR"
+	EnvGroupBy = "MEETUP_GROUP_BY"
+)
+
+// ConfigLayer is a partial configuration which overrides the values set beneath it.
+type ConfigLayer struct {
+	Source ConfigSource
+	Config Config
+}
+
+// EffectiveConfig is a fully resolved configuration, along with the source of each value.
+type EffectiveConfig struct {
+	Config
+
+	Profile string
+	Sources map[string]ConfigSource
+}
+
+// EnvLayer reads configuration overrides from MEETUP_* environment variables. Editor commands are split on whitespace.
+func EnvLayer() ConfigLayer {
+	return ConfigLayer{
+		Source: SourceEnv,
+		Config: Config{
+			RootDir: os.Getenv(EnvRootDir),
+			Editor:  strings.Fields(os.Getenv(EnvEditor)),
+			DefaultMetadata: Metadata{
+				GroupBy: GroupStrategy(os.Getenv(EnvGroupBy)),
+			},
+		},
+	}
+}
+
+// Validate checks the metadata for invalid values.
+func (m Metadata) Validate() error {
+	switch m.GroupBy {
+	case "", GroupByDomain, GroupByDate:
+	default:
+		return fmt.Errorf("invalid group by strategy: %s", m.GroupBy)
+	}
+
+	for i, rule := range m.TemplateRules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("invalid template rule %d: %w", i, err)
+		}
+	}
+
+	for domain, domainConfig := range m.Domains {
+		if err := domainConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid config for domain '%s': %w", domain, err)
+		}
+	}
+
+	if m.TrashRetention != nil && *m.TrashRetention < 0 {
+		return fmt.Errorf("trash retention cannot be negative")
+	}
+
+	if err := m.ArchiveFormat.Validate(); err != nil {
+		return err
+	}
+
+	for name, view := range m.Views {
+		if err := view.Validate(); err != nil {
+			return fmt.Errorf("invalid view '%s': %w", name, err)
+		}
+	}
+
+	// check the configured states alone too, since merging hides duplicate names
+	if err := validateTaskStates(m.TaskStates); err != nil {
+		return fmt.Errorf("invalid task states: %w", err)
+	}
+
+	states := mergeTaskStates(DefaultTaskStates(), m.TaskStates)
+
+	if err := validateTaskStates(states); err != nil {
+		return fmt.Errorf("invalid task states: %w", err)
+	}
+
+	for domain, domainConfig := range m.Domains {
+		if err := validateTaskStates(mergeTaskStates(states, domainConfig.TaskStates)); err != nil {
+			return fmt.Errorf("invalid task states for domain '%s': %w", domain, err)
+		}
+	}
+
+	for period, rollup := range m.Rollups {
+		if err := validateRollupPeriod(period); err != nil {
+			return err
+		}
+
+		if strings.Contains(rollup.Domain, "/") || strings.Contains(rollup.Domain, "..") || strings.HasPrefix(rollup.Domain, ".") || strings.HasSuffix(rollup.Domain, ".") {
+			return fmt.Errorf("invalid domain '%s' for %s rollups", rollup.Domain, period)
+		}
+
+		if strings.Contains(rollup.Name, "/") {
+			return fmt.Errorf("invalid name '%s' for %s rollups", rollup.Name, period)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the config for invalid values. Unlike NewManager, unset values are allowed so that partial
+// configurations (ie profiles) can be validated.
+func (c Config) Validate() error {
+	if c.Parallelism < 0 {
+		return fmt.Errorf("parallelism cannot be negative")
+	}
+
+	if err := c.DefaultMetadata.Validate(); err != nil {
+		return fmt.Errorf("invalid default metadata: %w", err)
+	}
+
+	return nil
+}
+
+// Validate checks the top-level config and every profile for invalid values.
+func (f ConfigFile) Validate() error {
+	if err := f.Config.Validate(); err != nil {
+		return err
+	}
+
+	for name, profile := range f.Profiles {
+		if err := profile.Validate(); err != nil {
+			return fmt.Errorf("invalid profile '%s': %w", name, err)
+		}
+	}
+
+	if _, found := f.Profiles[f.Profile]; f.Profile != "" && f.Profile != DefaultProfile && !found {
+		return fmt.Errorf("no such profile '%s'", f.Profile)
+	}
+
+	return nil
+}
+
+// ParseKey splits a dotted configuration key into its components. Components containing dots (like domain names) can
+// be wrapped in brackets (ie "domains[work.product].extension").
+func ParseKey(key string) ([]string, error) {
+	var components []string
+	var current strings.Builder
+
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; c {
+		case '.':
+			if current.Len() == 0 && (i == 0 || key[i-1] != ']') {
+				return nil, fmt.Errorf("invalid key '%s': empty component", key)
+			}
+
+			if current.Len() > 0 {
+				components = append(components, current.String())
+				current.Reset()
+			}
+		case '[':
+			end := strings.IndexByte(key[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid key '%s': unterminated '['", key)
+			}
+
+			if current.Len() > 0 {
+				components = append(components, current.String())
+				current.Reset()
+			}
+
+			components = append(components, key[i+1:i+end])
+			i += end
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	if current.Len() > 0 {
+		components = append(components, current.String())
+	}
+
+	if len(components) == 0 {
+		return nil, fmt.Errorf("invalid key '%s': empty key", key)
+	}
+
+	return components, nil
+}
+
+// yamlDocument is a yaml file which can be edited by key while preserving its comments and ordering.
+type yamlDocument struct {
+	path string
+	root yaml.Node
+}
+
+func loadYamlDocument(p string) (*yamlDocument, error) {
+	doc := &yamlDocument{path: p}
+
+	data, err := os.ReadFile(p)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not read '%s': %w", p, err)
+	}
+
+	if err := yaml.Unmarshal(data, &doc.root); err != nil {
+		return nil, fmt.Errorf("could not parse '%s': %w", p, err)
+	}
+
+	if doc.root.Kind == 0 {
+		doc.root = yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		}
+	}
+
+	return doc, nil
+}
+
+// lookup returns the node at the given key, or nil if it does not exist.
+func lookupNode(node *yaml.Node, keys []string) *yaml.Node {
+	for _, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		var next *yaml.Node
+		for i := 0; i < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+
+		if next == nil {
+			return nil
+		}
+
+		node = next
+	}
+
+	return node
+}
+
+func (d *yamlDocument) set(keys []string, value *yaml.Node) error {
+	node := d.root.Content[0]
+
+	for i, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("'%s' is not a mapping", strings.Join(keys[:i], "."))
+		}
+
+		var next *yaml.Node
+		for j := 0; j < len(node.Content); j += 2 {
+			if node.Content[j].Value == key {
+				next = node.Content[j+1]
+
+				if i == len(keys)-1 {
+					node.Content[j+1] = value
+				}
+
+				break
+			}
+		}
+
+		if next == nil {
+			next = value
+			if i < len(keys)-1 {
+				next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, next)
+		}
+
+		node = next
+	}
+
+	return nil
+}
+
+func (d *yamlDocument) unset(keys []string) bool {
+	parent := lookupNode(d.root.Content[0], keys[:len(keys)-1])
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return false
+	}
+
+	for i := 0; i < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == keys[len(keys)-1] {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// decode strictly decodes the document into v, failing on unknown keys.
+func (d *yamlDocument) decode(v any) error {
+	data, err := yaml.Marshal(&d.root)
+	if err != nil {
+		return err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return nil
+}
+
+func (d *yamlDocument) save() error {
+	data, err := yaml.Marshal(&d.root)
+	if err != nil {
+		return fmt.Errorf("could not marshal '%s': %w", d.path, err)
+	}
+
+	if err := os.MkdirAll(path.Dir(d.path), 0755); err != nil {
+		return fmt.Errorf("could not create directory for '%s': %w", d.path, err)
+	}
+
+	if err := os.WriteFile(d.path, data, 0644); err != nil {
+		return fmt.Errorf("could not write '%s': %w", d.path, err)
+	}
+
+	return nil
+}
+
+func parseValue(value string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+		return nil, fmt.Errorf("could not parse value: %w", err)
+	}
+
+	if doc.Kind == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	}
+
+	return doc.Content[0], nil
+}
+
+// GetValue returns the yaml representation of the value at key in v.
+func GetValue(v any, key string) (string, error) {
+	keys, err := ParseKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return "", fmt.Errorf("could not encode value: %w", err)
+	}
+
+	found := lookupNode(&node, keys)
+	if found == nil {
+		return "", fmt.Errorf("key '%s' is not set", key)
+	}
+
+	if found.Kind == yaml.ScalarNode {
+		return found.Value, nil
+	}
+
+	found.Style = yaml.FlowStyle
+
+	data, err := yaml.Marshal(found)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal value: %w", err)
+	}
+
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+func profileKeys(profile string, keys []string) []string {
+	if profile == "" || profile == DefaultProfile {
+		return keys
+	}
+
+	return append([]string{"profiles", profile}, keys...)
+}
+
+// editConfigFile applies fn to the config file at path, and only saves the changes if the result is valid.
+func editConfigFile(p string, fn func(doc *yamlDocument) error) error {
+	doc, err := loadYamlDocument(p)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(doc); err != nil {
+		return err
+	}
+
+	var file ConfigFile
+	if err := doc.decode(&file); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	if err := file.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	return doc.save()
+}
+
+// SetConfigValue sets the value for key in the given profile of the config file at path. The value is parsed as yaml.
+func SetConfigValue(p string, profile string, key string, value string) error {
+	keys, err := ParseKey(key)
+	if err != nil {
+		return err
+	}
+
+	node, err := parseValue(value)
+	if err != nil {
+		return err
+	}
+
+	return editConfigFile(p, func(doc *yamlDocument) error {
+		return doc.set(profileKeys(profile, keys), node)
+	})
+}
+
+// UnsetConfigValue removes key from the given profile of the config file at path.
+func UnsetConfigValue(p string, profile string, key string) error {
+	keys, err := ParseKey(key)
+	if err != nil {
+		return err
+	}
+
+	return editConfigFile(p, func(doc *yamlDocument) error {
+		if !doc.unset(profileKeys(profile, keys)) {
+			return fmt.Errorf("key '%s' is not set", key)
+		}
+
+		return nil
+	})
+}
+
+// ValidateConfigFile strictly loads and validates the config file at path.
+func ValidateConfigFile(p string) error {
+	return editConfigFile(p, func(doc *yamlDocument) error { return nil })
+}
+
+// Metadata returns the manager's metadata.
+func (m *Manager) Metadata() Metadata {
+	return m.metadata
+}
+
+// MetadataPath returns the path to the manager's metadata file.
+func (m *Manager) MetadataPath() string {
+	return path.Join(m.RootDir, MetadataFilename)
+}
+
+func (m *Manager) editMetadata(fn func(doc *yamlDocument) error) error {
+	doc, err := loadYamlDocument(m.MetadataPath())
+	if err != nil {
+		return err
+	}
+
+	if err := fn(doc); err != nil {
+		return err
+	}
+
+	metadata := m.DefaultMetadata
+	if err := doc.decode(&metadata); err != nil {
+		return fmt.Errorf("invalid metadata: %w", err)
+	}
+
+	if err := metadata.Validate(); err != nil {
+		return fmt.Errorf("invalid metadata: %w", err)
+	}
+
+	if err := metadata.compileTemplateRules(); err != nil {
+		return fmt.Errorf("invalid metadata: %w", err)
+	}
+
+	if metadata.GroupBy != m.metadata.GroupBy {
+		return fmt.Errorf("group_by cannot be changed directly, use 'meetup meeting group-by' instead")
+	}
+
+	if err := os.MkdirAll(m.RootDir, 0755); err != nil {
+		return fmt.Errorf("could not create meetup dir: %w", err)
+	}
+
+	if err := doc.save(); err != nil {
+		return err
+	}
+
+	m.metadata = metadata
+
+	return nil
+}
+
+// SetMetadataValue sets the value for key in the metadata file. Changing "group_by" moves all existing meetings to
+// the new layout.
+func (m *Manager) SetMetadataValue(key string, value string) error {
+	keys, err := ParseKey(key)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 1 && keys[0] == "group_by" {
+		gs := GroupStrategy(value)
+
+		if err := (Metadata{GroupBy: gs}).Validate(); err != nil {
+			return err
+		}
+
+		return m.UpdateMeetingGroupBy(gs)
+	}
+
+	node, err := parseValue(value)
+	if err != nil {
+		return err
+	}
+
+	return m.editMetadata(func(doc *yamlDocument) error {
+		return doc.set(keys, node)
+	})
+}
+
+// UnsetMetadataValue removes key from the metadata file.
+func (m *Manager) UnsetMetadataValue(key string) error {
+	keys, err := ParseKey(key)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 1 && keys[0] == "group_by" {
+		return fmt.Errorf("group_by cannot be unset")
+	}
+
+	return m.editMetadata(func(doc *yamlDocument) error {
+		if !doc.unset(keys) {
+			return fmt.Errorf("key '%s' is not set", key)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,165 @@
+package meetup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{}
+
+func init() {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		weekdays[name] = day
+		weekdays[name[:3]] = day
+	}
+}
+
+// truncateDay returns midnight of the day containing t, in t's location.
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Week returns the first (Monday) and last (Sunday) days of the week containing t.
+func Week(t time.Time) (time.Time, time.Time) {
+	offset := (int(t.Weekday()) + 6) % 7
+	start := truncateDay(t).AddDate(0, 0, -offset)
+
+	return start, start.AddDate(0, 0, 6)
+}
+
+// Month returns the first and last days of the month containing t.
+func Month(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+
+	return start, start.AddDate(0, 1, -1)
+}
+
+// addMonths moves t by the given number of months, clamping to the last day of the resulting month rather than
+// overflowing into the month after it.
+func addMonths(t time.Time, months int) time.Time {
+	_, last := Month(time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location()))
+
+	if t.Day() > last.Day() {
+		return last
+	}
+
+	return time.Date(last.Year(), last.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// DateRanges are the names of the relative date ranges accepted by ParseDateRange.
+var DateRanges = []string{"this-week", "last-week", "this-month", "last-month"}
+
+// ParseDateRange returns the first and last days of a named date range relative to now, which must be one of
+// DateRanges. Weeks start on Monday.
+func ParseDateRange(name string, now time.Time) (time.Time, time.Time, error) {
+	switch name {
+	case "this-week":
+		since, until := Week(now)
+		return since, until, nil
+	case "last-week":
+		since, until := Week(now.AddDate(0, 0, -7))
+		return since, until, nil
+	case "this-month":
+		since, until := Month(now)
+		return since, until, nil
+	case "last-month":
+		since, until := Month(now.AddDate(0, 0, -now.Day()))
+		return since, until, nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("unknown date range '%s', expected one of %s", name, strings.Join(DateRanges, ", "))
+	}
+}
+
+// ParseDate parses a date in DateFormat, or a date relative to now. Relative dates may be one of:
+//
+//   - today, yesterday, or tomorrow
+//   - a signed duration in days or weeks (ie +3d, -1w)
+//   - a weekday, optionally prefixed by last, this, or next (ie "last monday", "fri"), where "this" is the day in the
+//     current Monday to Sunday week
+//   - last, this, or next followed by week or month, which moves the date by a week or month, keeping to the last day
+//     of shorter months (ie "last month" on March 31st is the last day of February)
+//
+// The returned time is always midnight in now's location.
+func ParseDate(raw string, now time.Time) (time.Time, error) {
+	raw = strings.ToLower(strings.Join(strings.Fields(raw), " "))
+	today := truncateDay(now)
+
+	if date, err := time.ParseInLocation(DateFormat, raw, now.Location()); err == nil {
+		return date, nil
+	}
+
+	switch raw {
+	case "today", "now":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if strings.HasPrefix(raw, "+") || strings.HasPrefix(raw, "-") {
+		d, err := ParseDuration(raw)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid relative date '%s': %w", raw, err)
+		}
+
+		day := durationUnits["d"]
+		if time.Duration(d)%day != 0 {
+			return time.Time{}, fmt.Errorf("invalid relative date '%s': must be a whole number of days", raw)
+		}
+
+		return today.AddDate(0, 0, int(time.Duration(d)/day)), nil
+	}
+
+	modifier, unit, found := strings.Cut(raw, " ")
+	if !found {
+		modifier, unit = "this", raw
+	}
+
+	var direction int
+
+	switch modifier {
+	case "last":
+		direction = -1
+	case "this":
+		direction = 0
+	case "next":
+		direction = 1
+	default:
+		return time.Time{}, fmt.Errorf("invalid date '%s': expected a date like %s or a relative date", raw, DateFormat)
+	}
+
+	switch unit {
+	case "week":
+		return today.AddDate(0, 0, 7*direction), nil
+	case "month":
+		return addMonths(today, direction), nil
+	}
+
+	weekday, ok := weekdays[unit]
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid date '%s': unknown day or unit '%s'", raw, unit)
+	}
+
+	switch direction {
+	case -1:
+		offset := (int(today.Weekday()) - int(weekday) + 7) % 7
+		if offset == 0 {
+			offset = 7
+		}
+
+		return today.AddDate(0, 0, -offset), nil
+	case 1:
+		offset := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if offset == 0 {
+			offset = 7
+		}
+
+		return today.AddDate(0, 0, offset), nil
+	default:
+		start, _ := Week(today)
+		return start.AddDate(0, 0, (int(weekday)+6)%7), nil
+	}
+}
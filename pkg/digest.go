@@ -0,0 +1,151 @@
+package meetup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+type DigestOptions struct {
+	// Since and Until are the first and last days of the digest. Until defaults to today.
+	Since time.Time
+	Until time.Time
+}
+
+// Digest summarizes the meetings held and the tasks opened and closed in a period.
+type Digest struct {
+	// Since and Until are the first and last days of the digest, in DateFormat.
+	Since string `json:"since"`
+	Until string `json:"until"`
+
+	Meetings []Meeting `json:"meetings"`
+
+	// Opened are the tasks first seen in the period, and Closed are the tasks first seen done in the period. Recurring
+	// tasks which are seen open again after being done are opened again. Tasks are taken from the latest meeting in the
+	// period they appear in.
+	Opened []Task `json:"opened"`
+	Closed []Task `json:"closed"`
+}
+
+// Digest builds a digest of the meetings matching the query. Whether a task is new or newly closed is decided using
+// every meeting in its domain, including archived meetings and meetings before the period.
+func (m *Manager) Digest(query MeetingQuery, opts DigestOptions) (Digest, error) {
+	if opts.Until.IsZero() {
+		opts.Until = time.Now()
+	}
+
+	query.Since, query.Until = truncateDay(opts.Since), truncateDay(opts.Until)
+
+	digest := Digest{
+		Since:  query.Since.Format(DateFormat),
+		Until:  query.Until.Format(DateFormat),
+		Opened: []Task{},
+		Closed: []Task{},
+	}
+
+	meetings, err := m.ListMeetings(query)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	digest.Meetings = append([]Meeting{}, meetings...)
+
+	tasks, err := m.Tasks(TaskQuery{Meeting: query})
+	if err != nil {
+		return Digest{}, err
+	}
+
+	tasks = latestTasks(tasks)
+
+	histories, err := m.taskHistories(context.Background(), taskDomains(tasks))
+	if err != nil {
+		return Digest{}, err
+	}
+
+	for _, task := range tasks {
+		run := histories[identify(task)].at(task.Meeting.Date)
+
+		if run.opened >= digest.Since {
+			digest.Opened = append(digest.Opened, task)
+		}
+
+		if task.State.Done && run.closed >= digest.Since {
+			digest.Closed = append(digest.Closed, task)
+		}
+	}
+
+	return digest, nil
+}
+
+// WriteMarkdown writes the digest as a Markdown summary. Meetings are listed per domain with repeated meetings counted
+// rather than listed, and tasks are listed with the meeting they were last seen in.
+func (d Digest) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Summary for %s to %s\n\n", d.Since, d.Until)
+	fmt.Fprintf(&b, "### Meetings (%d)\n\n", len(d.Meetings))
+
+	if len(d.Meetings) == 0 {
+		b.WriteString("No meetings.\n")
+	}
+
+	var domains []string
+	counts := map[string]map[string]int{}
+
+	for _, meeting := range d.Meetings {
+		if counts[meeting.Domain] == nil {
+			domains = append(domains, meeting.Domain)
+			counts[meeting.Domain] = map[string]int{}
+		}
+
+		counts[meeting.Domain][meeting.Name]++
+	}
+
+	slices.Sort(domains)
+
+	for _, domain := range domains {
+		var names []string
+		for name := range counts[domain] {
+			names = append(names, name)
+		}
+
+		slices.Sort(names)
+
+		for i, name := range names {
+			if count := counts[domain][name]; count > 1 {
+				names[i] = fmt.Sprintf("%s (%d)", name, count)
+			}
+		}
+
+		fmt.Fprintf(&b, "- **%s**: %s\n", domain, strings.Join(names, ", "))
+	}
+
+	writeTasks := func(heading string, tasks []Task) {
+		fmt.Fprintf(&b, "\n### %s (%d)\n\n", heading, len(tasks))
+
+		if len(tasks) == 0 {
+			b.WriteString("No tasks.\n")
+		}
+
+		for _, task := range tasks {
+			checkbox := " "
+			if task.State.Done {
+				checkbox = "x"
+			}
+
+			fmt.Fprintf(&b, "- [%s] %s (%s %s, %s)\n", checkbox, task.Description, task.Meeting.Domain, task.Meeting.FullName(), task.Meeting.Date)
+		}
+	}
+
+	writeTasks("Opened", d.Opened)
+	writeTasks("Closed", d.Closed)
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("could not write digest: %w", err)
+	}
+
+	return nil
+}
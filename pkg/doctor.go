@@ -0,0 +1,502 @@
+package meetup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// QuarantineDirName is the directory malformed files are moved to by Repair.
+	QuarantineDirName = ".quarantine"
+
+	// AssetsDirSuffix is appended to a meeting's path to form the directory holding files associated with it.
+	AssetsDirSuffix = ".assets"
+)
+
+type ProblemKind string
+
+const (
+	ProblemMalformedPath      ProblemKind = "malformed-path"
+	ProblemMissingExtension   ProblemKind = "missing-extension"
+	ProblemInvalidDate        ProblemKind = "invalid-date"
+	ProblemEmptyDir           ProblemKind = "empty-dir"
+	ProblemLayout             ProblemKind = "layout"
+	ProblemTemplate           ProblemKind = "template"
+	ProblemMetadata           ProblemKind = "metadata"
+	ProblemDeprecatedMetadata ProblemKind = "deprecated-metadata"
+)
+
+// Problem is an issue found in the meetup directory by Diagnose.
+type Problem struct {
+	Kind ProblemKind
+
+	// Path is the path of the offending file relative to the meetup directory.
+	Path    string
+	Message string
+
+	// meeting is the meeting the file most likely belongs to, for layout problems.
+	meeting Meeting
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("[%s] %s: %s", p.Kind, p.Path, p.Message)
+}
+
+// isHidden reports whether the entry should be ignored when looking for meetings. This includes the metadata file, the
+// template, quarantine, and other dot directories, and meeting assets directories.
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasSuffix(name, AssetsDirSuffix)
+}
+
+func isValidDate(date string) bool {
+	_, err := time.Parse(DateFormat, date)
+	return err == nil
+}
+
+// parseMeetingPath parses the meeting at the given path relative to the meetup directory, and verifies its date. The
+// extension configured for the meeting's domain is stripped from its name. Files without the extension are still
+// meetings, since they may have been created before the extension was configured.
+func (m *Manager) parseMeetingPath(gs GroupStrategy, rel string) (Meeting, error) {
+	meeting, err := MeetingFromPath(gs, rel)
+	if err != nil {
+		return Meeting{}, err
+	}
+
+	if !isValidDate(meeting.Date) {
+		return Meeting{}, fmt.Errorf("invalid date '%s'", meeting.Date)
+	}
+
+	if extension := m.DomainConfig(meeting.Domain).Extension; extension != "" && strings.HasSuffix(rel, extension) {
+		return MeetingFromPath(gs, strings.TrimSuffix(rel, extension))
+	}
+
+	return meeting, nil
+}
+
+func otherGroupStrategy(gs GroupStrategy) GroupStrategy {
+	if gs == GroupByDate {
+		return GroupByDomain
+	}
+
+	return GroupByDate
+}
+
+// Diagnose walks the meetup directory looking for problems which may cause meetings to be missed or misparsed.
+func (m *Manager) Diagnose() ([]Problem, error) {
+	var problems []Problem
+
+	metadataProblems, err := m.diagnoseMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	problems = append(problems, metadataProblems...)
+
+	templateProblems, err := m.diagnoseTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	problems = append(problems, templateProblems...)
+
+	// directories which contain at least one file somewhere beneath them
+	populated := map[string]bool{}
+	var dirs []string
+
+	err = filepath.WalkDir(m.RootDir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if p == m.RootDir && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if p == m.RootDir {
+			return nil
+		}
+
+		if isHidden(entry.Name()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		rel := strings.TrimPrefix(p, m.RootDir+string(os.PathSeparator))
+
+		if entry.IsDir() {
+			dirs = append(dirs, rel)
+			return nil
+		}
+
+		for dir := path.Dir(rel); dir != "."; dir = path.Dir(dir) {
+			populated[dir] = true
+		}
+
+		if problem, found := m.diagnoseMeetingPath(rel); found {
+			problems = append(problems, problem)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not walk meetup dir: %w", err)
+	}
+
+	for _, dir := range dirs {
+		// only report the top-most empty directory, since removing it removes all of its children
+		if !populated[dir] && (path.Dir(dir) == "." || populated[path.Dir(dir)]) {
+			problems = append(problems, Problem{
+				Kind:    ProblemEmptyDir,
+				Path:    dir,
+				Message: "directory does not contain any meetings",
+			})
+		}
+	}
+
+	return problems, nil
+}
+
+func (m *Manager) diagnoseMeetingPath(rel string) (Problem, bool) {
+	_, err := MeetingFromPath(m.metadata.GroupBy, rel)
+	if err != nil {
+		return Problem{
+			Kind:    ProblemMalformedPath,
+			Path:    rel,
+			Message: err.Error(),
+		}, true
+	}
+
+	meeting, err := m.parseMeetingPath(m.metadata.GroupBy, rel)
+	if err == nil {
+		if extension := m.DomainConfig(meeting.Domain).Extension; !strings.HasSuffix(rel, extension) {
+			return Problem{
+				Kind:    ProblemMissingExtension,
+				Path:    rel,
+				Message: fmt.Sprintf("meeting is missing the extension '%s' configured for domain '%s'", extension, meeting.Domain),
+			}, true
+		}
+
+		return Problem{}, false
+	}
+
+	if meeting, err := m.parseMeetingPath(otherGroupStrategy(m.metadata.GroupBy), rel); err == nil {
+		return Problem{
+			Kind:    ProblemLayout,
+			Path:    rel,
+			Message: fmt.Sprintf("meeting is laid out for group_by '%s', but group_by is '%s'", otherGroupStrategy(m.metadata.GroupBy), m.metadata.GroupBy),
+			meeting: meeting,
+		}, true
+	}
+
+	meeting, _ = MeetingFromPath(m.metadata.GroupBy, rel)
+
+	if isValidDate(meeting.Date) {
+		return Problem{
+			Kind:    ProblemMalformedPath,
+			Path:    rel,
+			Message: err.Error(),
+		}, true
+	}
+
+	return Problem{
+		Kind:    ProblemInvalidDate,
+		Path:    rel,
+		Message: fmt.Sprintf("'%s' is not a valid date (expected %s)", meeting.Date, DateFormat),
+	}, true
+}
+
+func (m *Manager) diagnoseTemplates() ([]Problem, error) {
+	dir := path.Join(m.RootDir, TemplateDirName)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not read templates: %w", err)
+	}
+
+	var problems []Problem
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		if _, err := template.ParseFiles(path.Join(dir, entry.Name())); err != nil {
+			problems = append(problems, Problem{
+				Kind:    ProblemTemplate,
+				Path:    path.Join(TemplateDirName, entry.Name()),
+				Message: err.Error(),
+			})
+		}
+	}
+
+	for i, rule := range m.metadata.TemplateRules {
+		if !m.TemplateExists(rule.Template) {
+			problems = append(problems, Problem{
+				Kind:    ProblemTemplate,
+				Path:    MetadataFilename,
+				Message: fmt.Sprintf("template rule %d references missing template '%s'", i, rule.Template),
+			})
+		}
+	}
+
+	return problems, nil
+}
+
+func (m *Manager) diagnoseMetadata() ([]Problem, error) {
+	data, err := os.ReadFile(m.MetadataPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not read metadata: %w", err)
+	}
+
+	raw := map[string]any{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return []Problem{{Kind: ProblemMetadata, Path: MetadataFilename, Message: err.Error()}}, nil
+	}
+
+	var problems []Problem
+
+	known := []string{"group_by", "template_rules", "domains", "trash_retention", "archive_format", "views", "task_states", "rollups"}
+
+	for key := range raw {
+		switch {
+		case key == "domain_templates":
+			problems = append(problems, Problem{
+				Kind:    ProblemDeprecatedMetadata,
+				Path:    MetadataFilename,
+				Message: "'domain_templates' has been replaced by 'template_rules'",
+			})
+		case !slices.Contains(known, key):
+			problems = append(problems, Problem{
+				Kind:    ProblemMetadata,
+				Path:    MetadataFilename,
+				Message: fmt.Sprintf("unknown key '%s'", key),
+			})
+		}
+	}
+
+	// catch unknown keys nested within known keys
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var metadata struct {
+		Metadata        `yaml:",inline"`
+		DomainTemplates map[string]string `yaml:"domain_templates"`
+	}
+
+	if err := decoder.Decode(&metadata); err != nil && !errors.Is(err, io.EOF) && len(problems) == 0 {
+		problems = append(problems, Problem{
+			Kind:    ProblemMetadata,
+			Path:    MetadataFilename,
+			Message: err.Error(),
+		})
+	}
+
+	slices.SortFunc(problems, func(a, b Problem) int {
+		return strings.Compare(a.Message, b.Message)
+	})
+
+	return problems, nil
+}
+
+// Repair attempts to fix each of the given problems, returning an error for each problem which could not be fixed.
+// Malformed meetings are moved into the quarantine directory, meetings using the wrong layout are moved to the correct
+// location, meetings missing their domain's extension are renamed in place, empty directories are removed, and
+// deprecated or unknown metadata is migrated or dropped. Template problems cannot be repaired automatically.
+func (m *Manager) Repair(problems []Problem) []error {
+	errs := make([]error, len(problems))
+	metadataFixed := false
+
+	for i, problem := range problems {
+		switch problem.Kind {
+		case ProblemMalformedPath, ProblemInvalidDate:
+			errs[i] = m.quarantine(problem.Path)
+		case ProblemLayout:
+			errs[i] = m.relocate(problem)
+		case ProblemMissingExtension:
+			errs[i] = m.addExtension(problem.Path)
+		case ProblemEmptyDir:
+			errs[i] = m.removeEmptyDir(problem.Path)
+		case ProblemMetadata, ProblemDeprecatedMetadata:
+			if metadataFixed {
+				continue
+			}
+
+			errs[i] = m.repairMetadata()
+			metadataFixed = true
+		default:
+			errs[i] = fmt.Errorf("cannot automatically repair %s problems", problem.Kind)
+		}
+	}
+
+	return errs
+}
+
+func (m *Manager) quarantine(rel string) error {
+	dst := path.Join(m.RootDir, QuarantineDirName, rel)
+
+	if err := os.MkdirAll(path.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("could not create quarantine directory: %w", err)
+	}
+
+	if err := os.Rename(path.Join(m.RootDir, rel), dst); err != nil {
+		return fmt.Errorf("could not quarantine file: %w", err)
+	}
+
+	_, err := m.pruneDirs(path.Dir(path.Join(m.RootDir, rel)))
+
+	return err
+}
+
+func (m *Manager) relocate(problem Problem) error {
+	src := path.Join(m.RootDir, problem.Path)
+	dst := problem.meeting.GetPath(m.RootDir, m.metadata.GroupBy) + path.Ext(problem.Path)
+
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("could not move meeting: '%s' already exists", strings.TrimPrefix(dst, m.RootDir+"/"))
+	}
+
+	if err := os.MkdirAll(path.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("could not create meeting directory: %w", err)
+	}
+
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("could not move meeting: %w", err)
+	}
+
+	_, err := m.pruneDirs(path.Dir(src))
+
+	return err
+}
+
+// addExtension renames the meeting at rel to include the extension configured for its domain.
+func (m *Manager) addExtension(rel string) error {
+	meeting, err := m.parseMeetingPath(m.metadata.GroupBy, rel)
+	if err != nil {
+		return err
+	}
+
+	src := path.Join(m.RootDir, rel)
+	dst := src + m.DomainConfig(meeting.Domain).Extension
+
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("could not rename meeting: '%s' already exists", strings.TrimPrefix(dst, m.RootDir+"/"))
+	}
+
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("could not rename meeting: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Manager) removeEmptyDir(rel string) error {
+	dir := path.Join(m.RootDir, rel)
+
+	err := filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() {
+			return fmt.Errorf("directory is not empty: found '%s'", strings.TrimPrefix(p, m.RootDir+"/"))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("could not remove directory: %w", err)
+	}
+
+	return nil
+}
+
+// repairMetadata rewrites the metadata file with only known keys, converting any deprecated domain_templates into
+// template rules. The original file is kept with a ".bak" suffix.
+func (m *Manager) repairMetadata() error {
+	data, err := os.ReadFile(m.MetadataPath())
+	if err != nil {
+		return fmt.Errorf("could not read metadata: %w", err)
+	}
+
+	var legacy struct {
+		DomainTemplates map[string]string `yaml:"domain_templates"`
+	}
+
+	if err := yaml.Unmarshal(data, &legacy); err != nil {
+		return fmt.Errorf("could not parse metadata: %w", err)
+	}
+
+	domains := make([]string, 0, len(legacy.DomainTemplates))
+	for domain := range legacy.DomainTemplates {
+		domains = append(domains, domain)
+	}
+
+	slices.Sort(domains)
+
+	for _, domain := range domains {
+		m.metadata.TemplateRules = append(m.metadata.TemplateRules, TemplateRule{
+			Domain:   domain,
+			Template: legacy.DomainTemplates[domain],
+		})
+	}
+
+	if err := m.metadata.compileTemplateRules(); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(m.MetadataPath()+".bak", data, 0644); err != nil {
+		return fmt.Errorf("could not back up metadata: %w", err)
+	}
+
+	return m.SyncMetadata()
+}
+
+// pruneDirs removes dir and each of its parents up to the meetup directory until a non-empty directory is found,
+// returning the removed directories.
+func (m *Manager) pruneDirs(dir string) ([]string, error) {
+	var removed []string
+
+	for ; dir != m.RootDir && strings.HasPrefix(dir, m.RootDir); dir = path.Dir(dir) {
+		err := os.Remove(dir)
+		if err != nil {
+			pathErr := err.(*os.PathError)
+			if pathErr.Err == syscall.ENOTEMPTY || pathErr.Err == syscall.EEXIST {
+				break
+			}
+
+			return removed, fmt.Errorf("could not remove directory: %w", err)
+		}
+
+		removed = append(removed, dir)
+	}
+
+	return removed, nil
+}
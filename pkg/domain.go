@@ -0,0 +1,468 @@
+package meetup
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gobwas/glob"
+)
+
+// DomainConfig overrides the manager configuration for all meetings in a domain and its subdomains. Unset fields are
+// inherited from the parent domain.
+type DomainConfig struct {
+	Editor    []string `yaml:"editor,omitempty"`
+	Extension string   `yaml:"extension,omitempty"`
+	Template  string   `yaml:"template,omitempty"`
+
+	// TaskStates override the task states with the same name, and add new states.
+	TaskStates []TaskState `yaml:"task_states,omitempty"`
+
+	// Retention is the maximum age of meetings in the domain before they are considered expired, or 0 to keep meetings
+	// forever.
+	Retention Duration `yaml:"retention,omitempty"`
+}
+
+// merge overrides the fields of c with the set fields of other.
+func (c DomainConfig) merge(other DomainConfig) DomainConfig {
+	if len(other.Editor) > 0 {
+		c.Editor = other.Editor
+	}
+
+	if other.Extension != "" {
+		c.Extension = other.Extension
+	}
+
+	if other.Template != "" {
+		c.Template = other.Template
+	}
+
+	if len(other.TaskStates) > 0 {
+		c.TaskStates = mergeTaskStates(c.TaskStates, other.TaskStates)
+	}
+
+	if other.Retention != 0 {
+		c.Retention = other.Retention
+	}
+
+	return c
+}
+
+// Validate checks that the domain configuration is usable.
+func (c DomainConfig) Validate() error {
+	if c.Extension != "" && (!strings.HasPrefix(c.Extension, ".") || strings.ContainsAny(c.Extension[1:], "./")) {
+		return fmt.Errorf("invalid extension '%s': must be a single '.' followed by a suffix", c.Extension)
+	}
+
+	if c.Retention < 0 {
+		return fmt.Errorf("retention cannot be negative")
+	}
+
+	if err := validateTaskStates(c.TaskStates); err != nil {
+		return fmt.Errorf("invalid task states: %w", err)
+	}
+
+	return nil
+}
+
+// DomainConfig resolves the effective configuration for a domain by applying the overrides of each of its parent
+// domains in turn, starting with the top-most component (ie "work", then "work.product", then "work.product.team").
+func (m *Manager) DomainConfig(domain string) DomainConfig {
+	config := DomainConfig{
+		Editor: m.Editor,
+	}
+
+	if domain == "" {
+		return config
+	}
+
+	components := strings.Split(domain, ".")
+
+	for i := range components {
+		if override, found := m.metadata.Domains[strings.Join(components[:i+1], ".")]; found {
+			config = config.merge(override)
+		}
+	}
+
+	return config
+}
+
+// pathForMeeting returns the path to the meeting file, including the extension configured for its domain unless the
+// meeting was created before the extension was configured.
+func (m *Manager) pathForMeeting(meeting Meeting) string {
+	return withExtension(meeting.GetPath(m.RootDir, m.metadata.GroupBy), m.DomainConfig(meeting.Domain).Extension, "")
+}
+
+// withExtension returns base followed by the extension and suffix, or base followed by only the suffix if only that
+// file exists. This finds meetings created before an extension was configured for their domain, until they are renamed
+// by Repair.
+func withExtension(base string, extension string, suffix string) string {
+	if extension == "" {
+		return base + suffix
+	}
+
+	if _, err := os.Stat(base + extension + suffix); os.IsNotExist(err) {
+		if info, err := os.Stat(base + suffix); err == nil && !info.IsDir() {
+			return base + suffix
+		}
+	}
+
+	return base + extension + suffix
+}
+
+// assetsPath returns the path to the directory holding files associated with the meeting (ie images).
+func (m *Manager) assetsPath(meeting Meeting) string {
+	return meeting.GetPath(m.RootDir, m.metadata.GroupBy) + AssetsDirSuffix
+}
+
+// ExpiredMeetings returns all meetings which are older than the retention configured for their domain.
+func (m *Manager) ExpiredMeetings(now time.Time) ([]Meeting, error) {
+	meetings, err := m.ListMeetings(MeetingQuery{
+		Name:   glob.MustCompile("*"),
+		Domain: glob.MustCompile("*"),
+		Date:   glob.MustCompile("*"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []Meeting
+
+	for _, meeting := range meetings {
+		retention := m.DomainConfig(meeting.Domain).Retention
+		if retention == 0 {
+			continue
+		}
+
+		date, err := time.Parse(DateFormat, meeting.Date)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse date for meeting '%s': %w", meeting, err)
+		}
+
+		if now.Sub(date) > time.Duration(retention) {
+			expired = append(expired, meeting)
+		}
+	}
+
+	return expired, nil
+}
+
+// ValidateDomain checks that the domain can be used as a path in the meetup directory. Domains are dot separated
+// components which may not be empty or contain path separators.
+func ValidateDomain(domain string) error {
+	for _, component := range strings.Split(domain, ".") {
+		if component == "" || strings.ContainsAny(component, `/\`) {
+			return fmt.Errorf("invalid domain '%s'", domain)
+		}
+	}
+
+	return nil
+}
+
+// InDomain reports whether domain is the given parent domain or one of its subdomains.
+func InDomain(domain string, parent string) bool {
+	return domain == parent || strings.HasPrefix(domain, parent+".")
+}
+
+// DomainNode summarizes the meetings in a domain and its subdomains.
+type DomainNode struct {
+	// Name is the last component of the domain.
+	Name   string
+	Domain string
+
+	// Meetings is the number of meetings directly in the domain, and Total includes all subdomains.
+	Meetings int
+	Total    int
+
+	// FirstDate and LastDate are the dates of the earliest and latest meetings in the domain or its subdomains.
+	FirstDate string
+	LastDate  string
+
+	Children []*DomainNode
+}
+
+func (n *DomainNode) child(name string) *DomainNode {
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+
+	domain := name
+	if n.Domain != "" {
+		domain = n.Domain + "." + name
+	}
+
+	child := &DomainNode{Name: name, Domain: domain}
+	n.Children = append(n.Children, child)
+
+	return child
+}
+
+func (n *DomainNode) add(date string) {
+	n.Total++
+
+	if n.FirstDate == "" || date < n.FirstDate {
+		n.FirstDate = date
+	}
+
+	if n.LastDate == "" || date > n.LastDate {
+		n.LastDate = date
+	}
+}
+
+func (n *DomainNode) sort() {
+	slices.SortFunc(n.Children, func(a, b *DomainNode) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for _, child := range n.Children {
+		child.sort()
+	}
+}
+
+// DomainTree builds a tree of every domain containing meetings. The returned root node has an empty domain and
+// summarizes all meetings.
+func (m *Manager) DomainTree() (*DomainNode, error) {
+	meetings, err := m.ListMeetings(MeetingQuery{
+		Name:   glob.MustCompile("*"),
+		Domain: glob.MustCompile("*"),
+		Date:   glob.MustCompile("*"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	root := &DomainNode{}
+
+	for _, meeting := range meetings {
+		node := root
+		node.add(meeting.Date)
+
+		for _, component := range strings.Split(meeting.Domain, ".") {
+			node = node.child(component)
+			node.add(meeting.Date)
+		}
+
+		node.Meetings++
+	}
+
+	root.sort()
+
+	return root, nil
+}
+
+// DomainMeetings returns every meeting in the domain or its subdomains.
+func (m *Manager) DomainMeetings(domain string) ([]Meeting, error) {
+	meetings, err := m.ListMeetings(MeetingQuery{
+		Name:   glob.MustCompile("*"),
+		Domain: glob.MustCompile("*"),
+		Date:   glob.MustCompile("*"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(meetings, func(meeting Meeting) bool {
+		return !InDomain(meeting.Domain, domain)
+	}), nil
+}
+
+// moveDomain moves every meeting from one domain subtree to another. Unless merge is set the destination domain must
+// not contain any meetings. All destinations are checked before any meeting is moved. Links are updated once all
+// meetings have been moved, and the domain's configuration, template rules, views, and rollups are updated to refer to
+// the new domain.
+func (m *Manager) moveDomain(from string, to string, merge bool) ([]Meeting, error) {
+	if err := ValidateDomain(from); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateDomain(to); err != nil {
+		return nil, err
+	}
+
+	if from == to || InDomain(to, from) {
+		return nil, fmt.Errorf("cannot move domain '%s' into itself", from)
+	}
+
+	meetings, err := m.DomainMeetings(from)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(meetings) == 0 {
+		return nil, fmt.Errorf("domain '%s' does not contain any meetings", from)
+	}
+
+	existing, err := m.DomainMeetings(to)
+	if err != nil {
+		return nil, err
+	}
+
+	// when moving a subdomain up into its parent its own meetings are not already in the destination
+	existing = slices.DeleteFunc(existing, func(meeting Meeting) bool {
+		return InDomain(meeting.Domain, from)
+	})
+
+	if len(existing) > 0 && !merge {
+		return nil, fmt.Errorf("domain '%s' already contains %d meeting(s), merge the domains instead", to, len(existing))
+	}
+
+	// meetings are moved to where they belong with the metadata for the new domain, ie its configured extension
+	renamed := *m
+	if err := renamed.renameDomainMetadata(from, to); err != nil {
+		return nil, err
+	}
+
+	targets := make([]Meeting, len(meetings))
+
+	for i, meeting := range meetings {
+		targets[i] = meeting
+		targets[i].Domain = to + strings.TrimPrefix(meeting.Domain, from)
+
+		if renamed.MeetingExists(targets[i]) {
+			return nil, fmt.Errorf("cannot move '%s': '%s' already exists", meeting, targets[i])
+		}
+	}
+
+	var moves []linkMove
+	var moveErr error
+
+	for i, meeting := range meetings {
+		move, err := m.moveMeeting(meeting, targets[i], renamed.pathForMeeting(targets[i]), MoveOptions{})
+		if err != nil {
+			moveErr = err
+			break
+		}
+
+		if move != nil {
+			moves = append(moves, *move)
+		}
+	}
+
+	if len(moves) == 0 {
+		return nil, moveErr
+	}
+
+	if err := m.recordMoves(moves); err != nil {
+		return nil, errors.Join(moveErr, err)
+	}
+
+	m.metadata = renamed.metadata
+
+	if err := m.SyncMetadata(); err != nil {
+		return nil, errors.Join(moveErr, fmt.Errorf("could not sync metadata: %w", err))
+	}
+
+	// links to the meetings which were moved are updated even if a later move failed
+	if err := m.rewriteLinksAfterMoves(moves); err != nil {
+		return nil, errors.Join(moveErr, fmt.Errorf("could not update links: %w", err))
+	}
+
+	if moveErr != nil {
+		return nil, moveErr
+	}
+
+	return targets, nil
+}
+
+// renameDomainMetadata updates the metadata which refers to the from domain or its subdomains to refer to the to domain.
+// Domain configuration already set for a destination domain is kept over the configuration being moved into it.
+func (m *Manager) renameDomainMetadata(from string, to string) error {
+	rename := func(domain string) string {
+		if !InDomain(domain, from) {
+			return domain
+		}
+
+		return to + strings.TrimPrefix(domain, from)
+	}
+
+	if m.metadata.Domains != nil {
+		domains := make(map[string]DomainConfig, len(m.metadata.Domains))
+
+		for domain, config := range m.metadata.Domains {
+			if !InDomain(domain, from) {
+				domains[domain] = config
+			}
+		}
+
+		for domain, config := range m.metadata.Domains {
+			if _, found := domains[rename(domain)]; InDomain(domain, from) && !found {
+				domains[rename(domain)] = config
+			}
+		}
+
+		m.metadata.Domains = domains
+	}
+
+	// the rules, views, and rollups may be shared with the config the manager was created with
+	m.metadata.TemplateRules = slices.Clone(m.metadata.TemplateRules)
+	for i, rule := range m.metadata.TemplateRules {
+		m.metadata.TemplateRules[i].Domain = rename(rule.Domain)
+	}
+
+	if err := m.metadata.compileTemplateRules(); err != nil {
+		return err
+	}
+
+	if m.metadata.Views != nil {
+		m.metadata.Views = maps.Clone(m.metadata.Views)
+		for name, view := range m.metadata.Views {
+			view.Query = renameQueryDomain(view.Query, from, to)
+			m.metadata.Views[name] = view
+		}
+	}
+
+	if m.metadata.Rollups != nil {
+		m.metadata.Rollups = maps.Clone(m.metadata.Rollups)
+		for period, rollup := range m.metadata.Rollups {
+			rollup.Domain = rename(rollup.Domain)
+			m.metadata.Rollups[period] = rollup
+		}
+	}
+
+	return nil
+}
+
+// RenameDomain moves every meeting in a domain and its subdomains to a new domain, which must not already contain any
+// meetings. The moved meetings are returned.
+func (m *Manager) RenameDomain(from string, to string) ([]Meeting, error) {
+	return m.moveDomain(from, to, false)
+}
+
+// MergeDomain moves every meeting in a domain and its subdomains into another, possibly non-empty, domain. Merging
+// fails before moving anything if any meeting would overwrite an existing meeting.
+func (m *Manager) MergeDomain(from string, to string) ([]Meeting, error) {
+	return m.moveDomain(from, to, true)
+}
+
+// RemoveDomain moves every meeting in a domain to the trash. Meetings in subdomains are only removed if recursive is
+// set, and are otherwise reported as an error.
+func (m *Manager) RemoveDomain(domain string, recursive bool) ([]Meeting, error) {
+	meetings, err := m.DomainMeetings(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(meetings) == 0 {
+		return nil, fmt.Errorf("domain '%s' does not contain any meetings", domain)
+	}
+
+	if !recursive {
+		for _, meeting := range meetings {
+			if meeting.Domain != domain {
+				return nil, fmt.Errorf("domain '%s' has subdomains (ie '%s'), remove recursively to include them", domain, meeting.Domain)
+			}
+		}
+	}
+
+	if err := m.RemoveMeetings(meetings); err != nil {
+		return nil, err
+	}
+
+	return meetings, nil
+}
@@ -1,23 +1,250 @@
 package meetup
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
 type JobFunc func()
 
-// JobQueue is a mindnummingly simple job queue, to support our minimal needs.
+// JobMode controls how a JobQueue handles failing jobs.
+type JobMode int
+
+const (
+	// CollectAll runs every job regardless of failures, and Wait returns every error.
+	CollectAll JobMode = iota
+
+	// FailFast cancels the queue's context after the first failure, so that jobs which have not started yet are
+	// skipped and running jobs can stop early.
+	FailFast
+)
+
+// JobPanicError is returned for jobs which panic.
+type JobPanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *JobPanicError) Error() string {
+	return fmt.Sprintf("job panicked: %v", e.Value)
+}
+
+// JobMetrics counts the jobs submitted to a JobQueue. Queued jobs are waiting for a free slot, and completed jobs
+// include failed jobs. Since submitting blocks until there is a free slot, Queued is at most the number of goroutines
+// currently submitting jobs.
+type JobMetrics struct {
+	Queued    int
+	Running   int
+	Completed int
+	Failed    int
+	Skipped   int
+}
+
+// JobQueue runs jobs concurrently, with at most n running at once. Submitting a job blocks until there is room for it
+// to run.
 type JobQueue struct {
-	jobChan chan *JobFunc
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+	mode   JobMode
+
+	slots chan struct{}
+	wg    sync.WaitGroup
+
+	mu      sync.Mutex
+	errs    []error
+	metrics JobMetrics
 }
 
+// NewJobQueue creates a queue which runs at most n jobs at once and collects every error.
 func NewJobQueue(n int) *JobQueue {
+	return NewJobQueueContext(context.Background(), n, CollectAll)
+}
+
+// NewJobQueueContext creates a queue which runs at most n jobs at once. Once ctx is done, jobs which have not started
+// are skipped.
+func NewJobQueueContext(ctx context.Context, n int, mode JobMode) *JobQueue {
+	if n < 1 {
+		n = 1
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+
 	return &JobQueue{
-		jobChan: make(chan *JobFunc, n),
+		parent: ctx,
+		ctx:    jobCtx,
+		cancel: cancel,
+		mode:   mode,
+		slots:  make(chan struct{}, n),
 	}
 }
 
+// Context returns the context passed to jobs, which is cancelled after the first failure in FailFast mode.
+func (jq *JobQueue) Context() context.Context {
+	return jq.ctx
+}
+
+// Run runs fn once there is room in the queue. Panics are recovered and returned by Wait.
 func (jq *JobQueue) Run(fn JobFunc) {
-	jq.jobChan <- &fn
+	jq.Go(func(context.Context) error {
+		fn()
+		return nil
+	})
+}
+
+// Go runs fn once there is room in the queue, or skips it if the queue's context is done first. Errors and recovered
+// panics are returned by Wait. Go reports whether the job was started.
+func (jq *JobQueue) Go(fn func(ctx context.Context) error) bool {
+	jq.update(func(metrics *JobMetrics) { metrics.Queued++ })
+
+	select {
+	case jq.slots <- struct{}{}:
+	case <-jq.ctx.Done():
+		jq.update(func(metrics *JobMetrics) {
+			metrics.Queued--
+			metrics.Skipped++
+		})
+
+		return false
+	}
+
+	// the context may be done even if a slot was free
+	if jq.ctx.Err() != nil {
+		<-jq.slots
+		jq.update(func(metrics *JobMetrics) {
+			metrics.Queued--
+			metrics.Skipped++
+		})
+
+		return false
+	}
+
+	jq.update(func(metrics *JobMetrics) {
+		metrics.Queued--
+		metrics.Running++
+	})
+
+	jq.wg.Add(1)
 
 	go func() {
-		fn()
-		<-jq.jobChan
+		defer jq.wg.Done()
+		defer func() { <-jq.slots }()
+
+		_, err := callJob(jq.ctx, func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, fn(ctx)
+		})
+
+		jq.update(func(metrics *JobMetrics) {
+			metrics.Running--
+			metrics.Completed++
+
+			if err != nil {
+				metrics.Failed++
+			}
+		})
+
+		if err != nil {
+			jq.fail(err)
+		}
+	}()
+
+	return true
+}
+
+// callJob runs fn, converting panics into a JobPanicError.
+func callJob[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) (value T, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = &JobPanicError{Value: recovered, Stack: debug.Stack()}
+		}
 	}()
+
+	return fn(ctx)
+}
+
+func (jq *JobQueue) fail(err error) {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	// jobs stopped by our own cancellation only repeat the error which caused it
+	if jq.mode == FailFast && len(jq.errs) > 0 && errors.Is(err, context.Canceled) {
+		return
+	}
+
+	jq.errs = append(jq.errs, err)
+
+	if jq.mode == FailFast {
+		jq.cancel()
+	}
+}
+
+func (jq *JobQueue) update(fn func(metrics *JobMetrics)) {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	fn(&jq.metrics)
+}
+
+// Metrics returns the current job counts.
+func (jq *JobQueue) Metrics() JobMetrics {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	return jq.metrics
+}
+
+// Wait blocks until every started job has finished, and returns the errors of the failed jobs joined in the order
+// they failed. If jobs were skipped because the parent context is done, its error is included first. Jobs must not be
+// submitted while waiting, and Wait releases the queue's context so later jobs are skipped.
+func (jq *JobQueue) Wait() error {
+	jq.wg.Wait()
+	jq.cancel()
+
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	errs := jq.errs
+
+	if err := jq.parent.Err(); err != nil && jq.metrics.Skipped > 0 {
+		errs = append([]error{err}, errs...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// JobResult is the eventual value of a job submitted with Submit.
+type JobResult[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Get blocks until the job has finished or been skipped, and returns its value and error. Skipped jobs return the
+// queue's context error.
+func (r *JobResult[T]) Get() (T, error) {
+	<-r.done
+	return r.value, r.err
+}
+
+// Submit runs a job returning a value on the queue like JobQueue.Go. The job's error is also returned by Wait.
+func Submit[T any](jq *JobQueue, fn func(ctx context.Context) (T, error)) *JobResult[T] {
+	result := &JobResult[T]{done: make(chan struct{})}
+
+	started := jq.Go(func(ctx context.Context) error {
+		defer close(result.done)
+
+		result.value, result.err = callJob(ctx, fn)
+
+		return result.err
+	})
+
+	if !started {
+		result.err = jq.ctx.Err()
+		close(result.done)
+	}
+
+	return result
 }
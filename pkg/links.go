@@ -0,0 +1,247 @@
+package meetup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+var (
+	// markdownLinkRegex matches inline markdown links and images, capturing the link target.
+	markdownLinkRegex = regexp.MustCompile(`(!?\[[^\]]*\]\()([^)\s]+)((?:\s+"[^"]*")?\))`)
+
+	linkSchemeRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
+// isRelativeLink reports whether the link target refers to a local file by relative path.
+func isRelativeLink(target string) bool {
+	return target != "" && !strings.HasPrefix(target, "#") && !strings.HasPrefix(target, "/") && !linkSchemeRegex.MatchString(target)
+}
+
+// rewriteMarkdownLinks calls fn with each relative link target in data, replacing the target with the returned value.
+// Any fragment (ie "#heading") is preserved.
+func rewriteMarkdownLinks(data string, fn func(target string) string) string {
+	return markdownLinkRegex.ReplaceAllStringFunc(data, func(link string) string {
+		match := markdownLinkRegex.FindStringSubmatch(link)
+		target, fragment, _ := strings.Cut(match[2], "#")
+
+		if !isRelativeLink(target) {
+			return link
+		}
+
+		newTarget := fn(target)
+		if newTarget == target {
+			return link
+		}
+
+		if fragment != "" {
+			newTarget += "#" + fragment
+		}
+
+		return match[1] + newTarget + match[3]
+	})
+}
+
+// relativeLink returns the link target for dst from a note in dir.
+func relativeLink(dir string, dst string) string {
+	rel, err := filepath.Rel(dir, dst)
+	if err != nil {
+		return dst
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// linkMove describes a meeting (and its assets) which was moved from oldPath to newPath.
+type linkMove struct {
+	from Meeting
+	to   Meeting
+
+	oldPath   string
+	newPath   string
+	oldAssets string
+	newAssets string
+}
+
+// linkRewrite holds the moves for rewriteLinksAfterMoves, indexed for each way links are resolved.
+type linkRewrite struct {
+	byFrom      map[string]linkMove
+	byOldPath   map[string]linkMove
+	byNewPath   map[string]linkMove
+	byOldAssets map[string]linkMove
+
+	// before and after are every meeting (including archived meetings) before and after the moves.
+	before []Meeting
+	after  []Meeting
+}
+
+// rewriteLinksAfterMoves updates the links in every meeting after meetings (and their assets) have been moved. Markdown
+// links which pointed at an old location are pointed at the new location, and relative links in the moved meetings
+// themselves are updated to account for their new directories. Wiki links which no longer resolve to the meeting they
+// referred to before the moves are rewritten to refer to it. Every meeting is only read once however many meetings
+// were moved.
+func (m *Manager) rewriteLinksAfterMoves(moves []linkMove) error {
+	if len(moves) == 0 {
+		return nil
+	}
+
+	meetings, err := m.ListMeetings(MeetingQuery{
+		Name:   glob.MustCompile("*"),
+		Domain: glob.MustCompile("*"),
+		Date:   glob.MustCompile("*"),
+	})
+	if err != nil {
+		return err
+	}
+
+	after, err := m.ListMeetings(MeetingQuery{IncludeArchived: true})
+	if err != nil {
+		return err
+	}
+
+	rewrite := linkRewrite{
+		byFrom:      make(map[string]linkMove, len(moves)),
+		byOldPath:   make(map[string]linkMove, len(moves)),
+		byNewPath:   make(map[string]linkMove, len(moves)),
+		byOldAssets: make(map[string]linkMove, len(moves)),
+		after:       after,
+	}
+
+	byTo := make(map[string]linkMove, len(moves))
+
+	for _, move := range moves {
+		rewrite.byFrom[meetingKey(move.from)] = move
+		rewrite.byOldPath[move.oldPath] = move
+		rewrite.byNewPath[move.newPath] = move
+		rewrite.byOldAssets[move.oldAssets] = move
+		byTo[meetingKey(move.to)] = move
+	}
+
+	rewrite.before = make([]Meeting, len(after))
+	for i, meeting := range after {
+		if move, found := byTo[meetingKey(meeting)]; found {
+			meeting = move.from
+		}
+
+		rewrite.before[i] = meeting
+	}
+
+	jq := NewJobQueueContext(context.Background(), m.parallelism(), FailFast)
+
+	for _, meeting := range meetings {
+		meeting := meeting
+
+		jq.Go(func(context.Context) error {
+			return m.rewriteMeetingLinks(meeting, rewrite)
+		})
+	}
+
+	return jq.Wait()
+}
+
+// movedAssets returns the move whose old assets directory contains p.
+func (r linkRewrite) movedAssets(p string) (linkMove, bool) {
+	for dir := p; dir != "/" && dir != "."; dir = filepath.Dir(dir) {
+		if move, found := r.byOldAssets[dir]; found {
+			return move, true
+		}
+	}
+
+	return linkMove{}, false
+}
+
+// rewriteMeetingLinks updates the links in a single meeting for rewriteLinksAfterMoves.
+func (m *Manager) rewriteMeetingLinks(meeting Meeting, rewrite linkRewrite) error {
+	notePath := m.pathForMeeting(meeting)
+	noteDir := filepath.Dir(notePath)
+
+	// links in a moved meeting were written relative to its old location
+	resolveDir := noteDir
+	oldSource := meeting
+	if move, found := rewrite.byNewPath[notePath]; found {
+		resolveDir = filepath.Dir(move.oldPath)
+		oldSource = move.from
+	}
+
+	data, err := os.ReadFile(notePath)
+	if err != nil {
+		return fmt.Errorf("could not read meeting '%s': %w", meeting, err)
+	}
+
+	rewritten := rewriteMarkdownLinks(string(data), func(target string) string {
+		resolved := filepath.Join(resolveDir, filepath.FromSlash(target))
+
+		if move, found := rewrite.byOldPath[resolved]; found {
+			return relativeLink(noteDir, move.newPath)
+		}
+
+		if move, found := rewrite.movedAssets(resolved); found {
+			return relativeLink(noteDir, move.newAssets+strings.TrimPrefix(resolved, move.oldAssets))
+		}
+
+		if resolveDir != noteDir {
+			return relativeLink(noteDir, resolved)
+		}
+
+		return target
+	})
+
+	rewritten = rewriteWikiLinks(rewritten, func(raw string) string {
+		target, err := parseWikiTarget(raw)
+		if err != nil {
+			return raw
+		}
+
+		// broken links are left alone
+		want, err := resolveWikiTarget(target, oldSource, rewrite.before)
+		if err != nil {
+			return raw
+		}
+
+		if move, found := rewrite.byFrom[meetingKey(want)]; found {
+			want = move.to
+		}
+
+		if current, err := resolveWikiTarget(target, meeting, rewrite.after); err == nil && meetingKey(current) == meetingKey(want) {
+			return raw
+		}
+
+		return wikiTargetFor(target, want, meeting, rewrite.after)
+	})
+
+	if rewritten == string(data) {
+		return nil
+	}
+
+	if err := os.WriteFile(notePath, []byte(rewritten), 0644); err != nil {
+		return fmt.Errorf("could not update links in meeting '%s': %w", meeting, err)
+	}
+
+	return nil
+}
+
+// wikiTargetFor returns a target referring to want from source, keeping the form of the original target where possible.
+// Links to the last meeting stay that way if it is still want, and otherwise refer to want by date.
+func wikiTargetFor(original wikiTarget, want Meeting, source Meeting, meetings []Meeting) string {
+	if !original.dotted {
+		return wikiTarget{meeting: want}.format()
+	}
+
+	if original.meeting.Date == "" {
+		last := wikiTarget{meeting: Meeting{Domain: want.Domain, Name: want.Name}, dotted: true}
+		if original.meeting.Time != "" {
+			last.meeting.Time = want.Time
+		}
+
+		if resolved, err := resolveWikiTarget(last, source, meetings); err == nil && meetingKey(resolved) == meetingKey(want) {
+			return last.format()
+		}
+	}
+
+	return wikiTarget{meeting: want, dotted: true}.format()
+}
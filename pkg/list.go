@@ -0,0 +1,329 @@
+package meetup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SortField is a value meetings or tasks can be sorted by.
+type SortField string
+
+const (
+	SortDate     SortField = "date"
+	SortDomain   SortField = "domain"
+	SortName     SortField = "name"
+	SortModified SortField = "modified"
+
+	// SortTasks sorts meetings by the number of tasks they contain, and is not valid for tasks.
+	SortTasks SortField = "tasks"
+
+	// SortDescription sorts tasks by their description, and is not valid for meetings.
+	SortDescription SortField = "description"
+)
+
+type SortKey struct {
+	Field      SortField
+	Descending bool
+}
+
+func (k SortKey) String() string {
+	if k.Descending {
+		return "-" + string(k.Field)
+	}
+
+	return string(k.Field)
+}
+
+// ParseSortKeys parses a comma separated list of sort fields, each optionally prefixed with "-" to sort in descending
+// order (ie "domain,-date").
+func ParseSortKeys(raw string) ([]SortKey, error) {
+	var keys []SortKey
+
+	for _, component := range strings.Split(raw, ",") {
+		component = strings.TrimSpace(component)
+		if component == "" {
+			continue
+		}
+
+		key := SortKey{}
+
+		if field, found := strings.CutPrefix(component, "-"); found {
+			key.Descending = true
+			component = field
+		} else {
+			component = strings.TrimPrefix(component, "+")
+		}
+
+		key.Field = SortField(component)
+
+		switch key.Field {
+		case SortDate, SortDomain, SortName, SortModified, SortTasks, SortDescription:
+		default:
+			return nil, fmt.Errorf("unknown sort field '%s'", component)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// ListOptions controls the order and number of meetings or tasks returned by a listing.
+type ListOptions struct {
+	// Sort is the keys to sort by in order of priority. Ties, or all results when unset, are ordered chronologically
+	// and then by domain and name so that the order is always deterministic.
+	Sort []SortKey
+
+	// Offset skips the first results, and Limit returns at most Limit results if non-zero.
+	Offset int
+	Limit  int
+
+	// Latest only includes the most recent meeting for each domain and name.
+	Latest bool
+}
+
+func (o ListOptions) Validate() error {
+	if o.Offset < 0 {
+		return fmt.Errorf("offset cannot be negative")
+	}
+
+	if o.Limit < 0 {
+		return fmt.Errorf("limit cannot be negative")
+	}
+
+	return nil
+}
+
+func paginate[T any](items []T, offset int, limit int) []T {
+	if offset >= len(items) {
+		return items[:0]
+	}
+
+	items = items[offset:]
+
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+// latestMeetings keeps only the most recent meeting for each domain and name, preserving the order of meetings.
+func latestMeetings(meetings []Meeting) []Meeting {
+	latest := map[string]Meeting{}
+
+	for _, meeting := range meetings {
+		key := meeting.Domain + "." + meeting.Name
+
+		if current, found := latest[key]; !found || compareMeetings(current, meeting) < 0 {
+			latest[key] = meeting
+		}
+	}
+
+	return slices.DeleteFunc(meetings, func(meeting Meeting) bool {
+		return latest[meeting.Domain+"."+meeting.Name] != meeting
+	})
+}
+
+// modTime returns the modification time of the meeting file or archive.
+func (m *Manager) modTime(meeting Meeting) (time.Time, error) {
+	p := m.pathForMeeting(meeting)
+
+	if meeting.Archived {
+		var err error
+		if p, _, err = m.findArchive(meeting); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	info, err := os.Stat(p)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not stat meeting '%s': %w", meeting, err)
+	}
+
+	return info.ModTime(), nil
+}
+
+// compareField compares the meetings by a field other than the task count.
+func compareField(field SortField, a Meeting, b Meeting, modTimes map[Meeting]time.Time) int {
+	switch field {
+	case SortDate:
+		return compareChronological(a, b)
+	case SortDomain:
+		return strings.Compare(a.Domain, b.Domain)
+	case SortName:
+		return strings.Compare(a.Name, b.Name)
+	case SortModified:
+		return modTimes[a].Compare(modTimes[b])
+	default:
+		return 0
+	}
+}
+
+// modTimes returns the modification time of each meeting if the keys sort by modification time.
+func (m *Manager) modTimes(meetings []Meeting, keys []SortKey) (map[Meeting]time.Time, error) {
+	if !slices.ContainsFunc(keys, func(key SortKey) bool { return key.Field == SortModified }) {
+		return nil, nil
+	}
+
+	modTimes := make(map[Meeting]time.Time, len(meetings))
+
+	for _, meeting := range meetings {
+		t, err := m.modTime(meeting)
+		if err != nil {
+			return nil, err
+		}
+
+		modTimes[meeting] = t
+	}
+
+	return modTimes, nil
+}
+
+func (m *Manager) sortMeetings(meetings []Meeting, keys []SortKey) error {
+	modTimes, err := m.modTimes(meetings, keys)
+	if err != nil {
+		return err
+	}
+
+	var taskCounts map[Meeting]int
+
+	for _, key := range keys {
+		switch key.Field {
+		case SortDescription:
+			return fmt.Errorf("meetings cannot be sorted by %s", key.Field)
+		case SortTasks:
+			if taskCounts != nil {
+				continue
+			}
+
+			counts, err := scanMeetings(context.Background(), m.parallelism(), meetings, func(meeting Meeting) (int, error) {
+				tasks, err := m.searchMeeting(meeting, TaskQuery{})
+				return len(tasks), err
+			})
+			if err != nil {
+				return err
+			}
+
+			taskCounts = make(map[Meeting]int, len(meetings))
+
+			for i, meeting := range meetings {
+				taskCounts[meeting] = counts[i]
+			}
+		}
+	}
+
+	slices.SortStableFunc(meetings, func(a, b Meeting) int {
+		for _, key := range keys {
+			c := compareField(key.Field, a, b, modTimes)
+			if key.Field == SortTasks {
+				c = taskCounts[a] - taskCounts[b]
+			}
+
+			if key.Descending {
+				c = -c
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+
+		return compareMeetings(a, b)
+	})
+
+	return nil
+}
+
+// ListMeetingsWithOptions lists the meetings matching the query, sorted and paginated according to the options.
+func (m *Manager) ListMeetingsWithOptions(query MeetingQuery, opts ListOptions) ([]Meeting, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	meetings, err := m.ListMeetings(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Latest {
+		meetings = latestMeetings(meetings)
+	}
+
+	if err := m.sortMeetings(meetings, opts.Sort); err != nil {
+		return nil, err
+	}
+
+	return paginate(meetings, opts.Offset, opts.Limit), nil
+}
+
+// TasksWithOptions lists the tasks matching the query, sorted and paginated according to the options. With Latest only
+// tasks from the most recent meeting for each domain and name are included.
+func (m *Manager) TasksWithOptions(query TaskQuery, opts ListOptions) ([]Task, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range opts.Sort {
+		if key.Field == SortTasks {
+			return nil, fmt.Errorf("tasks cannot be sorted by %s", key.Field)
+		}
+	}
+
+	tasks, err := m.Tasks(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Latest {
+		meetings, err := m.ListMeetings(query.Meeting)
+		if err != nil {
+			return nil, err
+		}
+
+		latest := map[Meeting]bool{}
+		for _, meeting := range latestMeetings(meetings) {
+			latest[meeting] = true
+		}
+
+		tasks = slices.DeleteFunc(tasks, func(task Task) bool {
+			return !latest[task.Meeting]
+		})
+	}
+
+	meetings := make([]Meeting, 0, len(tasks))
+	for _, task := range tasks {
+		meetings = append(meetings, task.Meeting)
+	}
+
+	modTimes, err := m.modTimes(meetings, opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	// tasks are already in meeting and line order, so a stable sort keeps ties deterministic
+	slices.SortStableFunc(tasks, func(a, b Task) int {
+		for _, key := range opts.Sort {
+			c := compareField(key.Field, a.Meeting, b.Meeting, modTimes)
+			if key.Field == SortDescription {
+				c = strings.Compare(a.Description, b.Description)
+			}
+
+			if key.Descending {
+				c = -c
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+
+	return paginate(tasks, opts.Offset, opts.Limit), nil
+}
@@ -2,9 +2,11 @@ package meetup
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -19,20 +21,43 @@ const (
 )
 
 type Metadata struct {
-	GroupBy         GroupStrategy     `yaml:"group_by"`
-	DomainTemplates map[string]string `yaml:"domain_templates"`
+	GroupBy       GroupStrategy           `yaml:"group_by,omitempty"`
+	TemplateRules []TemplateRule          `yaml:"template_rules,omitempty"`
+	Domains       map[string]DomainConfig `yaml:"domains,omitempty"`
+
+	// TrashRetention is how long removed meetings are kept in the trash, or 0 to keep them until the trash is emptied.
+	// It is a pointer so that an explicit 0 is kept when merging and writing metadata.
+	TrashRetention *Duration `yaml:"trash_retention,omitempty"`
+
+	// ArchiveFormat is how archived meetings are stored, defaulting to ArchiveTree.
+	ArchiveFormat ArchiveFormat `yaml:"archive_format,omitempty"`
+
+	// Views are saved meeting and task listings by name.
+	Views map[string]View `yaml:"views,omitempty"`
+
+	// TaskStates override the default task states with the same name, and add new states.
+	TaskStates []TaskState `yaml:"task_states,omitempty"`
+
+	// Rollups configure the weekly and monthly rollup notes, keyed by period.
+	Rollups map[StatsPeriod]RollupConfig `yaml:"rollups,omitempty"`
 }
 
 func DefaultMetadata() Metadata {
+	retention := Duration(time.Hour * 24 * 30)
+
 	return Metadata{
-		GroupBy: GroupByDomain,
+		GroupBy:        GroupByDomain,
+		TrashRetention: &retention,
 	}
 }
 
 type Config struct {
-	RootDir         string   `yaml:"root_dir"`
-	Editor          []string `yaml:"editor"`
-	DefaultMetadata Metadata `yaml:"default_metadata"`
+	RootDir         string   `yaml:"root_dir,omitempty"`
+	Editor          []string `yaml:"editor,omitempty"`
+	DefaultMetadata Metadata `yaml:"default_metadata,omitempty"`
+
+	// Parallelism is how many meetings are read at once when scanning meetings, or 0 to use the number of CPUs.
+	Parallelism int `yaml:"parallelism,omitempty"`
 }
 
 func DefaultConfig() (Config, error) {
@@ -71,29 +96,61 @@ func NewManager(config Config) (Manager, error) {
 	}
 
 	metadata := config.DefaultMetadata
+
+	// decode into a copy rather than through the pointer shared with the config
+	if metadata.TrashRetention != nil {
+		retention := *metadata.TrashRetention
+		metadata.TrashRetention = &retention
+	}
+
 	if err := yaml.Unmarshal(data, &metadata); err != nil {
 		return Manager{}, fmt.Errorf("could not load metadata: %w", err)
 	}
 
+	if err := metadata.Validate(); err != nil {
+		return Manager{}, fmt.Errorf("could not load metadata: %w", err)
+	}
+
+	if err := metadata.compileTemplateRules(); err != nil {
+		return Manager{}, fmt.Errorf("could not load metadata: %w", err)
+	}
+
+	// domain_templates is not read any more, and would be dropped the next time the metadata is written
+	var legacy struct {
+		DomainTemplates map[string]string `yaml:"domain_templates"`
+	}
+
+	if err := yaml.Unmarshal(data, &legacy); err == nil && legacy.DomainTemplates != nil {
+		slog.Warn("'domain_templates' has been replaced by 'template_rules' and is ignored, run 'meetup doctor --fix' to migrate it", "path", path.Join(config.RootDir, MetadataFilename))
+	}
+
 	if len(config.Editor) == 0 {
 		return Manager{}, fmt.Errorf("editor cannot be empty")
 	}
 
-	path, args := config.Editor[0], config.Editor[1:]
-
-	cmd := exec.Command(path, args...)
-	cmd.Stderr = os.Stderr
-	cmd.Stdout = os.Stdout
-	cmd.Stdin = os.Stdin
+	if config.Parallelism < 0 {
+		return Manager{}, fmt.Errorf("parallelism cannot be negative")
+	}
 
 	return Manager{
 		Config: config,
 
-		baseCmd:  cmd,
+		baseCmd:  editorCmd(config.Editor),
 		metadata: metadata,
 	}, nil
 }
 
+func editorCmd(editor []string) *exec.Cmd {
+	path, args := editor[0], editor[1:]
+
+	cmd := exec.Command(path, args...)
+	cmd.Stderr = os.Stderr
+	cmd.Stdout = os.Stdout
+	cmd.Stdin = os.Stdin
+
+	return cmd
+}
+
 func (m *Manager) SyncMetadata() error {
 	data, err := yaml.Marshal(m.metadata)
 	if err != nil && !os.IsNotExist(err) {
@@ -102,6 +159,10 @@ func (m *Manager) SyncMetadata() error {
 
 	metadataFile := path.Join(m.RootDir, MetadataFilename)
 
+	if err := os.MkdirAll(m.RootDir, 0755); err != nil {
+		return fmt.Errorf("error creating meetup dir: %w", err)
+	}
+
 	if err := os.WriteFile(metadataFile, data, 0644); err != nil {
 		return fmt.Errorf("error writing metadata: %w", err)
 	}
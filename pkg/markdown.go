@@ -0,0 +1,314 @@
+package meetup
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	// headingRegex matches ATX headings, capturing the heading text.
+	headingRegex = regexp.MustCompile(`^ {0,3}#{1,6}(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+
+	// listItemRegex matches bullet (-, *, +) and numbered (1. or 1)) list items, capturing the indentation and the
+	// item's content.
+	listItemRegex = regexp.MustCompile(`^(\s*)(?:[-*+]|\d{1,9}[.)])(?:\s+(.*))?$`)
+
+	// checkboxRegex matches the task list checkbox at the start of a list item, capturing its marker and the rest of
+	// the item.
+	checkboxRegex = regexp.MustCompile(`^\[([^\]])\](?:\s+(.*))?$`)
+
+	fenceRegex = regexp.MustCompile("^\\s*(```|~~~)")
+
+	// checkboxItemRegex matches a list item starting with a checkbox, capturing the text before the marker, the
+	// marker, and the text after the checkbox.
+	checkboxItemRegex = regexp.MustCompile(`^(\s*(?:[-*+]|\d{1,9}[.)])\s+\[)([^\]])\](.*)$`)
+)
+
+// indentWidth returns the width of leading whitespace, counting tabs as 4 columns.
+func indentWidth(indent string) int {
+	width := 0
+
+	for _, c := range indent {
+		if c == '\t' {
+			width += 4 - width%4
+		} else {
+			width++
+		}
+	}
+
+	return width
+}
+
+// listEntry is a list item which may contain nested items.
+type listEntry struct {
+	indent int
+
+	// task is the index of the item's task, or -1 if the item is not a task.
+	task int
+}
+
+// stateForPrefix returns the state whose prefix line starts with, and the rest of the line.
+func stateForPrefix(states []TaskState, line string) (TaskState, string, bool) {
+	for _, state := range states {
+		if state.Prefix != "" && strings.HasPrefix(line, state.Prefix) {
+			return state, strings.TrimPrefix(line, state.Prefix), true
+		}
+	}
+
+	return TaskState{}, "", false
+}
+
+// stateForMarker returns the state with the given checkbox marker.
+func stateForMarker(states []TaskState, marker string) (TaskState, bool) {
+	for _, state := range states {
+		if state.Marker != "" && strings.EqualFold(state.Marker, marker) {
+			return state, true
+		}
+	}
+
+	return TaskState{}, false
+}
+
+// maxLineSize is the longest line which can be read from a meeting, well above bufio.MaxScanTokenSize so that pasted
+// logs or embedded images do not make a meeting unreadable.
+const maxLineSize = 64 * 1024 * 1024
+
+// newLineScanner returns a scanner over the lines of r which accepts lines up to maxLineSize.
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	return scanner
+}
+
+// parseHeadings returns the text of the ATX headings in a markdown document, ignoring lines in fenced code blocks.
+func parseHeadings(r io.Reader) ([]string, error) {
+	var headings []string
+	var inFence string
+
+	scanner := newLineScanner(r)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if match := fenceRegex.FindStringSubmatch(line); match != nil {
+			switch inFence {
+			case "":
+				inFence = match[1]
+			case match[1]:
+				inFence = ""
+			}
+
+			continue
+		}
+
+		if inFence != "" {
+			continue
+		}
+
+		if match := headingRegex.FindStringSubmatch(line); match != nil && strings.TrimSpace(match[1]) != "" {
+			headings = append(headings, strings.TrimSpace(match[1]))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return headings, nil
+}
+
+// parseTasks reads the tasks in a markdown document. Tasks are list items (using any bullet or numbered list marker)
+// starting with a checkbox whose marker belongs to one of the states, or lines starting with one of the states'
+// prefixes. Nested list items become subtasks of the closest task containing them, and indented lines following a task
+// without a blank line in between continue its description. Lines in fenced code blocks are ignored.
+func parseTasks(r io.Reader, meeting Meeting, states []TaskState) ([]Task, error) {
+	var tasks []Task
+	var stack []listEntry
+
+	section := ""
+	inFence := ""
+
+	// continuing is the index of the task whose description the next line may continue, or -1
+	continuing := -1
+
+	scanner := newLineScanner(r)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNumber++
+
+		if match := fenceRegex.FindStringSubmatch(line); match != nil {
+			switch inFence {
+			case "":
+				inFence = match[1]
+			case match[1]:
+				inFence = ""
+			}
+
+			continuing = -1
+			continue
+		}
+
+		if inFence != "" {
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			continuing = -1
+			continue
+		}
+
+		if match := headingRegex.FindStringSubmatch(line); match != nil {
+			section = strings.TrimSpace(match[1])
+			stack = stack[:0]
+			continuing = -1
+			continue
+		}
+
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := indentWidth(line[:len(line)-len(trimmed)])
+
+		var task *Task
+		isItem := false
+
+		if state, description, found := stateForPrefix(states, trimmed); found {
+			task = &Task{State: state, Description: description}
+			isItem = true
+		} else if match := listItemRegex.FindStringSubmatch(line); match != nil {
+			isItem = true
+
+			if checkbox := checkboxRegex.FindStringSubmatch(match[2]); checkbox != nil {
+				if state, found := stateForMarker(states, checkbox[1]); found {
+					task = &Task{State: state, Description: checkbox[2]}
+				}
+			}
+		}
+
+		if !isItem {
+			if continuing >= 0 && indent > stack[len(stack)-1].indent {
+				tasks[continuing].Description = strings.TrimSpace(tasks[continuing].Description + " " + trimmed)
+				continue
+			}
+
+			// anything else less indented than a list item ends it
+			for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+				stack = stack[:len(stack)-1]
+			}
+
+			continuing = -1
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		entry := listEntry{indent: indent, task: -1}
+		continuing = -1
+
+		if task != nil {
+			task.Meeting = meeting
+			task.Line = lineNumber
+			task.Section = section
+			task.Description = strings.TrimSpace(task.Description)
+
+			for i := len(stack) - 1; i >= 0; i-- {
+				if parent := stack[i].task; parent >= 0 {
+					task.ParentLine = tasks[parent].Line
+					task.Depth = tasks[parent].Depth + 1
+					break
+				}
+			}
+
+			entry.task = len(tasks)
+			continuing = len(tasks)
+			tasks = append(tasks, *task)
+		}
+
+		stack = append(stack, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	occurrences := map[string]int{}
+
+	for i := range tasks {
+		tasks[i].Due = findDue(tasks[i].Description, meeting)
+
+		description := normalizeDescription(tasks[i].Description)
+		tasks[i].Occurrence = occurrences[description]
+		occurrences[description]++
+	}
+
+	return tasks, nil
+}
+
+// setLineState rewrites a line holding a task in the from state to be in the to state, keeping its indentation, list
+// marker, and description. Switching between checkbox and prefix states replaces the list marker and checkbox with
+// the prefix, or the prefix with a "-" list item.
+func setLineState(line string, from TaskState, to TaskState) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := line[:len(line)-len(trimmed)]
+
+	var rest string
+
+	if from.Prefix != "" {
+		rest = strings.TrimPrefix(trimmed, from.Prefix)
+	} else if match := checkboxItemRegex.FindStringSubmatch(line); match != nil {
+		if to.Marker != "" {
+			return match[1] + to.Marker + "]" + match[3]
+		}
+
+		rest = strings.TrimLeft(match[3], " \t")
+	}
+
+	return indent + formatTask(rest, to)
+}
+
+// formatTask returns a line holding a task with the description in the state.
+func formatTask(description string, state TaskState) string {
+	if state.Prefix != "" {
+		return state.Prefix + description
+	}
+
+	return strings.TrimRight("- ["+state.Marker+"] "+description, " ")
+}
+
+// TaskNode is a task along with the tasks nested under it.
+type TaskNode struct {
+	Task
+	Children []*TaskNode `json:"children,omitempty"`
+}
+
+// TaskTree arranges tasks into trees by nesting each task under its parent. Tasks whose parent is not in tasks are
+// returned as roots, so filtered subtasks are still included. The order of tasks is preserved among siblings.
+func TaskTree(tasks []Task) []*TaskNode {
+	type key struct {
+		meeting Meeting
+		line    int
+	}
+
+	nodes := make(map[key]*TaskNode, len(tasks))
+	for _, task := range tasks {
+		nodes[key{task.Meeting, task.Line}] = &TaskNode{Task: task}
+	}
+
+	var roots []*TaskNode
+
+	for _, task := range tasks {
+		node := nodes[key{task.Meeting, task.Line}]
+
+		if parent, found := nodes[key{task.Meeting, task.ParentLine}]; task.ParentLine != 0 && found {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	return roots
+}
@@ -1,29 +1,52 @@
 package meetup
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"io/fs"
+	"log/slog"
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
-	"syscall"
 	"text/template"
+	"time"
 
 	"github.com/gobwas/glob"
 	"github.com/otiai10/copy"
 )
 
+const (
+	DateFormat = "2006-01-02"
+	TimeFormat = "15:04"
+)
+
 var (
 	seperators = []byte{' ', '\t', '\n', '-', '_'}
+
+	// meetingFileNameRegex matches a meeting's file name without its extension (ie "sync@1430+2").
+	meetingFileNameRegex = regexp.MustCompile(`^(.+?)(?:@([0-9]{2}):?([0-9]{2}))?(?:\+([0-9]+))?$`)
 )
 
 type Meeting struct {
-	Name     string
-	Date     string
-	Domain   string
-	Template string
+	Name     string `json:"name"`
+	Date     string `json:"date"`
+	Domain   string `json:"domain"`
+	Template string `json:"template,omitempty"`
+
+	// Time is the optional start time of the meeting in TimeFormat.
+	Time string `yaml:"time,omitempty" json:"time,omitempty"`
+
+	// Sequence distinguishes meetings with the same name, date, and time. The first meeting has no sequence (0), and
+	// later meetings start from 2.
+	Sequence int `yaml:"sequence,omitempty" json:"sequence,omitempty"`
+
+	// Archived is set for meetings listed from the archive directory.
+	Archived bool `yaml:"archived,omitempty" json:"archived,omitempty"`
 }
 
 // GetPath retusn the path to the meeting with meetupDir as the root.
@@ -32,16 +55,114 @@ func (m Meeting) GetPath(meetupDir string, gs GroupStrategy) string {
 
 	switch gs {
 	case GroupByDomain:
-		return path.Join(meetupDir, domainComponents, m.Date, m.Name)
+		return path.Join(meetupDir, domainComponents, m.Date, m.fileName())
 	case GroupByDate:
-		return path.Join(meetupDir, m.Date, domainComponents, m.Name)
+		return path.Join(meetupDir, m.Date, domainComponents, m.fileName())
 	default:
 		panic(fmt.Sprintf("unknown group_by: %s", gs))
 	}
 }
 
+// fileName returns the meeting's file name without an extension, which includes the time and sequence when set.
+func (m Meeting) fileName() string {
+	name := m.Name
+
+	if m.Time != "" {
+		name += "@" + strings.ReplaceAll(m.Time, ":", "")
+	}
+
+	if m.Sequence > 0 {
+		name += "+" + strconv.Itoa(m.Sequence)
+	}
+
+	return name
+}
+
+// FullName returns the meeting's name along with its time and sequence when set (ie "sync@14:30+2"), which can be
+// parsed by SplitMeetingName.
+func (m Meeting) FullName() string {
+	name := m.Name
+
+	if m.Time != "" {
+		name += "@" + m.Time
+	}
+
+	if m.Sequence > 0 {
+		name += "+" + strconv.Itoa(m.Sequence)
+	}
+
+	return name
+}
+
 func (m Meeting) String() string {
-	return fmt.Sprintf("%s %s %s", m.Date, m.Domain, m.Name)
+	return fmt.Sprintf("%s %s %s", m.Date, m.Domain, m.FullName())
+}
+
+// SplitMeetingName splits a meeting name with an optional time and sequence (ie "sync@1430+2" or "sync@14:30") into
+// its components. Names without a valid time or sequence are returned as is.
+func SplitMeetingName(raw string) (string, string, int) {
+	match := meetingFileNameRegex.FindStringSubmatch(raw)
+	if match == nil {
+		return raw, "", 0
+	}
+
+	name, t := match[1], ""
+
+	if match[2] != "" {
+		parsed, err := time.Parse("1504", match[2]+match[3])
+		if err != nil {
+			return raw, "", 0
+		}
+
+		t = parsed.Format(TimeFormat)
+	}
+
+	sequence := 0
+
+	if match[4] != "" {
+		sequence, _ = strconv.Atoi(match[4])
+	}
+
+	return name, t, sequence
+}
+
+// ParseTime parses a time of day like "14:30", "1430", "2:30pm", or "2pm" into TimeFormat.
+func ParseTime(raw string) (string, error) {
+	raw = strings.ToLower(strings.ReplaceAll(raw, " ", ""))
+
+	for _, layout := range []string{TimeFormat, "1504", "3:04pm", "3pm"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t.Format(TimeFormat), nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid time '%s': expected a time like 14:30 or 2:30pm", raw)
+}
+
+// compareChronological orders meetings by date, time, and sequence, with untimed meetings first.
+func compareChronological(a, b Meeting) int {
+	if c := strings.Compare(a.Date, b.Date); c != 0 {
+		return c
+	}
+
+	if c := strings.Compare(a.Time, b.Time); c != 0 {
+		return c
+	}
+
+	return a.Sequence - b.Sequence
+}
+
+// compareMeetings orders meetings chronologically, and then meetings at the same time by domain and name.
+func compareMeetings(a, b Meeting) int {
+	if c := compareChronological(a, b); c != 0 {
+		return c
+	}
+
+	if c := strings.Compare(a.Domain, b.Domain); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a.Name, b.Name)
 }
 
 func (m Meeting) Title() string {
@@ -58,57 +179,126 @@ func (m Meeting) Title() string {
 	return builder.String()
 }
 
+// MeetingQuery matches meetings by wildcards over their name, domain, and date, and by an inclusive date range. A nil
+// glob or zero time matches every meeting.
 type MeetingQuery struct {
 	Name   glob.Glob
 	Domain glob.Glob
 	Date   glob.Glob
+
+	Since time.Time
+	Until time.Time
+
+	// Query optionally further restricts the matching meetings, see ParseMeetingQuery. Tag terms only match meetings
+	// listed by a Manager, which can read the meetings' contents.
+	Query *Query
+
+	// IncludeArchived includes archived meetings when listing meetings.
+	IncludeArchived bool
+}
+
+func matchGlob(g glob.Glob, s string) bool {
+	return g == nil || g.Match(s)
 }
 
 func (mw MeetingQuery) Match(m Meeting) bool {
-	return mw.Name.Match(m.Name) &&
-		mw.Domain.Match(m.Domain) &&
-		mw.Date.Match(m.Date)
+	return mw.match(m, nil)
+}
+
+// match is Match with a function returning the meeting's tags for the query, which is only called if needed.
+func (mw MeetingQuery) match(m Meeting, tags func() []string) bool {
+	return mw.matchFields(m) && (mw.Query == nil || mw.Query.MatchMeeting(m, tags))
+}
+
+func (mw MeetingQuery) matchFields(m Meeting) bool {
+	if !matchGlob(mw.Name, m.Name) || !matchGlob(mw.Domain, m.Domain) || !matchGlob(mw.Date, m.Date) {
+		return false
+	}
+
+	if mw.Since.IsZero() && mw.Until.IsZero() {
+		return true
+	}
+
+	if !isValidDate(m.Date) {
+		return false
+	}
+
+	// dates in DateFormat sort lexically, which avoids comparing times across locations
+	if !mw.Since.IsZero() && m.Date < mw.Since.Format(DateFormat) {
+		return false
+	}
+
+	if !mw.Until.IsZero() && m.Date > mw.Until.Format(DateFormat) {
+		return false
+	}
+
+	return true
 }
 
 func (m *Manager) createMeetingFile(meeting Meeting) (string, error) {
-	meetingPath := meeting.GetPath(m.RootDir, m.metadata.GroupBy)
-	meetingDir := path.Dir(meetingPath)
+	meetingPath := m.pathForMeeting(meeting)
 
-	if _, err := os.Stat(meetingDir); err == nil {
+	if _, err := os.Stat(meetingPath); err == nil {
 		return meetingPath, nil
 	}
 
-	if err := os.MkdirAll(meetingDir, 0755); err != nil {
+	if meeting.Template != "" && !m.TemplateExists(meeting.Template) {
+		return "", fmt.Errorf("template '%s' does not exist", meeting.Template)
+	}
+
+	templateName, err := m.ResolveTemplate(meeting)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve template: %w", err)
+	}
+
+	if templateName == "" {
+		templateName = m.DomainConfig(meeting.Domain).Template
+	}
+
+	if templateName != "" && !m.TemplateExists(templateName) {
+		slog.Warn("configured template does not exist", "meeting", meeting, "template", templateName)
+		templateName = ""
+	}
+
+	if err := os.MkdirAll(path.Dir(meetingPath), 0755); err != nil {
 		return "", fmt.Errorf("could not create meeting directory: %w", err)
 	}
 
 	outFile, err := os.Create(meetingPath)
-	if os.IsExist(err) {
-		return meetingPath, nil
-	} else if err != nil {
+	if err != nil {
 		return "", fmt.Errorf("could not create meeting file: %w", err)
 	}
 
 	defer outFile.Close()
 
-	if template, found := m.metadata.DomainTemplates[meeting.Domain]; meeting.Template == "" && found {
-		// todo: warn if template is not found
-		meeting.Template = template
+	if templateName != "" {
+		if err := m.renderTemplate(outFile, templateName, meeting); err != nil {
+			return "", err
+		}
 	}
 
-	if meeting.Template != "" {
-		templatePath := path.Join(m.Config.RootDir, TemplateDirName, meeting.Template)
-		template, err := template.ParseFiles(templatePath)
-		if err != nil {
-			return "", fmt.Errorf("could not parse template: %w", err)
-		}
+	return meetingPath, nil
+}
 
-		if err := template.Execute(outFile, meeting); err != nil {
-			return "", fmt.Errorf("could not execute template: %w", err)
-		}
+func (m *Manager) renderTemplate(w io.Writer, templateName string, meeting Meeting) error {
+	meeting.Template = templateName
+
+	return m.executeTemplate(w, templateName, meeting)
+}
+
+// executeTemplate renders the named template with the given data.
+func (m *Manager) executeTemplate(w io.Writer, templateName string, data any) error {
+	templatePath := path.Join(m.Config.RootDir, TemplateDirName, templateName)
+	template, err := template.ParseFiles(templatePath)
+	if err != nil {
+		return fmt.Errorf("could not parse template: %w", err)
 	}
 
-	return meetingPath, nil
+	if err := template.Execute(w, data); err != nil {
+		return fmt.Errorf("could not execute template: %w", err)
+	}
+
+	return nil
 }
 
 // OpenMeeting opens a meeting in the editor, and creates it if it doesn't not exist.
@@ -119,6 +309,10 @@ func (m *Manager) OpenMeeting(meeting Meeting) error {
 	}
 
 	cmd := *m.baseCmd
+	if editor := m.DomainConfig(meeting.Domain).Editor; len(editor) > 0 {
+		cmd = *editorCmd(editor)
+	}
+
 	cmd.Args = append(cmd.Args, meetingPath)
 
 	if err := cmd.Run(); err != nil {
@@ -133,24 +327,33 @@ func (m *Manager) ListMeetings(mw MeetingQuery) ([]Meeting, error) {
 
 	err := filepath.WalkDir(m.RootDir, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
+			if path == m.RootDir && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+
 			return err
 		}
 
-		if entry.IsDir() && entry.Name() == TemplateDirName {
-			return filepath.SkipDir
+		if path == m.RootDir {
+			return nil
 		}
 
-		if entry.Name() == MetadataFilename {
+		if isHidden(entry.Name()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
 			return nil
 		}
 
 		if !entry.IsDir() {
-			meeting, err := MeetingFromPath(m.metadata.GroupBy, strings.TrimPrefix(path, m.RootDir))
+			// malformed paths are skipped rather than failing the whole listing, use Diagnose to find them
+			meeting, err := m.parseMeetingPath(m.metadata.GroupBy, strings.TrimPrefix(path, m.RootDir))
 			if err != nil {
-				return err
+				return nil
 			}
 
-			if mw.Match(meeting) {
+			if mw.match(meeting, m.tagsFunc(meeting)) {
 				meetings = append(meetings, meeting)
 			}
 		}
@@ -162,28 +365,224 @@ func (m *Manager) ListMeetings(mw MeetingQuery) ([]Meeting, error) {
 		return nil, fmt.Errorf("could not list meetings: %w", err)
 	}
 
+	if mw.IncludeArchived {
+		archived, err := m.ListArchived(mw)
+		if err != nil {
+			return nil, err
+		}
+
+		meetings = append(meetings, archived...)
+	}
+
+	slices.SortStableFunc(meetings, compareMeetings)
+
 	return meetings, nil
 }
 
+// RemoveMeeting moves the meeting into the trash, where it can be restored until the trash is emptied.
 func (m *Manager) RemoveMeeting(meeting Meeting) error {
-	meetingPath := meeting.GetPath(m.RootDir, m.metadata.GroupBy)
+	meetingPath := m.pathForMeeting(meeting)
 
-	if err := os.Remove(meetingPath); err != nil {
+	if _, err := os.Stat(meetingPath); err != nil {
 		return fmt.Errorf("could not delete meeting: %w", err)
 	}
 
-	for domainPath := path.Dir(meetingPath); domainPath != m.RootDir; domainPath = path.Dir(domainPath) {
-		err := os.Remove(domainPath)
+	if _, err := m.trash(meeting, meetingPath, true); err != nil {
+		return fmt.Errorf("could not delete meeting: %w", err)
+	}
+
+	if err := m.purgeTrash(); err != nil {
+		return fmt.Errorf("could not purge trash: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMeetings moves each of the meetings into the trash like RemoveMeeting, updating the trash once rather than for
+// each meeting. Meetings are removed until one fails.
+func (m *Manager) RemoveMeetings(meetings []Meeting) error {
+	if err := m.trashMeetings(meetings); err != nil {
+		return err
+	}
+
+	if err := m.purgeTrash(); err != nil {
+		return fmt.Errorf("could not purge trash: %w", err)
+	}
+
+	return nil
+}
+
+// MeetingExists reports whether the meeting has a file in the meetup directory.
+func (m *Manager) MeetingExists(meeting Meeting) bool {
+	_, err := os.Stat(m.pathForMeeting(meeting))
+	return err == nil
+}
+
+// tagsFunc returns a function which reads the hashtags in the meeting, treating unreadable meetings as having no tags.
+func (m *Manager) tagsFunc(meeting Meeting) func() []string {
+	return func() []string {
+		f, err := m.openMeeting(meeting)
 		if err != nil {
-			pathErr := err.(*os.PathError)
-			if pathErr.Err == syscall.ENOTEMPTY {
-				break
-			}
+			return nil
+		}
+		defer f.Close()
+
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return nil
+		}
+
+		return findTags(string(data))
+	}
+}
+
+// NextSequence returns the meeting if it does not exist yet, or otherwise the meeting with the first unused sequence.
+func (m *Manager) NextSequence(meeting Meeting) Meeting {
+	if !m.MeetingExists(meeting) {
+		return meeting
+	}
+
+	if meeting.Sequence < 2 {
+		meeting.Sequence = 2
+	}
+
+	for m.MeetingExists(meeting) {
+		meeting.Sequence++
+	}
+
+	return meeting
+}
+
+// MoveOptions controls how meetings are moved.
+type MoveOptions struct {
+	// Overwrite allows replacing an existing meeting at the destination, which is moved to the trash.
+	Overwrite bool
+}
+
+// MoveMeeting moves a meeting to a new domain, name, or date. Moving will fail rather than overwrite an existing
+// meeting.
+func (m *Manager) MoveMeeting(from Meeting, to Meeting) error {
+	return m.MoveMeetingWithOptions(from, to, MoveOptions{})
+}
+
+// MoveMeetingWithOptions moves a meeting along with its assets directory to a new domain, name, or date. Markdown
+// links in other meetings which refer to the meeting or its assets are updated to the new location, as are the
+// relative links in the moved meeting itself. Wiki links are rewritten so they still refer to the same meetings.
+func (m *Manager) MoveMeetingWithOptions(from Meeting, to Meeting, opts MoveOptions) error {
+	move, err := m.moveMeeting(from, to, m.pathForMeeting(to), opts)
+	if err != nil || move == nil {
+		return err
+	}
+
+	if err := m.recordMoves([]linkMove{*move}); err != nil {
+		return err
+	}
+
+	if err := m.rewriteLinksAfterMoves([]linkMove{*move}); err != nil {
+		return fmt.Errorf("could not update links: %w", err)
+	}
+
+	return nil
+}
+
+// moveMeeting moves a meeting and its assets to dst like MoveMeetingWithOptions without updating any links, returning
+// the move to pass to rewriteLinksAfterMoves, or nil if the meeting did not need to be moved.
+func (m *Manager) moveMeeting(from Meeting, to Meeting, dst string, opts MoveOptions) (*linkMove, error) {
+	src := m.pathForMeeting(from)
+
+	if src == dst {
+		return nil, nil
+	}
+
+	if _, err := os.Stat(src); err != nil {
+		return nil, fmt.Errorf("could not move meeting: %w", err)
+	}
+
+	_, err := os.Stat(dst)
+	overwrite := err == nil
+
+	if overwrite && !opts.Overwrite {
+		return nil, fmt.Errorf("could not move meeting: '%s' already exists", to)
+	}
+
+	srcAssets, dstAssets := m.assetsPath(from), m.assetsPath(to)
+
+	// the assets of an overwritten meeting are moved to the trash along with it
+	if _, err := os.Stat(dstAssets); err == nil && !overwrite {
+		return nil, fmt.Errorf("could not move meeting: assets directory for '%s' already exists", to)
+	}
 
-			return fmt.Errorf("could not delete meeting: %w", err)
+	// only replace the destination once nothing else can fail before the move
+	if overwrite {
+		if _, err := m.trash(to, dst, true); err != nil {
+			return nil, fmt.Errorf("could not replace meeting: %w", err)
 		}
 	}
 
+	if err := os.MkdirAll(path.Dir(dst), 0755); err != nil {
+		return nil, fmt.Errorf("could not create meeting directory: %w", err)
+	}
+
+	if err := os.Rename(src, dst); err != nil {
+		return nil, fmt.Errorf("could not move meeting: %w", err)
+	}
+
+	if _, err := os.Stat(srcAssets); err == nil {
+		if err := os.Rename(srcAssets, dstAssets); err != nil {
+			return nil, fmt.Errorf("could not move meeting assets: %w", err)
+		}
+	}
+
+	if _, err := m.pruneDirs(path.Dir(src)); err != nil {
+		return nil, fmt.Errorf("could not move meeting: %w", err)
+	}
+
+	return &linkMove{from: from, to: to, oldPath: src, newPath: dst, oldAssets: srcAssets, newAssets: dstAssets}, nil
+}
+
+// ApplyTemplate replaces the contents of an existing meeting with the rendered template. The previous contents are
+// moved to the trash.
+func (m *Manager) ApplyTemplate(meeting Meeting, templateName string) error {
+	if !m.TemplateExists(templateName) {
+		return fmt.Errorf("template '%s' does not exist", templateName)
+	}
+
+	meetingPath := m.pathForMeeting(meeting)
+
+	if _, err := os.Stat(meetingPath); err != nil {
+		return fmt.Errorf("could not apply template: %w", err)
+	}
+
+	buffer := bytes.Buffer{}
+	if err := m.renderTemplate(&buffer, templateName, meeting); err != nil {
+		return err
+	}
+
+	if _, err := m.trash(meeting, meetingPath, false); err != nil {
+		return fmt.Errorf("could not apply template: %w", err)
+	}
+
+	if err := os.MkdirAll(path.Dir(meetingPath), 0755); err != nil {
+		return fmt.Errorf("could not create meeting directory: %w", err)
+	}
+
+	if err := os.WriteFile(meetingPath, buffer.Bytes(), 0644); err != nil {
+		return fmt.Errorf("could not write meeting: %w", err)
+	}
+
+	return nil
+}
+
+// deleteMeetingFile permanently deletes the meeting file at p, and prunes any directories left empty.
+func (m *Manager) deleteMeetingFile(p string) error {
+	if err := os.Remove(p); err != nil {
+		return fmt.Errorf("could not delete meeting: %w", err)
+	}
+
+	if _, err := m.pruneDirs(path.Dir(p)); err != nil {
+		return fmt.Errorf("could not delete meeting: %w", err)
+	}
+
 	return nil
 }
 
@@ -204,8 +603,8 @@ func (m *Manager) UpdateMeetingGroupBy(newGs GroupStrategy) error {
 	}
 
 	for _, meeting := range meetings {
-		oldMeetingPath := meeting.GetPath(m.RootDir, oldGs)
-		newMeetingPath := meeting.GetPath(m.RootDir, newGs)
+		oldMeetingPath := m.pathForMeeting(meeting)
+		newMeetingPath := meeting.GetPath(m.RootDir, newGs) + strings.TrimPrefix(oldMeetingPath, meeting.GetPath(m.RootDir, oldGs))
 
 		if err := os.MkdirAll(path.Dir(newMeetingPath), 0755); err != nil {
 			return fmt.Errorf("could not create meeting directory: %w", err)
@@ -215,11 +614,22 @@ func (m *Manager) UpdateMeetingGroupBy(newGs GroupStrategy) error {
 			return fmt.Errorf("could not copy meeting: %w", err)
 		}
 
-		if err := m.RemoveMeeting(meeting); err != nil {
+		oldAssets := meeting.GetPath(m.RootDir, oldGs) + AssetsDirSuffix
+		if _, err := os.Stat(oldAssets); err == nil {
+			if err := os.Rename(oldAssets, meeting.GetPath(m.RootDir, newGs)+AssetsDirSuffix); err != nil {
+				return fmt.Errorf("could not move meeting assets: %w", err)
+			}
+		}
+
+		if err := m.deleteMeetingFile(oldMeetingPath); err != nil {
 			return fmt.Errorf("could not remove meeting: %w", err)
 		}
 	}
 
+	if err := m.migrateArchive(oldGs, newGs); err != nil {
+		return fmt.Errorf("could not migrate archive: %w", err)
+	}
+
 	m.metadata.GroupBy = newGs
 	if err := m.SyncMetadata(); err != nil {
 		return fmt.Errorf("could not sync metadata: %w", err)
@@ -0,0 +1,100 @@
+package meetup
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MovesFilename is the file in the meetup directory recording which meetings were moved, so that tasks exported before
+// their meeting was moved can still be matched to it.
+const MovesFilename = ".moves.yaml"
+
+// meetingMove records that a meeting was moved to a new domain, name, or date.
+type meetingMove struct {
+	From Meeting `yaml:"from"`
+	To   Meeting `yaml:"to"`
+}
+
+func (m *Manager) movesPath() string {
+	return path.Join(m.RootDir, MovesFilename)
+}
+
+func (m *Manager) loadMoves() ([]meetingMove, error) {
+	data, err := os.ReadFile(m.movesPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not read moves: %w", err)
+	}
+
+	var moves []meetingMove
+	if err := yaml.Unmarshal(data, &moves); err != nil {
+		return nil, fmt.Errorf("could not parse moves: %w", err)
+	}
+
+	return moves, nil
+}
+
+// recordMoves appends the moved meetings to the moves file.
+func (m *Manager) recordMoves(moves []linkMove) error {
+	if len(moves) == 0 {
+		return nil
+	}
+
+	recorded, err := m.loadMoves()
+	if err != nil {
+		return err
+	}
+
+	for _, move := range moves {
+		recorded = append(recorded, meetingMove{From: move.from, To: move.to})
+	}
+
+	data, err := yaml.Marshal(recorded)
+	if err != nil {
+		return fmt.Errorf("could not marshal moves: %w", err)
+	}
+
+	if err := os.WriteFile(m.movesPath(), data, 0644); err != nil {
+		return fmt.Errorf("could not write moves: %w", err)
+	}
+
+	return nil
+}
+
+// formerMeetings returns the meetings each moved meeting was previously, keyed by the path of the meeting relative to
+// the meetup directory when grouped by date.
+func (m *Manager) formerMeetings() (map[string][]Meeting, error) {
+	moves, err := m.loadMoves()
+	if err != nil {
+		return nil, err
+	}
+
+	formers := map[string][]Meeting{}
+
+	for _, move := range moves {
+		from, to := move.From.GetPath("", GroupByDate), move.To.GetPath("", GroupByDate)
+		formers[to] = append(slices.Clone(formers[from]), move.From)
+	}
+
+	return formers, nil
+}
+
+// formerTasks returns the task as it was in each of the meetings its meeting was previously.
+func (t Task) formerTasks(formers map[string][]Meeting) []Task {
+	var tasks []Task
+
+	for _, meeting := range formers[t.Meeting.GetPath("", GroupByDate)] {
+		former := t
+		former.Meeting = meeting
+		tasks = append(tasks, former)
+	}
+
+	return tasks
+}
@@ -0,0 +1,260 @@
+package meetup
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultProfile is the name of the profile described by the top-level values of the config file.
+	DefaultProfile = "default"
+
+	ConfigFilename = "config.yaml"
+)
+
+// ConfigFile is the user's configuration file. Its top-level values form the default profile, and each named profile
+// overrides them.
+type ConfigFile struct {
+	Config `yaml:",inline"`
+
+	// Profile is the name of the profile to use when none is specified.
+	Profile  string            `yaml:"profile,omitempty"`
+	Profiles map[string]Config `yaml:"profiles,omitempty"`
+}
+
+// ConfigFilePath returns the path to the user's configuration file.
+func ConfigFilePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not find user config dir: %w", err)
+	}
+
+	return path.Join(configDir, "meetup", ConfigFilename), nil
+}
+
+// LoadConfigFile reads the config file at the given path. A missing file is treated as an empty configuration.
+func LoadConfigFile(p string) (ConfigFile, error) {
+	bytes, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ConfigFile{}, nil
+		}
+
+		return ConfigFile{}, fmt.Errorf("could not read config file: %w", err)
+	}
+
+	var file ConfigFile
+	if err := yaml.Unmarshal(bytes, &file); err != nil {
+		return ConfigFile{}, fmt.Errorf("could not parse config file: %w", err)
+	}
+
+	return file, nil
+}
+
+// Save writes the config file to the given path, creating its parent directory if needed.
+func (f ConfigFile) Save(p string) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("could not marshal config file: %w", err)
+	}
+
+	if err := os.MkdirAll(path.Dir(p), 0755); err != nil {
+		return fmt.Errorf("could not create config dir: %w", err)
+	}
+
+	if err := os.WriteFile(p, data, 0644); err != nil {
+		return fmt.Errorf("could not write config file: %w", err)
+	}
+
+	return nil
+}
+
+// ProfileNames returns the names of all available profiles, including the default profile.
+func (f ConfigFile) ProfileNames() []string {
+	names := []string{DefaultProfile}
+
+	for name := range f.Profiles {
+		if name != DefaultProfile {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names[1:])
+
+	return names
+}
+
+// ActiveProfile returns the name of the profile to use, preferring the given name over the profile selected in the
+// config file.
+func (f ConfigFile) ActiveProfile(name string) string {
+	switch {
+	case name != "":
+		return name
+	case f.Profile != "":
+		return f.Profile
+	default:
+		return DefaultProfile
+	}
+}
+
+// ProfileConfig resolves the configuration for the named profile by layering the default config, the top-level
+// values of the config file, and the profile's own values.
+func (f ConfigFile) ProfileConfig(name string) (Config, error) {
+	effective, err := f.Resolve(name)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return effective.Config, nil
+}
+
+// Resolve resolves the configuration for the named profile like ProfileConfig, and then applies each of the given
+// layers in order. The source of each value is recorded in the returned EffectiveConfig.
+func (f ConfigFile) Resolve(name string, layers ...ConfigLayer) (EffectiveConfig, error) {
+	config, err := DefaultConfig()
+	if err != nil {
+		return EffectiveConfig{}, fmt.Errorf("could not create default config: %w", err)
+	}
+
+	effective := EffectiveConfig{
+		Config:  config,
+		Profile: name,
+		Sources: map[string]ConfigSource{},
+	}
+
+	for _, key := range config.setKeys() {
+		effective.Sources[key] = SourceDefault
+	}
+
+	fileLayers := []ConfigLayer{{Source: SourceFile, Config: f.Config}}
+
+	if name != DefaultProfile {
+		profile, found := f.Profiles[name]
+		if !found {
+			return EffectiveConfig{}, fmt.Errorf("no such profile '%s'", name)
+		}
+
+		fileLayers = append(fileLayers, ConfigLayer{Source: SourceProfile, Config: profile})
+	}
+
+	for _, layer := range append(fileLayers, layers...) {
+		effective.Config = effective.Config.merge(layer.Config)
+
+		for _, key := range layer.Config.setKeys() {
+			effective.Sources[key] = layer.Source
+		}
+	}
+
+	return effective, nil
+}
+
+// merge overrides the fields of c with the set fields of other.
+func (c Config) merge(other Config) Config {
+	if other.RootDir != "" {
+		c.RootDir = other.RootDir
+	}
+
+	if len(other.Editor) > 0 {
+		c.Editor = other.Editor
+	}
+
+	if other.Parallelism != 0 {
+		c.Parallelism = other.Parallelism
+	}
+
+	c.DefaultMetadata = c.DefaultMetadata.merge(other.DefaultMetadata)
+
+	return c
+}
+
+// setKeys returns the config keys of the values which are set in c.
+func (c Config) setKeys() []string {
+	var keys []string
+
+	if c.RootDir != "" {
+		keys = append(keys, "root_dir")
+	}
+
+	if len(c.Editor) > 0 {
+		keys = append(keys, "editor")
+	}
+
+	if c.Parallelism != 0 {
+		keys = append(keys, "parallelism")
+	}
+
+	if c.DefaultMetadata.GroupBy != "" {
+		keys = append(keys, "default_metadata.group_by")
+	}
+
+	if c.DefaultMetadata.TemplateRules != nil {
+		keys = append(keys, "default_metadata.template_rules")
+	}
+
+	if c.DefaultMetadata.Domains != nil {
+		keys = append(keys, "default_metadata.domains")
+	}
+
+	if c.DefaultMetadata.TrashRetention != nil {
+		keys = append(keys, "default_metadata.trash_retention")
+	}
+
+	if c.DefaultMetadata.ArchiveFormat != "" {
+		keys = append(keys, "default_metadata.archive_format")
+	}
+
+	if c.DefaultMetadata.Views != nil {
+		keys = append(keys, "default_metadata.views")
+	}
+
+	if c.DefaultMetadata.TaskStates != nil {
+		keys = append(keys, "default_metadata.task_states")
+	}
+
+	if c.DefaultMetadata.Rollups != nil {
+		keys = append(keys, "default_metadata.rollups")
+	}
+
+	return keys
+}
+
+// merge overrides the fields of m with the set fields of other.
+func (m Metadata) merge(other Metadata) Metadata {
+	if other.GroupBy != "" {
+		m.GroupBy = other.GroupBy
+	}
+
+	if other.TemplateRules != nil {
+		m.TemplateRules = other.TemplateRules
+	}
+
+	if other.Domains != nil {
+		m.Domains = other.Domains
+	}
+
+	if other.TrashRetention != nil {
+		m.TrashRetention = other.TrashRetention
+	}
+
+	if other.ArchiveFormat != "" {
+		m.ArchiveFormat = other.ArchiveFormat
+	}
+
+	if other.Views != nil {
+		m.Views = other.Views
+	}
+
+	if other.TaskStates != nil {
+		m.TaskStates = other.TaskStates
+	}
+
+	if other.Rollups != nil {
+		m.Rollups = other.Rollups
+	}
+
+	return m
+}
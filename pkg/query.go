@@ -0,0 +1,609 @@
+package meetup
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob"
+)
+
+var (
+	hashtagRegex = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_][\p{L}\p{N}_\-/]*)`)
+)
+
+// findTags returns the unique hashtags in s, without the leading '#'.
+func findTags(s string) []string {
+	var tags []string
+
+	for _, match := range hashtagRegex.FindAllStringSubmatch(s, -1) {
+		if !slices.Contains(tags, match[1]) {
+			tags = append(tags, match[1])
+		}
+	}
+
+	return tags
+}
+
+// QuerySyntaxError describes an invalid query, and where in the query the problem was found.
+type QuerySyntaxError struct {
+	Query string
+
+	// Pos is the byte offset of the problem in Query.
+	Pos int
+	Msg string
+}
+
+func (e *QuerySyntaxError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Pos+1, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenTerm
+)
+
+type token struct {
+	kind tokenKind
+	pos  int
+	text string
+
+	// field, op, and value are only set for terms, with valuePos the offset of the value in the query.
+	field    string
+	op       string
+	value    string
+	valuePos int
+}
+
+var queryOperators = []string{">=", "<=", "!=", ":", "=", ">", "<"}
+
+type queryLexer struct {
+	query string
+	pos   int
+}
+
+func (l *queryLexer) errorf(pos int, format string, args ...any) error {
+	return &QuerySyntaxError{Query: l.query, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// peek decodes the rune at the current position, returning utf8.RuneError and a size of 0 at the end of the query.
+func (l *queryLexer) peek() (rune, int) {
+	return utf8.DecodeRuneInString(l.query[l.pos:])
+}
+
+func (l *queryLexer) next() (token, error) {
+	for r, size := l.peek(); size > 0 && unicode.IsSpace(r); r, size = l.peek() {
+		l.pos += size
+	}
+
+	start := l.pos
+
+	if l.pos >= len(l.query) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	switch l.query[l.pos] {
+	case '(':
+		l.pos++
+		return token{kind: tokenLParen, pos: start, text: "("}, nil
+	case ')':
+		l.pos++
+		return token{kind: tokenRParen, pos: start, text: ")"}, nil
+	}
+
+	for r, size := l.peek(); size > 0 && (unicode.IsLetter(r) || r == '_'); r, size = l.peek() {
+		l.pos += size
+	}
+
+	word := l.query[start:l.pos]
+
+	var op string
+	for _, candidate := range queryOperators {
+		if strings.HasPrefix(l.query[l.pos:], candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	if op == "" {
+		switch strings.ToUpper(word) {
+		case "AND":
+			return token{kind: tokenAnd, pos: start, text: word}, nil
+		case "OR":
+			return token{kind: tokenOr, pos: start, text: word}, nil
+		case "NOT":
+			return token{kind: tokenNot, pos: start, text: word}, nil
+		}
+
+		end := strings.IndexFunc(l.query[start:], func(r rune) bool { return unicode.IsSpace(r) || r == '(' || r == ')' })
+		if end == -1 {
+			end = len(l.query) - start
+		}
+
+		if end == 0 {
+			end = 1
+		}
+
+		return token{}, l.errorf(start, "expected a term like field:value but found '%s'", l.query[start:start+end])
+	}
+
+	if word == "" {
+		return token{}, l.errorf(start, "missing field before '%s'", op)
+	}
+
+	l.pos += len(op)
+	valuePos := l.pos
+
+	var value string
+
+	if l.pos < len(l.query) && l.query[l.pos] == '"' {
+		end := strings.IndexByte(l.query[l.pos+1:], '"')
+		if end == -1 {
+			return token{}, l.errorf(l.pos, "unterminated quoted value")
+		}
+
+		value = l.query[l.pos+1 : l.pos+1+end]
+		l.pos += end + 2
+	} else {
+		for r, size := l.peek(); size > 0 && !unicode.IsSpace(r) && r != '(' && r != ')'; r, size = l.peek() {
+			l.pos += size
+		}
+
+		value = l.query[valuePos:l.pos]
+	}
+
+	if value == "" {
+		return token{}, l.errorf(valuePos, "missing value for field '%s'", word)
+	}
+
+	return token{
+		kind:     tokenTerm,
+		pos:      start,
+		text:     l.query[start:l.pos],
+		field:    strings.ToLower(word),
+		op:       op,
+		value:    value,
+		valuePos: valuePos,
+	}, nil
+}
+
+// queryTarget is the meeting or task a query is evaluated against.
+type queryTarget struct {
+	meeting Meeting
+	task    *Task
+
+	// tags lazily returns the tags of the task or meeting.
+	tags func() []string
+}
+
+type queryNode interface {
+	eval(target *queryTarget) bool
+}
+
+type andNode struct{ left, right queryNode }
+
+func (n andNode) eval(target *queryTarget) bool { return n.left.eval(target) && n.right.eval(target) }
+
+type orNode struct{ left, right queryNode }
+
+func (n orNode) eval(target *queryTarget) bool { return n.left.eval(target) || n.right.eval(target) }
+
+type notNode struct{ node queryNode }
+
+func (n notNode) eval(target *queryTarget) bool { return !n.node.eval(target) }
+
+type termNode func(target *queryTarget) bool
+
+func (n termNode) eval(target *queryTarget) bool { return n(target) }
+
+// Query is a parsed boolean query over meetings or tasks. See ParseMeetingQuery for the syntax.
+type Query struct {
+	raw   string
+	root  queryNode
+	tasks bool
+}
+
+func (q *Query) String() string {
+	return q.raw
+}
+
+// And returns a query matching only what both queries match. Either query may be nil, in which case the other is
+// returned.
+func (q *Query) And(other *Query) *Query {
+	if q == nil {
+		return other
+	}
+
+	if other == nil {
+		return q
+	}
+
+	return &Query{
+		raw:   fmt.Sprintf("(%s) AND (%s)", q.raw, other.raw),
+		root:  andNode{left: q.root, right: other.root},
+		tasks: q.tasks || other.tasks,
+	}
+}
+
+// MatchMeeting reports whether the meeting matches the query given the meeting's tags.
+func (q *Query) MatchMeeting(meeting Meeting, tags func() []string) bool {
+	if tags == nil {
+		tags = func() []string { return nil }
+	}
+
+	return q.root.eval(&queryTarget{meeting: meeting, tags: tags})
+}
+
+// MatchTask reports whether the task matches the query.
+func (q *Query) MatchTask(task Task) bool {
+	return q.root.eval(&queryTarget{meeting: task.Meeting, task: &task, tags: task.Tags})
+}
+
+type queryParser struct {
+	lexer   queryLexer
+	current token
+	tasks   bool
+}
+
+func (p *queryParser) advance() error {
+	var err error
+	p.current, err = p.lexer.next()
+	return err
+}
+
+func (p *queryParser) errorf(pos int, format string, args ...any) error {
+	return p.lexer.errorf(pos, format, args...)
+}
+
+// parseOr parses: and { OR and }
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.current.kind == tokenOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orNode{left, right}
+	}
+
+	return left, nil
+}
+
+// parseAnd parses: not { [AND] not }, where adjacent terms are implicitly joined with AND.
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.current.kind {
+		case tokenAnd:
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		case tokenNot, tokenLParen, tokenTerm:
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andNode{left, right}
+	}
+}
+
+// parseNot parses: NOT not | primary
+func (p *queryParser) parseNot() (queryNode, error) {
+	if p.current.kind == tokenNot {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		node, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return notNode{node}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+// parsePrimary parses: '(' or ')' | term
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	switch p.current.kind {
+	case tokenLParen:
+		open := p.current.pos
+
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.current.kind != tokenRParen {
+			return nil, p.errorf(open, "unclosed '('")
+		}
+
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		return node, nil
+	case tokenTerm:
+		node, err := p.term(p.current)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		return node, nil
+	case tokenEOF:
+		return nil, p.errorf(p.current.pos, "unexpected end of query")
+	default:
+		return nil, p.errorf(p.current.pos, "unexpected '%s'", p.current.text)
+	}
+}
+
+// stringTerm matches a string value with a glob for ':', or exactly for '=' and '!='.
+func (p *queryParser) stringTerm(tok token, get func(*queryTarget) string) (queryNode, error) {
+	switch tok.op {
+	case ":":
+		g, err := glob.Compile(tok.value)
+		if err != nil {
+			return nil, p.errorf(tok.valuePos, "invalid pattern '%s': %s", tok.value, err)
+		}
+
+		return termNode(func(target *queryTarget) bool { return g.Match(get(target)) }), nil
+	case "=":
+		return termNode(func(target *queryTarget) bool { return get(target) == tok.value }), nil
+	case "!=":
+		return termNode(func(target *queryTarget) bool { return get(target) != tok.value }), nil
+	default:
+		return nil, p.errorf(tok.pos+len(tok.field), "operator '%s' is not supported for %s", tok.op, tok.field)
+	}
+}
+
+// orderedTerm is a stringTerm which also supports comparisons, after normalizing the value with parse. Empty values
+// never match a comparison.
+func (p *queryParser) orderedTerm(tok token, parse func(string) (string, error), get func(*queryTarget) string) (queryNode, error) {
+	if tok.op == ":" {
+		return p.stringTerm(tok, get)
+	}
+
+	value, err := parse(tok.value)
+	if err != nil {
+		return nil, p.errorf(tok.valuePos, "%s", err)
+	}
+
+	compare := map[string]func(int) bool{
+		"=":  func(c int) bool { return c == 0 },
+		"!=": func(c int) bool { return c != 0 },
+		">":  func(c int) bool { return c > 0 },
+		">=": func(c int) bool { return c >= 0 },
+		"<":  func(c int) bool { return c < 0 },
+		"<=": func(c int) bool { return c <= 0 },
+	}[tok.op]
+
+	return termNode(func(target *queryTarget) bool {
+		actual := get(target)
+		if actual == "" {
+			return tok.op == "!="
+		}
+
+		return compare(strings.Compare(actual, value))
+	}), nil
+}
+
+func (p *queryParser) term(tok token) (queryNode, error) {
+	taskOnly := func() error {
+		if !p.tasks {
+			return p.errorf(tok.pos, "%s is only supported when querying tasks", tok.text)
+		}
+
+		return nil
+	}
+
+	switch tok.field {
+	case "name":
+		return p.stringTerm(tok, func(target *queryTarget) string { return target.meeting.Name })
+	case "domain":
+		return p.stringTerm(tok, func(target *queryTarget) string { return target.meeting.Domain })
+	case "date", "due":
+		parse := func(raw string) (string, error) {
+			date, err := ParseDate(raw, time.Now())
+			if err != nil {
+				return "", err
+			}
+
+			return date.Format(DateFormat), nil
+		}
+
+		if tok.field == "due" {
+			if err := taskOnly(); err != nil {
+				return nil, err
+			}
+
+			return p.orderedTerm(tok, parse, func(target *queryTarget) string { return target.task.Due })
+		}
+
+		return p.orderedTerm(tok, parse, func(target *queryTarget) string { return target.meeting.Date })
+	case "time":
+		return p.orderedTerm(tok, ParseTime, func(target *queryTarget) string { return target.meeting.Time })
+	case "description", "desc":
+		if err := taskOnly(); err != nil {
+			return nil, err
+		}
+
+		return p.stringTerm(tok, func(target *queryTarget) string { return target.task.Description })
+	case "section":
+		if err := taskOnly(); err != nil {
+			return nil, err
+		}
+
+		return p.stringTerm(tok, func(target *queryTarget) string { return target.task.Section })
+	case "state":
+		if err := taskOnly(); err != nil {
+			return nil, err
+		}
+
+		return p.stringTerm(tok, func(target *queryTarget) string { return target.task.State.Name })
+	case "tag":
+		var match func(string) bool
+
+		switch tok.op {
+		case ":":
+			g, err := glob.Compile(tok.value)
+			if err != nil {
+				return nil, p.errorf(tok.valuePos, "invalid pattern '%s': %s", tok.value, err)
+			}
+
+			match = g.Match
+		case "=", "!=":
+			match = func(tag string) bool { return tag == tok.value }
+		default:
+			return nil, p.errorf(tok.pos+len(tok.field), "operator '%s' is not supported for %s", tok.op, tok.field)
+		}
+
+		return termNode(func(target *queryTarget) bool {
+			return slices.ContainsFunc(target.tags(), match) != (tok.op == "!=")
+		}), nil
+	case "is":
+		if tok.op != ":" && tok.op != "=" {
+			return nil, p.errorf(tok.pos+len(tok.field), "operator '%s' is not supported for %s", tok.op, tok.field)
+		}
+
+		switch strings.ToLower(tok.value) {
+		case "archived":
+			return termNode(func(target *queryTarget) bool { return target.meeting.Archived }), nil
+		case "complete":
+			if err := taskOnly(); err != nil {
+				return nil, err
+			}
+
+			return termNode(func(target *queryTarget) bool { return target.task.State.Done }), nil
+		case "incomplete":
+			if err := taskOnly(); err != nil {
+				return nil, err
+			}
+
+			return termNode(func(target *queryTarget) bool { return !target.task.State.Done }), nil
+		default:
+			return nil, p.errorf(tok.valuePos, "unknown value '%s' for is, expected archived, complete, or incomplete", tok.value)
+		}
+	default:
+		return nil, p.errorf(tok.pos, "unknown field '%s'", tok.field)
+	}
+}
+
+func parseQuery(raw string, tasks bool) (*Query, error) {
+	p := &queryParser{lexer: queryLexer{query: raw}, tasks: tasks}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.current.kind != tokenEOF {
+		return nil, p.errorf(p.current.pos, "unexpected '%s'", p.current.text)
+	}
+
+	return &Query{raw: raw, root: root, tasks: tasks}, nil
+}
+
+// ParseMeetingQuery parses a boolean query over meetings. A query is made of terms joined by AND, OR, and NOT (in
+// order of increasing precedence) and grouped with parentheses, with adjacent terms implicitly joined by AND:
+//
+//	domain:work.* AND NOT name:standup AND (date>=2024-01-01 OR tag:urgent)
+//
+// Each term is a field, an operator, and a value which may be double quoted. The ':' operator matches a wildcard, '='
+// and '!=' compare exactly, and date and time fields also support '<', '<=', '>', and '>=' with absolute or relative
+// dates (see ParseDate). Supported fields are:
+//
+//   - name, domain, date, time
+//   - tag, which matches hashtags (ie #urgent) in the meeting
+//   - is:archived
+func ParseMeetingQuery(raw string) (*Query, error) {
+	return parseQuery(raw, false)
+}
+
+// ParseTaskQuery parses a boolean query over tasks, using the syntax of ParseMeetingQuery. Fields refer to a task's
+// meeting, except for the additional task fields:
+//
+//   - description (or desc)
+//   - section, the heading the task is under
+//   - state, the name of the task's state (ie state:cancelled)
+//   - due, the task's due date, which supports the same operators as date
+//   - tag, which matches hashtags in the task's description
+//   - is:complete and is:incomplete, which match tasks whose state is or is not done
+func ParseTaskQuery(raw string) (*Query, error) {
+	return parseQuery(raw, true)
+}
+
+// renameQueryDomain rewrites the domain terms in a query which refer to the from domain or one of its subdomains to
+// refer to the to domain instead. Queries which cannot be parsed are returned unchanged.
+func renameQueryDomain(raw string, from string, to string) string {
+	lexer := queryLexer{query: raw}
+	renamed := ""
+	last := 0
+
+	for {
+		tok, err := lexer.next()
+		if err != nil {
+			return raw
+		}
+
+		if tok.kind == tokenEOF {
+			break
+		}
+
+		if tok.kind != tokenTerm || tok.field != "domain" || !InDomain(tok.value, from) {
+			continue
+		}
+
+		start := tok.valuePos
+		if raw[start] == '"' {
+			start++
+		}
+
+		renamed += raw[last:start] + to + strings.TrimPrefix(tok.value, from)
+		last = start + len(tok.value)
+	}
+
+	return renamed + raw[last:]
+}
@@ -0,0 +1,312 @@
+package meetup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"text/template"
+	"time"
+)
+
+// DefaultRollupName is the name of rollup notes when their config does not set one.
+const DefaultRollupName = "summary"
+
+// DefaultRollupTemplate is used to generate rollup notes when their config does not set a template. See RollupData for
+// the fields available to rollup templates. Tasks are listed with their state's symbol rather than a checkbox, so that
+// they are not tasks of the rollup note itself.
+const DefaultRollupTemplate = `# {{ .Label }} rollup ({{ .Start }} to {{ .End }})
+
+{{ len .Meetings }} meetings, {{ len .Opened }} tasks opened, {{ len .Closed }} tasks closed.
+{{ range .Meetings }}
+## [{{ .Date }} {{ .Domain }} {{ .FullName }}]({{ .Link }})
+{{ if .Headings }}
+{{ range .Headings }}- {{ . }}
+{{ end }}{{ end }}{{ if .Opened }}
+Opened:
+
+{{ range .Opened }}- {{ .State }} {{ .Description }}
+{{ end }}{{ end }}{{ if .Closed }}
+Closed:
+
+{{ range .Closed }}- {{ .State }} {{ .Description }}
+{{ end }}{{ end }}{{ end }}
+## Notes
+`
+
+// RollupConfig configures the rollup notes for a period.
+type RollupConfig struct {
+	// Domain is the domain rollup notes are created in, defaulting to rollup.weekly or rollup.monthly.
+	Domain string `yaml:"domain,omitempty"`
+
+	// Name is the name of rollup notes, defaulting to DefaultRollupName.
+	Name string `yaml:"name,omitempty"`
+
+	// Template is the name of the template rollup notes are generated from, defaulting to DefaultRollupTemplate.
+	Template string `yaml:"template,omitempty"`
+}
+
+// validateRollupPeriod checks that rollups can be made for the period.
+func validateRollupPeriod(period StatsPeriod) error {
+	switch period {
+	case PeriodWeek, PeriodMonth:
+		return nil
+	default:
+		return fmt.Errorf("invalid rollup period '%s': expected one of week or month", period)
+	}
+}
+
+// RollupConfig returns the config for rollup notes for the period, with defaults for unset fields.
+func (m *Manager) RollupConfig(period StatsPeriod) RollupConfig {
+	config := m.metadata.Rollups[period]
+
+	if config.Domain == "" && period == PeriodMonth {
+		config.Domain = "rollup.monthly"
+	} else if config.Domain == "" {
+		config.Domain = "rollup.weekly"
+	}
+
+	if config.Name == "" {
+		config.Name = DefaultRollupName
+	}
+
+	return config
+}
+
+// RollupMeeting is a meeting in a rollup.
+type RollupMeeting struct {
+	Meeting
+
+	// Link is the relative link from the rollup note to the meeting.
+	Link string
+
+	// Headings are the text of the meeting's headings.
+	Headings []string
+
+	// Tasks are all of the meeting's tasks, Opened are the tasks first seen in the meeting, and Closed are the tasks
+	// first seen done in the meeting. See Digest.
+	Tasks  []Task
+	Opened []Task
+	Closed []Task
+}
+
+// RollupData is passed to the template generating a rollup note.
+type RollupData struct {
+	// Meeting is the rollup note itself.
+	Meeting Meeting
+
+	Period StatsPeriod
+
+	// Label names the period, like 2026-W43 or 2026-10, and Start and End are its first and last days in DateFormat.
+	Label string
+	Start string
+	End   string
+
+	// Meetings are the meetings in the period, excluding rollup notes, in order.
+	Meetings []RollupMeeting
+
+	// Opened and Closed are the tasks opened and closed across every meeting in the period.
+	Opened []Task
+	Closed []Task
+}
+
+// rollupLabel names the period starting at start.
+func rollupLabel(period StatsPeriod, start time.Time) string {
+	if period == PeriodMonth {
+		return start.Format("2006-01")
+	}
+
+	year, week := start.ISOWeek()
+
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// Rollup gathers the data for the rollup note of the week or month containing date. The rollup note is dated on the
+// first day of the period.
+func (m *Manager) Rollup(period StatsPeriod, date time.Time) (RollupData, error) {
+	if err := validateRollupPeriod(period); err != nil {
+		return RollupData{}, err
+	}
+
+	start := period.start(date)
+	end := period.next(start).AddDate(0, 0, -1)
+	config := m.RollupConfig(period)
+
+	data := RollupData{
+		Meeting: Meeting{Name: config.Name, Domain: config.Domain, Date: start.Format(DateFormat), Template: config.Template},
+		Period:  period,
+		Label:   rollupLabel(period, start),
+		Start:   start.Format(DateFormat),
+		End:     end.Format(DateFormat),
+		Opened:  []Task{},
+		Closed:  []Task{},
+	}
+
+	meetings, err := m.ListMeetings(MeetingQuery{Since: start, Until: end})
+	if err != nil {
+		return RollupData{}, err
+	}
+
+	var rollupDomains []string
+	for _, p := range []StatsPeriod{PeriodWeek, PeriodMonth} {
+		rollupDomains = append(rollupDomains, m.RollupConfig(p).Domain)
+	}
+
+	noteDir := path.Dir(m.pathForMeeting(data.Meeting))
+
+	var domains []string
+
+	for _, meeting := range meetings {
+		if inAnyDomain(meeting.Domain, rollupDomains) {
+			continue
+		}
+
+		data.Meetings = append(data.Meetings, RollupMeeting{
+			Meeting: meeting,
+			Link:    relativeLink(noteDir, m.pathForMeeting(meeting)),
+		})
+
+		domains = append(domains, meeting.Domain)
+	}
+
+	for i := range data.Meetings {
+		meeting := &data.Meetings[i]
+
+		if meeting.Headings, meeting.Tasks, err = m.readRollupMeeting(meeting.Meeting); err != nil {
+			return RollupData{}, err
+		}
+	}
+
+	histories, err := m.taskHistories(context.Background(), domains)
+	if err != nil {
+		return RollupData{}, err
+	}
+
+	for i := range data.Meetings {
+		meeting := &data.Meetings[i]
+		meeting.Opened, meeting.Closed = []Task{}, []Task{}
+
+		for _, task := range meeting.Tasks {
+			run := histories[identify(task)].at(meeting.Date)
+
+			if run.opened == meeting.Date {
+				meeting.Opened = append(meeting.Opened, task)
+			}
+
+			if task.State.Done && run.closed == meeting.Date {
+				meeting.Closed = append(meeting.Closed, task)
+			}
+		}
+
+		data.Opened = append(data.Opened, latestTasks(meeting.Opened)...)
+		data.Closed = append(data.Closed, latestTasks(meeting.Closed)...)
+	}
+
+	// the same task may be opened or closed in several meetings on the same day
+	data.Opened = latestTasks(data.Opened)
+	data.Closed = latestTasks(data.Closed)
+
+	return data, nil
+}
+
+// inAnyDomain reports whether domain is in any of the parent domains.
+func inAnyDomain(domain string, parents []string) bool {
+	for _, parent := range parents {
+		if InDomain(domain, parent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readRollupMeeting returns the headings and tasks in the meeting.
+func (m *Manager) readRollupMeeting(meeting Meeting) ([]string, []Task, error) {
+	file, err := m.openMeeting(meeting)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not open meeting '%s': %w", meeting, err)
+	}
+	defer file.Close()
+
+	contents, err := io.ReadAll(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read meeting '%s': %w", meeting, err)
+	}
+
+	headings, err := parseHeadings(bytes.NewReader(contents))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read meeting '%s': %w", meeting, err)
+	}
+
+	tasks, err := parseTasks(bytes.NewReader(contents), meeting, m.TaskStates(meeting.Domain))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read meeting '%s': %w", meeting, err)
+	}
+
+	return headings, tasks, nil
+}
+
+// RenderRollup renders the rollup note using the template from its config, or DefaultRollupTemplate.
+func (m *Manager) RenderRollup(w io.Writer, data RollupData) error {
+	if data.Meeting.Template != "" {
+		if !m.TemplateExists(data.Meeting.Template) {
+			return fmt.Errorf("template '%s' does not exist", data.Meeting.Template)
+		}
+
+		return m.executeTemplate(w, data.Meeting.Template, data)
+	}
+
+	tmpl, err := template.New("rollup").Parse(DefaultRollupTemplate)
+	if err != nil {
+		return fmt.Errorf("could not parse template: %w", err)
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("could not execute template: %w", err)
+	}
+
+	return nil
+}
+
+// CreateRollup generates the rollup note of the week or month containing date, returning the note. An existing note is
+// left alone so that it can be curated, unless regenerate is set, in which case the previous note is moved to the
+// trash.
+func (m *Manager) CreateRollup(period StatsPeriod, date time.Time, regenerate bool) (Meeting, error) {
+	data, err := m.Rollup(period, date)
+	if err != nil {
+		return Meeting{}, err
+	}
+
+	notePath := m.pathForMeeting(data.Meeting)
+
+	_, err = os.Stat(notePath)
+	exists := err == nil
+
+	if exists && !regenerate {
+		return data.Meeting, nil
+	}
+
+	// render before trashing the existing rollup so a broken template does not lose it
+	var buffer bytes.Buffer
+	if err := m.RenderRollup(&buffer, data); err != nil {
+		return Meeting{}, err
+	}
+
+	if exists {
+		if _, err := m.trash(data.Meeting, notePath, false); err != nil {
+			return Meeting{}, fmt.Errorf("could not regenerate rollup: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(path.Dir(notePath), 0755); err != nil {
+		return Meeting{}, fmt.Errorf("could not create meeting directory: %w", err)
+	}
+
+	if err := os.WriteFile(notePath, buffer.Bytes(), 0644); err != nil {
+		return Meeting{}, fmt.Errorf("could not write rollup: %w", err)
+	}
+
+	return data.Meeting, nil
+}
@@ -0,0 +1,278 @@
+package meetup
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// StatsPeriod is the length of the periods Stats groups meetings and tasks into.
+type StatsPeriod string
+
+const (
+	PeriodDay   StatsPeriod = "day"
+	PeriodWeek  StatsPeriod = "week"
+	PeriodMonth StatsPeriod = "month"
+	PeriodYear  StatsPeriod = "year"
+)
+
+func (p StatsPeriod) Validate() error {
+	switch p {
+	case PeriodDay, PeriodWeek, PeriodMonth, PeriodYear:
+		return nil
+	default:
+		return fmt.Errorf("invalid period '%s': expected one of day, week, month, or year", p)
+	}
+}
+
+// start returns the first day of the period containing t.
+func (p StatsPeriod) start(t time.Time) time.Time {
+	switch p {
+	case PeriodWeek:
+		start, _ := Week(t)
+		return start
+	case PeriodMonth:
+		start, _ := Month(t)
+		return start
+	case PeriodYear:
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return truncateDay(t)
+	}
+}
+
+// next returns the first day of the period after the one starting at start.
+func (p StatsPeriod) next(start time.Time) time.Time {
+	switch p {
+	case PeriodWeek:
+		return start.AddDate(0, 0, 7)
+	case PeriodMonth:
+		return start.AddDate(0, 1, 0)
+	case PeriodYear:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// label names the period starting at start. Weeks are named by their first (Monday) day.
+func (p StatsPeriod) label(start time.Time) string {
+	switch p {
+	case PeriodMonth:
+		return start.Format("2006-01")
+	case PeriodYear:
+		return start.Format("2006")
+	default:
+		return start.Format(DateFormat)
+	}
+}
+
+type StatsOptions struct {
+	// Period defaults to PeriodWeek.
+	Period StatsPeriod
+
+	// Oldest is the number of the oldest open tasks to include.
+	Oldest int
+
+	// Now is the time task ages are measured from, defaulting to the current time.
+	Now time.Time
+}
+
+// StatsRow counts the meetings and tasks in a domain, a period, or both. Tasks are counted in the domain and period of
+// the meeting they are in.
+type StatsRow struct {
+	Domain string `json:"domain,omitempty"`
+	Period string `json:"period,omitempty"`
+
+	Meetings    int `json:"meetings"`
+	OpenTasks   int `json:"open_tasks"`
+	ClosedTasks int `json:"closed_tasks"`
+
+	// AverageAge is the mean number of days since the open tasks were first seen.
+	AverageAge float64 `json:"average_age_days"`
+}
+
+type Stats struct {
+	Total StatsRow `json:"total"`
+
+	// Domains are sorted by domain, and Periods are in chronological order with a row for every period between the
+	// first and last meetings.
+	Domains []StatsRow `json:"domains"`
+	Periods []StatsRow `json:"periods"`
+
+	// Rows break the counts down by both domain and period, sorted by domain then period.
+	Rows []StatsRow `json:"rows"`
+
+	// Oldest are the open tasks which were first seen the longest ago, oldest first.
+	Oldest []Task `json:"oldest"`
+}
+
+// Stats counts the meetings matching the query's meeting query, and the open and closed tasks matching the query,
+// grouped by domain and period.
+func (m *Manager) Stats(query TaskQuery, opts StatsOptions) (Stats, error) {
+	if opts.Period == "" {
+		opts.Period = PeriodWeek
+	}
+
+	if err := opts.Period.Validate(); err != nil {
+		return Stats{}, err
+	}
+
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	meetings, err := m.ListMeetings(query.Meeting)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	tasks, err := m.Tasks(query)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	if err := m.trackTasks(context.Background(), tasks, opts.Now); err != nil {
+		return Stats{}, err
+	}
+
+	type key struct {
+		domain string
+		period string
+	}
+
+	type accumulator struct {
+		StatsRow
+		totalAge float64
+	}
+
+	rows := map[key]*accumulator{}
+
+	// matching returns the rows a meeting counts towards: the total, its domain, its period, and both
+	matching := func(meeting Meeting) ([]*accumulator, error) {
+		date, err := time.ParseInLocation(DateFormat, meeting.Date, opts.Now.Location())
+		if err != nil {
+			return nil, fmt.Errorf("could not parse date for meeting '%s': %w", meeting, err)
+		}
+
+		period := opts.Period.label(opts.Period.start(date))
+
+		var matched []*accumulator
+
+		for _, k := range []key{{"", ""}, {meeting.Domain, ""}, {"", period}, {meeting.Domain, period}} {
+			row, found := rows[k]
+			if !found {
+				row = &accumulator{StatsRow: StatsRow{Domain: k.domain, Period: k.period}}
+				rows[k] = row
+			}
+
+			matched = append(matched, row)
+		}
+
+		return matched, nil
+	}
+
+	var first, last string
+
+	for _, meeting := range meetings {
+		matched, err := matching(meeting)
+		if err != nil {
+			return Stats{}, err
+		}
+
+		for _, row := range matched {
+			row.Meetings++
+		}
+
+		if first == "" || meeting.Date < first {
+			first = meeting.Date
+		}
+
+		if meeting.Date > last {
+			last = meeting.Date
+		}
+	}
+
+	var open []Task
+
+	for _, task := range tasks {
+		matched, err := matching(task.Meeting)
+		if err != nil {
+			return Stats{}, err
+		}
+
+		if task.State.Done {
+			for _, row := range matched {
+				row.ClosedTasks++
+			}
+
+			continue
+		}
+
+		for _, row := range matched {
+			row.OpenTasks++
+			row.totalAge += time.Duration(task.Age).Hours() / 24
+		}
+
+		open = append(open, task)
+	}
+
+	stats := Stats{
+		Domains: []StatsRow{},
+		Periods: []StatsRow{},
+		Rows:    []StatsRow{},
+		Oldest:  []Task{},
+	}
+
+	// include empty periods so that charts are evenly spaced
+	if first != "" {
+		start, _ := time.ParseInLocation(DateFormat, first, opts.Now.Location())
+		end, _ := time.ParseInLocation(DateFormat, last, opts.Now.Location())
+
+		for period := opts.Period.start(start); !period.After(end); period = opts.Period.next(period) {
+			k := key{period: opts.Period.label(period)}
+			if _, found := rows[k]; !found {
+				rows[k] = &accumulator{StatsRow: StatsRow{Period: k.period}}
+			}
+		}
+	}
+
+	for k, row := range rows {
+		if row.OpenTasks > 0 {
+			row.AverageAge = row.totalAge / float64(row.OpenTasks)
+		}
+
+		switch {
+		case k.domain == "" && k.period == "":
+			stats.Total = row.StatsRow
+		case k.period == "":
+			stats.Domains = append(stats.Domains, row.StatsRow)
+		case k.domain == "":
+			stats.Periods = append(stats.Periods, row.StatsRow)
+		default:
+			stats.Rows = append(stats.Rows, row.StatsRow)
+		}
+	}
+
+	compareRows := func(a, b StatsRow) int {
+		if c := strings.Compare(a.Domain, b.Domain); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Period, b.Period)
+	}
+
+	slices.SortFunc(stats.Domains, compareRows)
+	slices.SortFunc(stats.Periods, compareRows)
+	slices.SortFunc(stats.Rows, compareRows)
+
+	slices.SortStableFunc(open, func(a, b Task) int {
+		return strings.Compare(a.FirstSeen, b.FirstSeen)
+	})
+
+	stats.Oldest = append(stats.Oldest, open[:min(len(open), max(0, opts.Oldest))]...)
+
+	return stats, nil
+}
@@ -1,116 +1,208 @@
 package meetup
 
 import (
-	"bufio"
-	"os"
-	"strings"
-	"sync"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/gobwas/glob"
 )
 
+// The prefixes of tasks in the default todo and done states.
 const (
 	DefaultTaskPrefix          = "- [ ] "
 	DefaultTaskCompletedPrefix = "- [x] "
 )
 
 type Task struct {
-	Meeting     Meeting
-	Complete    bool
-	Description string
+	Meeting     Meeting   `json:"meeting"`
+	State       TaskState `json:"state"`
+	Description string    `json:"description"`
+
+	// Line is the line number of the task in its meeting, starting from 1.
+	Line int `json:"line"`
+
+	// Section is the text of the closest heading above the task, if any.
+	Section string `json:"section,omitempty"`
+
+	// Due is the date (in DateFormat) given by a due:<date> in the description, if any. See findDue.
+	Due string `json:"due,omitempty"`
+
+	// Depth is how many tasks the task is nested under, and ParentLine is the line of the task it is directly nested
+	// under, or 0 for top level tasks.
+	Depth      int `json:"depth,omitempty"`
+	ParentLine int `json:"parent_line,omitempty"`
+
+	// Occurrence is the number of earlier tasks in the meeting with the same description, which tells apart the IDs of
+	// tasks with the same description.
+	Occurrence int `json:"occurrence,omitempty"`
+
+	// FirstSeen is the date of the earliest meeting in the task's domain containing the same task, and Age is the time
+	// since then in whole days. Both are only set when the task query tracks ages.
+	FirstSeen string   `json:"first_seen,omitempty"`
+	Age       Duration `json:"age,omitempty"`
+}
+
+// digest hashes the task's meeting, normalized description, and occurrence.
+func (t Task) digest() [sha1.Size]byte {
+	key := t.Meeting.GetPath("", GroupByDate) + "\x00" + normalizeDescription(t.Description)
+	if t.Occurrence > 0 {
+		key += "\x00" + strconv.Itoa(t.Occurrence)
+	}
+
+	return sha1.Sum([]byte(key))
+}
+
+// ID identifies the task by its meeting and description rather than its line, so that it does not change when the
+// meeting is edited around it. Descriptions are compared like when tracking task ages, so changing a task's hashtags
+// or due date keeps its ID, and tasks with the same description in the same meeting are told apart by their
+// Occurrence. Moving the task's meeting changes its ID, but the moves are recorded (see MovesFilename) so tasks
+// exported before the move are still matched when syncing or importing.
+func (t Task) ID() string {
+	digest := t.digest()
+	return hex.EncodeToString(digest[:8])
+}
+
+// Tags returns the hashtags (ie #urgent) in the task's description, without the leading '#'.
+func (t Task) Tags() []string {
+	return findTags(t.Description)
 }
 
 type TaskQuery struct {
-	Meeting     MeetingQuery
-	Complete    *bool
+	Meeting MeetingQuery
+
+	// Complete matches tasks whose state is done, or when false whose state is not done.
+	Complete *bool
+
+	// States matches tasks in any of the named states.
+	States []string
+
 	Description glob.Glob
+
+	// TrackAge sets the FirstSeen and Age of the matching tasks, which requires reading every meeting in their domains.
+	TrackAge bool
+
+	// Stale matches only incomplete tasks which were first seen longer ago than Stale, if it is set. Stale implies
+	// TrackAge.
+	Stale Duration
+
+	// Query optionally further restricts the matching tasks, see ParseTaskQuery.
+	Query *Query
 }
 
 func (t TaskQuery) Match(task Task) bool {
 	return t.Meeting.Match(task.Meeting) &&
-		(t.Complete == nil || *t.Complete == task.Complete) &&
-		t.Description.Match(task.Description)
+		(t.Complete == nil || *t.Complete == task.State.Done) &&
+		(len(t.States) == 0 || slices.Contains(t.States, task.State.Name)) &&
+		matchGlob(t.Description, task.Description) &&
+		(t.Query == nil || t.Query.MatchTask(task))
 }
 
 func (m *Manager) searchMeeting(meeting Meeting, query TaskQuery) ([]Task, error) {
-	tasks := []Task{}
-
-	// meetingPath := m.pathForMeeting(m.metadata.GroupBy, meeting, false)
-	meetingPath := meeting.GetPath(m.RootDir, m.metadata.GroupBy)
+	meetingFile, err := m.openMeeting(meeting)
+	if err != nil {
+		return nil, fmt.Errorf("could not open meeting '%s': %w", meeting, err)
+	}
+	defer meetingFile.Close()
 
-	meetingFile, err := os.Open(meetingPath)
+	tasks, err := parseTasks(meetingFile, meeting, m.TaskStates(meeting.Domain))
 	if err != nil {
+		return nil, fmt.Errorf("could not read meeting '%s': %w", meeting, err)
+	}
+
+	return slices.DeleteFunc(tasks, func(task Task) bool {
+		return !query.Match(task)
+	}), nil
+}
+
+// parallelism returns the number of meetings to read at once.
+func (m *Manager) parallelism() int {
+	if m.Parallelism > 0 {
+		return m.Parallelism
+	}
+
+	return runtime.NumCPU()
+}
+
+// scanMeetings calls fn for each meeting on a job queue, and returns the results in the same order as the meetings.
+// Meetings which fail do not stop the others from being scanned, and every error is returned joined in the order of
+// the meetings. If ctx is cancelled, no more meetings are scanned and the context's error is returned.
+func scanMeetings[T any](ctx context.Context, workers int, meetings []Meeting, fn func(Meeting) (T, error)) ([]T, error) {
+	jq := NewJobQueueContext(ctx, workers, CollectAll)
+	pending := make([]*JobResult[T], len(meetings))
+
+	for i, meeting := range meetings {
+		meeting := meeting
+
+		pending[i] = Submit(jq, func(context.Context) (T, error) {
+			return fn(meeting)
+		})
+	}
+
+	// errors are gathered from the results below to keep them in order
+	_ = jq.Wait()
+
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	scanner := bufio.NewScanner(meetingFile)
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		var task Task
-		switch {
-		case strings.HasPrefix(line, DefaultTaskPrefix):
-			task = Task{
-				Meeting:     meeting,
-				Complete:    false,
-				Description: strings.TrimPrefix(line, DefaultTaskPrefix),
-			}
-		case strings.HasPrefix(line, DefaultTaskCompletedPrefix):
-			task = Task{
-				Meeting:     meeting,
-				Complete:    true,
-				Description: strings.TrimPrefix(line, DefaultTaskCompletedPrefix),
-			}
-		default:
-			continue
-		}
-
-		if !query.Match(task) {
-			continue
-		}
-
-		tasks = append(tasks, task)
+	results := make([]T, len(meetings))
+	errs := make([]error, len(meetings))
+
+	for i, result := range pending {
+		results[i], errs[i] = result.Get()
 	}
 
-	return tasks, nil
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
 }
 
+// Tasks returns the tasks matching the query, ordered by meeting and then by their position in the meeting.
 func (m *Manager) Tasks(query TaskQuery) ([]Task, error) {
+	return m.TasksContext(context.Background(), query)
+}
+
+// TasksContext is like Tasks, but stops reading meetings once ctx is cancelled.
+func (m *Manager) TasksContext(ctx context.Context, query TaskQuery) ([]Task, error) {
 	meetings, err := m.ListMeetings(query.Meeting)
 	if err != nil {
 		return nil, err
 	}
 
-	tasks := []Task{}
-
-	jq := NewJobQueue(5)
-	errChan := make(chan error)
-	wg := sync.WaitGroup{}
-	wg.Add(len(meetings))
+	found, err := scanMeetings(ctx, m.parallelism(), meetings, func(meeting Meeting) ([]Task, error) {
+		return m.searchMeeting(meeting, query)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not search meetings for tasks: %w", err)
+	}
 
-	for _, meeting := range meetings {
-		select {
-		case err := <-errChan:
-			return nil, err
-		default:
-		}
+	tasks := []Task{}
+	for _, meetingTasks := range found {
+		tasks = append(tasks, meetingTasks...)
+	}
 
-		meeting := meeting
+	if !query.TrackAge && query.Stale <= 0 {
+		return tasks, nil
+	}
 
-		jq.Run(func() {
-			foundTasks, err := m.searchMeeting(meeting, query)
-			if err != nil {
-				errChan <- err
-				return
-			}
+	if err := m.trackTasks(ctx, tasks, time.Now()); err != nil {
+		return nil, err
+	}
 
-			tasks = append(tasks, foundTasks...)
-			wg.Done()
+	if query.Stale > 0 {
+		tasks = slices.DeleteFunc(tasks, func(task Task) bool {
+			return task.State.Done || task.Age <= query.Stale
 		})
 	}
 
-	wg.Wait()
-
 	return tasks, nil
 }
@@ -0,0 +1,172 @@
+package meetup
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// taskIdentity identifies the same task carried over between meetings.
+type taskIdentity struct {
+	domain      string
+	description string
+}
+
+// normalizeDescription returns the words of a task's description in lower case, ignoring punctuation, whitespace,
+// hashtags, and due dates.
+func normalizeDescription(description string) string {
+	description = dueRegex.ReplaceAllString(hashtagRegex.ReplaceAllString(description, " "), " ")
+
+	fields := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	return strings.Join(fields, " ")
+}
+
+// identify returns the task's identity. Descriptions are compared ignoring case, punctuation, whitespace, hashtags,
+// and due dates, so "Fix the flaky test" and "fix the flaky test! #urgent due:friday" are the same task.
+func identify(task Task) taskIdentity {
+	return taskIdentity{
+		domain:      task.Meeting.Domain,
+		description: normalizeDescription(task.Description),
+	}
+}
+
+// taskRun is a span of meetings in which a task was open, from the meeting it was first seen in until the first
+// meeting it was seen done in. A closed run is empty if the task has not been seen done yet.
+type taskRun struct {
+	opened string
+	closed string
+}
+
+// taskHistory is every run of a task in order. Recurring tasks which are seen open again after being done start a new
+// run.
+type taskHistory []taskRun
+
+// at returns the run containing the date, which is the latest run opened on or before it.
+func (h taskHistory) at(date string) taskRun {
+	for i := len(h) - 1; i >= 0; i-- {
+		if h[i].opened <= date {
+			return h[i]
+		}
+	}
+
+	return taskRun{}
+}
+
+// taskSighting is a task seen in a meeting on a date.
+type taskSighting struct {
+	date string
+	done bool
+}
+
+// taskHistories returns the history of every task in the meetings (including archived meetings) in the given domains.
+func (m *Manager) taskHistories(ctx context.Context, domains []string) (map[taskIdentity]taskHistory, error) {
+	histories := map[taskIdentity]taskHistory{}
+
+	if len(domains) == 0 {
+		return histories, nil
+	}
+
+	meetings, err := m.ListMeetings(MeetingQuery{IncludeArchived: true})
+	if err != nil {
+		return nil, err
+	}
+
+	meetings = slices.DeleteFunc(meetings, func(meeting Meeting) bool {
+		return !slices.Contains(domains, meeting.Domain)
+	})
+
+	found, err := scanMeetings(ctx, m.parallelism(), meetings, func(meeting Meeting) ([]Task, error) {
+		return m.searchMeeting(meeting, TaskQuery{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not search meetings for tasks: %w", err)
+	}
+
+	sightings := map[taskIdentity][]taskSighting{}
+
+	for _, meetingTasks := range found {
+		for _, task := range meetingTasks {
+			id := identify(task)
+			sightings[id] = append(sightings[id], taskSighting{date: task.Meeting.Date, done: task.State.Done})
+		}
+	}
+
+	for id, seen := range sightings {
+		// a task seen both open and done on the same day was done that day
+		slices.SortFunc(seen, func(a, b taskSighting) int {
+			switch {
+			case a.date != b.date:
+				return strings.Compare(a.date, b.date)
+			case a.done == b.done:
+				return 0
+			case b.done:
+				return -1
+			default:
+				return 1
+			}
+		})
+
+		var history taskHistory
+
+		for _, sighting := range seen {
+			if len(history) == 0 || history[len(history)-1].closed != "" && !sighting.done {
+				history = append(history, taskRun{opened: sighting.date})
+			}
+
+			if run := &history[len(history)-1]; sighting.done && run.closed == "" {
+				run.closed = sighting.date
+			}
+		}
+
+		histories[id] = history
+	}
+
+	return histories, nil
+}
+
+// taskDomains returns the unique domains of the tasks' meetings.
+func taskDomains(tasks []Task) []string {
+	var domains []string
+	for _, task := range tasks {
+		if !slices.Contains(domains, task.Meeting.Domain) {
+			domains = append(domains, task.Meeting.Domain)
+		}
+	}
+
+	return domains
+}
+
+// trackTasks sets the FirstSeen and Age of each task, using the earliest meeting (including archived meetings) in the
+// task's domain containing the same task since it was last seen done. Ages are measured in whole days up to now.
+func (m *Manager) trackTasks(ctx context.Context, tasks []Task, now time.Time) error {
+	histories, err := m.taskHistories(ctx, taskDomains(tasks))
+	if err != nil {
+		return err
+	}
+
+	today := truncateDay(now)
+
+	for i := range tasks {
+		tasks[i].FirstSeen = tasks[i].Meeting.Date
+
+		if seen := histories[identify(tasks[i])].at(tasks[i].Meeting.Date).opened; seen != "" && seen < tasks[i].FirstSeen {
+			tasks[i].FirstSeen = seen
+		}
+
+		date, err := time.ParseInLocation(DateFormat, tasks[i].FirstSeen, now.Location())
+		if err != nil {
+			return fmt.Errorf("could not parse date for meeting '%s': %w", tasks[i].Meeting, err)
+		}
+
+		days := max(0, int(today.Sub(date).Hours()/24+0.5))
+		tasks[i].Age = Duration(time.Duration(days) * time.Hour * 24)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package meetup
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// findTask returns the task in tasks at the same line as task, or if it has moved since task was read, the first task
+// with the same ID.
+func findTask(tasks []Task, task Task) (Task, bool) {
+	id := task.ID()
+	var moved *Task
+
+	for i := range tasks {
+		if tasks[i].ID() != id {
+			continue
+		}
+
+		if tasks[i].Line == task.Line {
+			return tasks[i], true
+		}
+
+		if moved == nil {
+			moved = &tasks[i]
+		}
+	}
+
+	if moved == nil {
+		return Task{}, false
+	}
+
+	return *moved, true
+}
+
+// SetTaskState changes the state of a task in its meeting to the named state. The task is found by its line, or by
+// its ID if the meeting was edited since the task was read. Tasks in archived meetings cannot be changed.
+func (m *Manager) SetTaskState(task Task, name string) (Task, error) {
+	if task.Meeting.Archived {
+		return Task{}, fmt.Errorf("cannot change tasks in archived meeting '%s'", task.Meeting)
+	}
+
+	states := m.TaskStates(task.Meeting.Domain)
+
+	state, err := findTaskState(states, name)
+	if err != nil {
+		return Task{}, err
+	}
+
+	notePath := m.pathForMeeting(task.Meeting)
+
+	data, err := os.ReadFile(notePath)
+	if err != nil {
+		return Task{}, fmt.Errorf("could not read meeting '%s': %w", task.Meeting, err)
+	}
+
+	tasks, err := parseTasks(bytes.NewReader(data), task.Meeting, states)
+	if err != nil {
+		return Task{}, fmt.Errorf("could not read meeting '%s': %w", task.Meeting, err)
+	}
+
+	current, found := findTask(tasks, task)
+	if !found {
+		return Task{}, fmt.Errorf("could not find task '%s' in meeting '%s'", task.Description, task.Meeting)
+	}
+
+	if current.State == state {
+		return current, nil
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	line := lines[current.Line-1]
+	content := strings.TrimRight(line, "\r\n")
+
+	lines[current.Line-1] = setLineState(content, current.State, state) + line[len(content):]
+
+	if err := os.WriteFile(notePath, []byte(strings.Join(lines, "")), 0644); err != nil {
+		return Task{}, fmt.Errorf("could not update meeting '%s': %w", task.Meeting, err)
+	}
+
+	current.State = state
+
+	return current, nil
+}
+
+// AddTask appends a task with the description in the named state to the end of an existing meeting.
+func (m *Manager) AddTask(meeting Meeting, description string, name string) (Task, error) {
+	if meeting.Archived {
+		return Task{}, fmt.Errorf("cannot add tasks to archived meeting '%s'", meeting)
+	}
+
+	if !m.MeetingExists(meeting) {
+		return Task{}, fmt.Errorf("meeting '%s' does not exist", meeting)
+	}
+
+	states := m.TaskStates(meeting.Domain)
+
+	state, err := findTaskState(states, name)
+	if err != nil {
+		return Task{}, err
+	}
+
+	notePath := m.pathForMeeting(meeting)
+
+	data, err := os.ReadFile(notePath)
+	if err != nil {
+		return Task{}, fmt.Errorf("could not read meeting '%s': %w", meeting, err)
+	}
+
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+
+	description = strings.Join(strings.Fields(description), " ")
+	data = append(data, formatTask(description, state)+"\n"...)
+
+	if err := os.WriteFile(notePath, data, 0644); err != nil {
+		return Task{}, fmt.Errorf("could not update meeting '%s': %w", meeting, err)
+	}
+
+	added := Task{
+		Meeting:     meeting,
+		State:       state,
+		Description: description,
+		Line:        bytes.Count(data, []byte("\n")),
+	}
+
+	// pick up the section and parent the task was added under
+	if tasks, err := parseTasks(bytes.NewReader(data), meeting, states); err == nil && len(tasks) > 0 && tasks[len(tasks)-1].Line == added.Line {
+		added = tasks[len(tasks)-1]
+	}
+
+	return added, nil
+}
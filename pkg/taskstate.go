@@ -0,0 +1,188 @@
+package meetup
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// TaskState is a named state a task can be in. Tasks are identified either by the character in a task list item's
+// checkbox (ie the "x" in "- [x] write tests") or by the start of the line (ie "TODO write tests").
+type TaskState struct {
+	Name string `yaml:"name" json:"name"`
+
+	// Marker is the single character between the brackets of the checkbox. Letters match regardless of case.
+	Marker string `yaml:"marker,omitempty" json:"marker,omitempty"`
+
+	// Prefix identifies tasks in this state by the start of the line rather than by a checkbox.
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+
+	// Symbol is shown for tasks in this state when listing tasks.
+	Symbol string `yaml:"symbol,omitempty" json:"symbol,omitempty"`
+
+	// Done states count as complete.
+	Done bool `yaml:"done,omitempty" json:"done,omitempty"`
+}
+
+var (
+	// TaskTodo is the default state of incomplete tasks ("- [ ]").
+	TaskTodo = TaskState{Name: "todo", Marker: " ", Symbol: "❌"}
+
+	// TaskDone is the default state of completed tasks ("- [x]").
+	TaskDone = TaskState{Name: "done", Marker: "x", Symbol: "✅", Done: true}
+)
+
+// DefaultTaskStates returns the task states used when none are configured.
+func DefaultTaskStates() []TaskState {
+	return []TaskState{TaskTodo, TaskDone}
+}
+
+func (s TaskState) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("task state name cannot be empty")
+	}
+
+	switch {
+	case s.Marker == "" && s.Prefix == "":
+		return fmt.Errorf("task state '%s' must have a marker or a prefix", s.Name)
+	case s.Marker != "" && s.Prefix != "":
+		return fmt.Errorf("task state '%s' cannot have both a marker and a prefix", s.Name)
+	case s.Marker != "" && (utf8.RuneCountInString(s.Marker) != 1 || s.Marker == "]"):
+		return fmt.Errorf("task state '%s' marker must be a single character other than ']'", s.Name)
+	}
+
+	return nil
+}
+
+// String returns the state's symbol, or how the state is written if it has none.
+func (s TaskState) String() string {
+	switch {
+	case s.Symbol != "":
+		return s.Symbol
+	case s.Marker != "":
+		return "[" + s.Marker + "]"
+	default:
+		return strings.TrimSpace(s.Prefix)
+	}
+}
+
+// findTaskState returns the state with the given name.
+func findTaskState(states []TaskState, name string) (TaskState, error) {
+	for _, state := range states {
+		if state.Name == name {
+			return state, nil
+		}
+	}
+
+	return TaskState{}, fmt.Errorf("unknown task state '%s'", name)
+}
+
+// doneState returns the state tasks are moved to when they are completed: the default done state if it is configured,
+// or else the first state which counts as done.
+func doneState(states []TaskState) (TaskState, error) {
+	return defaultState(states, TaskDone.Name, true)
+}
+
+// todoState returns the state tasks are moved to when they are reopened or added: the default todo state if it is
+// configured, or else the first state which does not count as done.
+func todoState(states []TaskState) (TaskState, error) {
+	return defaultState(states, TaskTodo.Name, false)
+}
+
+func defaultState(states []TaskState, name string, done bool) (TaskState, error) {
+	if state, err := findTaskState(states, name); err == nil && state.Done == done {
+		return state, nil
+	}
+
+	for _, state := range states {
+		if state.Done == done {
+			return state, nil
+		}
+	}
+
+	return TaskState{}, fmt.Errorf("no task state has done set to %t", done)
+}
+
+// validateTaskStates checks that every state is valid, and that no two states share a name, marker, or prefix.
+func validateTaskStates(states []TaskState) error {
+	names := map[string]bool{}
+	markers := map[string]bool{}
+	prefixes := map[string]bool{}
+
+	for _, state := range states {
+		if err := state.Validate(); err != nil {
+			return err
+		}
+
+		if names[state.Name] {
+			return fmt.Errorf("duplicate task state '%s'", state.Name)
+		}
+
+		if marker := strings.ToLower(state.Marker); marker != "" {
+			if markers[marker] {
+				return fmt.Errorf("task state '%s' reuses the marker '%s'", state.Name, state.Marker)
+			}
+
+			markers[marker] = true
+		}
+
+		if state.Prefix != "" {
+			if prefixes[state.Prefix] {
+				return fmt.Errorf("task state '%s' reuses the prefix '%s'", state.Name, state.Prefix)
+			}
+
+			prefixes[state.Prefix] = true
+		}
+
+		names[state.Name] = true
+	}
+
+	return nil
+}
+
+// mergeTaskStates replaces the states in states with the overrides of the same name, and appends the remaining
+// overrides.
+func mergeTaskStates(states []TaskState, overrides []TaskState) []TaskState {
+	merged := append([]TaskState{}, states...)
+
+	for _, override := range overrides {
+		replaced := false
+
+		for i, state := range merged {
+			if state.Name == override.Name {
+				merged[i] = override
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			merged = append(merged, override)
+		}
+	}
+
+	return merged
+}
+
+// TaskStates returns the task states used in meetings in the domain. States in the metadata and then the domain's
+// configuration override the default states with the same name, and add new states.
+func (m *Manager) TaskStates(domain string) []TaskState {
+	return mergeTaskStates(mergeTaskStates(DefaultTaskStates(), m.metadata.TaskStates), m.DomainConfig(domain).TaskStates)
+}
+
+// HasTaskState reports whether a task state with the name is used in any domain.
+func (m *Manager) HasTaskState(name string) bool {
+	domains := []string{""}
+	for domain := range m.metadata.Domains {
+		domains = append(domains, domain)
+	}
+
+	for _, domain := range domains {
+		if slices.ContainsFunc(m.TaskStates(domain), func(state TaskState) bool { return state.Name == name }) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,303 @@
+package meetup
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// TaskwarriorTimeFormat is the format of dates in taskwarrior's JSON.
+const TaskwarriorTimeFormat = "20060102T150405Z"
+
+// The statuses of taskwarrior tasks.
+const (
+	TaskwarriorPending   = "pending"
+	TaskwarriorCompleted = "completed"
+	TaskwarriorDeleted   = "deleted"
+	TaskwarriorWaiting   = "waiting"
+	TaskwarriorRecurring = "recurring"
+)
+
+// TaskwarriorTask is a task as exported and imported by taskwarrior (see https://taskwarrior.org/docs/design/task).
+type TaskwarriorTask struct {
+	UUID        string   `json:"uuid,omitempty"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Entry       string   `json:"entry,omitempty"`
+	End         string   `json:"end,omitempty"`
+	Due         string   `json:"due,omitempty"`
+	Project     string   `json:"project,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// TaskwarriorUUID returns the UUID used for the task in taskwarrior, which is derived from its ID.
+func TaskwarriorUUID(task Task) string {
+	digest := task.digest()
+	uuid := digest[:16]
+
+	// mark the uuid as name based (version 5) with the RFC 4122 variant
+	uuid[6] = uuid[6]&0x0f | 0x50
+	uuid[8] = uuid[8]&0x3f | 0x80
+
+	encoded := hex.EncodeToString(uuid)
+
+	return strings.Join([]string{encoded[:8], encoded[8:12], encoded[12:16], encoded[16:20], encoded[20:]}, "-")
+}
+
+// taskwarriorTime formats a date (in DateFormat) as midnight local time in taskwarrior's format.
+func taskwarriorTime(date string) string {
+	t, err := time.ParseInLocation(DateFormat, date, time.Local)
+	if err != nil {
+		return ""
+	}
+
+	return t.UTC().Format(TaskwarriorTimeFormat)
+}
+
+// NewTaskwarriorTask converts a task to taskwarrior. The task's domain becomes its project, and its hashtags become
+// tags. Dates are chosen like for NewTodoTxtItem, and tasks without a due date have no due date in taskwarrior.
+func NewTaskwarriorTask(task Task) TaskwarriorTask {
+	item := NewTodoTxtItem(task)
+
+	tw := TaskwarriorTask{
+		UUID:        TaskwarriorUUID(task),
+		Description: item.Description,
+		Status:      TaskwarriorPending,
+		Entry:       taskwarriorTime(item.Created),
+		Due:         taskwarriorTime(task.Due),
+		Project:     task.Meeting.Domain,
+		Tags:        task.Tags(),
+	}
+
+	if task.State.Done {
+		tw.Status = TaskwarriorCompleted
+		tw.End = taskwarriorTime(item.Completed)
+	}
+
+	return tw
+}
+
+// WriteTaskwarrior writes the tasks to w as a taskwarrior JSON array.
+func WriteTaskwarrior(w io.Writer, tasks []Task) error {
+	exported := make([]TaskwarriorTask, 0, len(tasks))
+	for _, task := range tasks {
+		exported = append(exported, NewTaskwarriorTask(task))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(exported); err != nil {
+		return fmt.Errorf("could not write taskwarrior json: %w", err)
+	}
+
+	return nil
+}
+
+// ReadTaskwarrior reads taskwarrior tasks from either a JSON array (as written by `task export`) or one JSON object per
+// line (as written by older versions of taskwarrior).
+func ReadTaskwarrior(r io.Reader) ([]TaskwarriorTask, error) {
+	reader := bufio.NewReader(r)
+
+	var first byte
+	for {
+		b, err := reader.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("could not read taskwarrior json: %w", err)
+		}
+
+		if !strings.ContainsRune(" \t\r\n", rune(b)) {
+			first = b
+			break
+		}
+	}
+
+	if err := reader.UnreadByte(); err != nil {
+		return nil, fmt.Errorf("could not read taskwarrior json: %w", err)
+	}
+
+	decoder := json.NewDecoder(reader)
+
+	if first == '[' {
+		var tasks []TaskwarriorTask
+		if err := decoder.Decode(&tasks); err != nil {
+			return nil, fmt.Errorf("could not parse taskwarrior json: %w", err)
+		}
+
+		return tasks, nil
+	}
+
+	var tasks []TaskwarriorTask
+
+	for {
+		var task TaskwarriorTask
+
+		err := decoder.Decode(&task)
+		if errors.Is(err, io.EOF) {
+			return tasks, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("could not parse taskwarrior json: %w", err)
+		}
+
+		tasks = append(tasks, task)
+	}
+}
+
+// TaskwarriorImport describes the changes made by ImportTaskwarrior.
+type TaskwarriorImport struct {
+	// Updated are the meeting tasks whose state changed.
+	Updated []Task
+
+	// Added are the tasks added to the target meeting.
+	Added []Task
+
+	// Skipped are the taskwarrior tasks which did not change any meeting.
+	Skipped []TaskwarriorTask
+}
+
+// ImportTaskwarrior applies taskwarrior tasks to meetings. Tasks exported from meetings (matched by their UUID, even if
+// their meeting was moved since) are completed or reopened to match their status, and deleted tasks are moved to the
+// "cancelled" state if it is configured. Other pending and completed tasks are added to the into meeting if it is not
+// nil, with their tags appended as hashtags.
+func (m *Manager) ImportTaskwarrior(tasks []TaskwarriorTask, into *Meeting) (TaskwarriorImport, error) {
+	var result TaskwarriorImport
+
+	existing, err := m.Tasks(TaskQuery{})
+	if err != nil {
+		return result, err
+	}
+
+	formers, err := m.formerMeetings()
+	if err != nil {
+		return result, err
+	}
+
+	byUUID := make(map[string]Task, len(existing))
+	ids := make(map[string]bool, len(existing))
+
+	for _, task := range existing {
+		byUUID[TaskwarriorUUID(task)] = task
+		ids[task.ID()] = true
+	}
+
+	// tasks exported before their meeting was moved are matched by their previous UUID
+	for _, task := range existing {
+		for _, former := range task.formerTasks(formers) {
+			if _, found := byUUID[TaskwarriorUUID(former)]; !found {
+				byUUID[TaskwarriorUUID(former)] = task
+			}
+		}
+	}
+
+	var errs []error
+
+	for _, tw := range tasks {
+		task, found := byUUID[strings.ToLower(tw.UUID)]
+
+		if !found {
+			added, err := m.importNewTask(tw, into, ids)
+			switch {
+			case err != nil:
+				errs = append(errs, err)
+			case added == nil:
+				result.Skipped = append(result.Skipped, tw)
+			default:
+				result.Added = append(result.Added, *added)
+				ids[added.ID()] = true
+			}
+
+			continue
+		}
+
+		states := m.TaskStates(task.Meeting.Domain)
+
+		var state TaskState
+		var err error
+
+		switch tw.Status {
+		case TaskwarriorCompleted:
+			if !task.State.Done {
+				state, err = doneState(states)
+			}
+		case TaskwarriorPending, TaskwarriorWaiting:
+			if task.State.Done {
+				state, err = todoState(states)
+			}
+		case TaskwarriorDeleted:
+			state, err = findTaskState(states, "cancelled")
+			if err != nil || state == task.State {
+				state, err = TaskState{}, nil
+			}
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if state.Name == "" {
+			result.Skipped = append(result.Skipped, tw)
+			continue
+		}
+
+		updated, err := m.SetTaskState(task, state.Name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		result.Updated = append(result.Updated, updated)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// importNewTask adds a taskwarrior task which was not exported from a meeting to the into meeting, returning nil if
+// it is not added. Tasks whose ID is in ids are already in the meeting (ie from importing the same file twice), and are
+// not added again.
+func (m *Manager) importNewTask(tw TaskwarriorTask, into *Meeting, ids map[string]bool) (*Task, error) {
+	if into == nil || strings.TrimSpace(tw.Description) == "" {
+		return nil, nil
+	}
+
+	description := tw.Description
+	for _, tag := range tw.Tags {
+		description += " #" + tag
+	}
+
+	if ids[(Task{Meeting: *into, Description: description}).ID()] {
+		return nil, nil
+	}
+
+	states := m.TaskStates(into.Domain)
+
+	var state TaskState
+	var err error
+
+	switch tw.Status {
+	case TaskwarriorPending, TaskwarriorWaiting:
+		state, err = todoState(states)
+	case TaskwarriorCompleted:
+		state, err = doneState(states)
+	default:
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	added, err := m.AddTask(*into, description, state.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
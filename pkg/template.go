@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/gobwas/glob"
 	"github.com/otiai10/copy"
 )
 
@@ -12,6 +16,195 @@ const (
 	TemplateDirName = ".templates"
 )
 
+// TemplateRule selects the template to use when a meeting is created without one. Each pattern is a wildcard, and an
+// empty pattern matches every meeting.
+type TemplateRule struct {
+	Domain   string `yaml:"domain,omitempty"`
+	Name     string `yaml:"name,omitempty"`
+	Weekday  string `yaml:"weekday,omitempty"`
+	Template string `yaml:"template"`
+
+	// globs holds the compiled domain, name, and weekday patterns, with nil for empty patterns. It is nil until the
+	// rule is compiled.
+	globs []glob.Glob
+}
+
+func (r TemplateRule) patterns() []string {
+	return []string{r.Domain, r.Name, r.Weekday}
+}
+
+// compile compiles the rule's patterns so that they are not compiled again for every meeting the rule is matched
+// against.
+func (r *TemplateRule) compile() error {
+	globs := make([]glob.Glob, 0, 3)
+
+	for _, pattern := range r.patterns() {
+		if pattern == "" {
+			globs = append(globs, nil)
+			continue
+		}
+
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+		}
+
+		globs = append(globs, g)
+	}
+
+	r.globs = globs
+
+	return nil
+}
+
+// Validate checks that the rule references a template and that all of its patterns compile.
+func (r TemplateRule) Validate() error {
+	if r.Template == "" {
+		return fmt.Errorf("rule has no template")
+	}
+
+	return r.compile()
+}
+
+// Match reports whether the rule applies to the given meeting. The weekday pattern is matched against both the full
+// and abbreviated lowercase weekday names (ie "monday" and "mon").
+func (r TemplateRule) Match(meeting Meeting) (bool, error) {
+	if r.globs == nil {
+		if err := r.compile(); err != nil {
+			return false, err
+		}
+	}
+
+	domain, name, weekdayGlob := r.globs[0], r.globs[1], r.globs[2]
+
+	if !matchGlob(domain, meeting.Domain) || !matchGlob(name, meeting.Name) {
+		return false, nil
+	}
+
+	if weekdayGlob == nil {
+		return true, nil
+	}
+
+	date, err := time.Parse(DateFormat, meeting.Date)
+	if err != nil {
+		return false, fmt.Errorf("could not determine weekday: %w", err)
+	}
+
+	weekday := strings.ToLower(date.Weekday().String())
+
+	return weekdayGlob.Match(weekday) || weekdayGlob.Match(weekday[:3]), nil
+}
+
+// Specificity ranks how narrowly the rule matches meetings. Rules which constrain more fields are more specific, with
+// ties broken by the number of literal (non-wildcard) characters in their patterns.
+func (r TemplateRule) Specificity() (int, int) {
+	fields, literals := 0, 0
+
+	for _, pattern := range r.patterns() {
+		if pattern == "" {
+			continue
+		}
+
+		fields++
+		literals += len(pattern) - strings.Count(pattern, "*") - strings.Count(pattern, "?")
+	}
+
+	return fields, literals
+}
+
+func (r TemplateRule) String() string {
+	var constraints []string
+
+	if r.Domain != "" {
+		constraints = append(constraints, fmt.Sprintf("domain=%s", r.Domain))
+	}
+
+	if r.Name != "" {
+		constraints = append(constraints, fmt.Sprintf("name=%s", r.Name))
+	}
+
+	if r.Weekday != "" {
+		constraints = append(constraints, fmt.Sprintf("weekday=%s", r.Weekday))
+	}
+
+	if len(constraints) == 0 {
+		constraints = append(constraints, "*")
+	}
+
+	return fmt.Sprintf("%s -> %s", strings.Join(constraints, " "), r.Template)
+}
+
+// compileTemplateRules compiles the patterns of every template rule, see TemplateRule.compile.
+func (m *Metadata) compileTemplateRules() error {
+	for i := range m.TemplateRules {
+		if err := m.TemplateRules[i].compile(); err != nil {
+			return fmt.Errorf("invalid template rule %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// MatchingTemplateRules returns the indices of every template rule which applies to the meeting, ordered from most to
+// least specific. Rules with the same specificity retain the order in which they were configured.
+func (m *Manager) MatchingTemplateRules(meeting Meeting) ([]int, error) {
+	var matches []int
+
+	for i, rule := range m.metadata.TemplateRules {
+		found, err := rule.Match(meeting)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			matches = append(matches, i)
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b int) int {
+		aFields, aLiterals := m.metadata.TemplateRules[a].Specificity()
+		bFields, bLiterals := m.metadata.TemplateRules[b].Specificity()
+
+		if aFields != bFields {
+			return bFields - aFields
+		}
+
+		return bLiterals - aLiterals
+	})
+
+	return matches, nil
+}
+
+// TemplateRules returns the configured template rules.
+func (m *Manager) TemplateRules() []TemplateRule {
+	return m.metadata.TemplateRules
+}
+
+// ResolveTemplate returns the name of the template which should be used for the meeting, or an empty string if no
+// template applies.
+func (m *Manager) ResolveTemplate(meeting Meeting) (string, error) {
+	if meeting.Template != "" {
+		return meeting.Template, nil
+	}
+
+	matches, err := m.MatchingTemplateRules(meeting)
+	if err != nil {
+		return "", err
+	}
+
+	if len(matches) == 0 {
+		return "", nil
+	}
+
+	return m.metadata.TemplateRules[matches[0]].Template, nil
+}
+
+// TemplateExists reports whether a template with the given name has been added.
+func (m *Manager) TemplateExists(name string) bool {
+	info, err := os.Stat(path.Join(m.RootDir, TemplateDirName, name))
+	return err == nil && !info.IsDir()
+}
+
 func (m *Manager) AddTemplate(paths ...string) error {
 	dir := path.Join(m.RootDir, TemplateDirName)
 
@@ -0,0 +1,363 @@
+package meetup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// TodoTxtIDKey is the todo.txt tag holding the ID of the meeting task a line was exported from.
+const TodoTxtIDKey = "meetup"
+
+var (
+	todoTxtDateRegex     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	todoTxtPriorityRegex = regexp.MustCompile(`^\(([A-Z])\)$`)
+	todoTxtTagRegex      = regexp.MustCompile(`^([\p{L}][\p{L}\p{N}_\-]*):([^\s/][^\s]*)$`)
+)
+
+// TodoTxtTag is a key:value tag on a todo.txt line.
+type TodoTxtTag struct {
+	Key   string
+	Value string
+}
+
+// TodoTxtItem is a task in the todo.txt format (see https://github.com/todotxt/todo.txt).
+type TodoTxtItem struct {
+	Done     bool
+	Priority string
+
+	// Completed and Created are dates in DateFormat.
+	Completed string
+	Created   string
+
+	// Description is the text of the task without its projects, contexts, or tags.
+	Description string
+	Projects    []string
+	Contexts    []string
+	Tags        []TodoTxtTag
+}
+
+// Tag returns the value of the tag with the given key.
+func (i TodoTxtItem) Tag(key string) (string, bool) {
+	for _, tag := range i.Tags {
+		if tag.Key == key {
+			return tag.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func (i TodoTxtItem) String() string {
+	var parts []string
+
+	if i.Done {
+		parts = append(parts, "x")
+	}
+
+	if i.Priority != "" {
+		parts = append(parts, "("+i.Priority+")")
+	}
+
+	// the completion date is only allowed alongside a creation date
+	if i.Done && i.Completed != "" && i.Created != "" {
+		parts = append(parts, i.Completed)
+	}
+
+	if i.Created != "" {
+		parts = append(parts, i.Created)
+	}
+
+	if i.Description != "" {
+		parts = append(parts, i.Description)
+	}
+
+	for _, project := range i.Projects {
+		parts = append(parts, "+"+project)
+	}
+
+	for _, context := range i.Contexts {
+		parts = append(parts, "@"+context)
+	}
+
+	for _, tag := range i.Tags {
+		parts = append(parts, tag.Key+":"+tag.Value)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// ParseTodoTxt parses a line of a todo.txt file. Projects, contexts, and tags are removed from the description
+// wherever they appear.
+func ParseTodoTxt(line string) TodoTxtItem {
+	var item TodoTxtItem
+
+	fields := strings.Fields(line)
+
+	if len(fields) > 0 && fields[0] == "x" {
+		item.Done = true
+		fields = fields[1:]
+	}
+
+	if len(fields) > 0 {
+		if match := todoTxtPriorityRegex.FindStringSubmatch(fields[0]); match != nil {
+			item.Priority = match[1]
+			fields = fields[1:]
+		}
+	}
+
+	var dates []string
+	for len(fields) > 0 && len(dates) < 2 && todoTxtDateRegex.MatchString(fields[0]) {
+		dates = append(dates, fields[0])
+		fields = fields[1:]
+	}
+
+	switch {
+	case len(dates) == 2 && item.Done:
+		item.Completed, item.Created = dates[0], dates[1]
+	case len(dates) == 1 && item.Done:
+		item.Completed = dates[0]
+	case len(dates) > 0:
+		item.Created = dates[0]
+
+		// a second date on an incomplete task is part of the description
+		fields = append(dates[1:], fields...)
+	}
+
+	var description []string
+
+	for _, field := range fields {
+		switch {
+		case len(field) > 1 && field[0] == '+':
+			item.Projects = append(item.Projects, field[1:])
+		case len(field) > 1 && field[0] == '@':
+			item.Contexts = append(item.Contexts, field[1:])
+		default:
+			if match := todoTxtTagRegex.FindStringSubmatch(field); match != nil {
+				item.Tags = append(item.Tags, TodoTxtTag{Key: match[1], Value: match[2]})
+			} else {
+				description = append(description, field)
+			}
+		}
+	}
+
+	item.Description = strings.Join(description, " ")
+
+	return item
+}
+
+// NewTodoTxtItem converts a task to todo.txt. The task's domain becomes its project, its hashtags become contexts, its
+// due date becomes a due tag, and the task's ID is kept in a TodoTxtIDKey tag. Tasks are created when they were first
+// seen if their age is tracked, or else on the date of their meeting, and done tasks are completed on the date of their
+// meeting.
+func NewTodoTxtItem(task Task) TodoTxtItem {
+	item := TodoTxtItem{
+		Done:        task.State.Done,
+		Created:     task.FirstSeen,
+		Description: strings.Join(strings.Fields(dueRegex.ReplaceAllString(hashtagRegex.ReplaceAllString(task.Description, " "), " ")), " "),
+		Contexts:    task.Tags(),
+	}
+
+	if task.Due != "" {
+		item.Tags = append(item.Tags, TodoTxtTag{Key: "due", Value: task.Due})
+	}
+
+	item.Tags = append(item.Tags, TodoTxtTag{Key: TodoTxtIDKey, Value: task.ID()})
+
+	if item.Created == "" {
+		item.Created = task.Meeting.Date
+	}
+
+	if item.Done {
+		item.Completed = task.Meeting.Date
+	}
+
+	if task.Meeting.Domain != "" {
+		item.Projects = []string{task.Meeting.Domain}
+	}
+
+	return item
+}
+
+// WriteTodoTxt writes the tasks to w as todo.txt lines.
+func WriteTodoTxt(w io.Writer, tasks []Task) error {
+	for _, task := range tasks {
+		if _, err := fmt.Fprintln(w, NewTodoTxtItem(task)); err != nil {
+			return fmt.Errorf("could not write todo.txt: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// TodoTxtSync is the outcome of SyncTodoTxt.
+type TodoTxtSync struct {
+	// Completed are the meeting tasks completed from the file.
+	Completed []Task
+
+	// Unmatched are the exported lines whose task could not be found in any meeting, ie because its description was
+	// edited since it was exported. They are kept in the file as they are.
+	Unmatched []string
+}
+
+// matchExported returns the exported line for each task by the task's current ID, and the IDs of the lines which were
+// matched. Lines are matched by the task's current ID, or else by the ID it had before its meeting was moved.
+func matchExported(tasks []Task, items map[string]TodoTxtItem, formers map[string][]Meeting) (map[string]TodoTxtItem, map[string]bool) {
+	previous := map[string]TodoTxtItem{}
+	found := map[string]bool{}
+
+	for _, task := range tasks {
+		if item, ok := items[task.ID()]; ok {
+			previous[task.ID()] = item
+			found[task.ID()] = true
+		}
+	}
+
+	for _, task := range tasks {
+		if _, ok := previous[task.ID()]; ok {
+			continue
+		}
+
+		for _, former := range task.formerTasks(formers) {
+			if item, ok := items[former.ID()]; ok && !found[former.ID()] {
+				previous[task.ID()] = item
+				found[former.ID()] = true
+				break
+			}
+		}
+	}
+
+	return previous, found
+}
+
+// SyncTodoTxt synchronizes the todo.txt file at p with the tasks matching the query. First, each task exported to the
+// file which was since marked done there is completed in its meeting. Then the lines exported from meetings are
+// replaced with the current matching tasks, keeping the priority and contexts added to them in the file, and leaving
+// the file's other lines untouched. Lines exported before their meeting was moved are matched to the moved task.
+func (m *Manager) SyncTodoTxt(p string, query TaskQuery) (TodoTxtSync, error) {
+	var sync TodoTxtSync
+
+	var kept []string
+	var exported []string
+
+	items := map[string]TodoTxtItem{}
+
+	file, err := os.Open(p)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return sync, fmt.Errorf("could not open todo.txt: %w", err)
+	default:
+		scanner := newLineScanner(file)
+
+		for scanner.Scan() {
+			line := scanner.Text()
+			item := ParseTodoTxt(line)
+
+			id, ok := item.Tag(TodoTxtIDKey)
+			if !ok {
+				kept = append(kept, line)
+				continue
+			}
+
+			exported = append(exported, line)
+			items[id] = item
+		}
+
+		err := scanner.Err()
+		file.Close()
+
+		if err != nil {
+			return sync, fmt.Errorf("could not read todo.txt: %w", err)
+		}
+	}
+
+	var previous map[string]TodoTxtItem
+
+	if len(exported) > 0 {
+		tasks, err := m.Tasks(TaskQuery{})
+		if err != nil {
+			return sync, err
+		}
+
+		formers, err := m.formerMeetings()
+		if err != nil {
+			return sync, err
+		}
+
+		var found map[string]bool
+		previous, found = matchExported(tasks, items, formers)
+
+		var errs []error
+
+		for _, task := range tasks {
+			item, ok := previous[task.ID()]
+			if !ok || task.State.Done || !item.Done {
+				continue
+			}
+
+			state, err := doneState(m.TaskStates(task.Meeting.Domain))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			updated, err := m.SetTaskState(task, state.Name)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			sync.Completed = append(sync.Completed, updated)
+		}
+
+		if err := errors.Join(errs...); err != nil {
+			return sync, err
+		}
+
+		for _, line := range exported {
+			if id, _ := ParseTodoTxt(line).Tag(TodoTxtIDKey); !found[id] {
+				sync.Unmatched = append(sync.Unmatched, line)
+			}
+		}
+
+		kept = append(kept, sync.Unmatched...)
+	}
+
+	tasks, err := m.Tasks(query)
+	if err != nil {
+		return sync, err
+	}
+
+	var builder strings.Builder
+
+	for _, line := range kept {
+		builder.WriteString(line + "\n")
+	}
+
+	for _, task := range tasks {
+		item := NewTodoTxtItem(task)
+
+		if previous, ok := previous[task.ID()]; ok {
+			item.Priority = previous.Priority
+
+			for _, context := range previous.Contexts {
+				if !slices.Contains(item.Contexts, context) {
+					item.Contexts = append(item.Contexts, context)
+				}
+			}
+		}
+
+		builder.WriteString(item.String() + "\n")
+	}
+
+	if err := os.WriteFile(p, []byte(builder.String()), 0644); err != nil {
+		return sync, fmt.Errorf("could not write todo.txt: %w", err)
+	}
+
+	return sync, nil
+}
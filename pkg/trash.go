@@ -0,0 +1,311 @@
+package meetup
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	TrashDirName          = ".trash"
+	TrashManifestFilename = "manifest.yaml"
+)
+
+// TrashItem is a removed meeting which can still be restored.
+type TrashItem struct {
+	ID      string  `yaml:"id"`
+	Meeting Meeting `yaml:"meeting"`
+
+	// Path is the original path of the meeting relative to the meetup directory.
+	Path      string    `yaml:"path"`
+	RemovedAt time.Time `yaml:"removed_at"`
+
+	// Assets reports whether the meeting's assets directory was trashed along with it.
+	Assets bool `yaml:"assets,omitempty"`
+}
+
+func (m *Manager) trashDir() string {
+	return path.Join(m.RootDir, TrashDirName)
+}
+
+func (m *Manager) loadTrashManifest() ([]TrashItem, error) {
+	data, err := os.ReadFile(path.Join(m.trashDir(), TrashManifestFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not read trash manifest: %w", err)
+	}
+
+	var items []TrashItem
+	if err := yaml.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("could not parse trash manifest: %w", err)
+	}
+
+	return items, nil
+}
+
+func (m *Manager) saveTrashManifest(items []TrashItem) error {
+	data, err := yaml.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("could not marshal trash manifest: %w", err)
+	}
+
+	if err := os.MkdirAll(m.trashDir(), 0755); err != nil {
+		return fmt.Errorf("could not create trash: %w", err)
+	}
+
+	// write the manifest in one step so it is never left partially written
+	manifest := path.Join(m.trashDir(), TrashManifestFilename)
+
+	if err := os.WriteFile(manifest+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("could not write trash manifest: %w", err)
+	}
+
+	if err := os.Rename(manifest+".tmp", manifest); err != nil {
+		return fmt.Errorf("could not write trash manifest: %w", err)
+	}
+
+	return nil
+}
+
+// newTrashID returns an unused ID for an item removed at now. IDs start with the time the item was removed, followed
+// by a random suffix so that items removed at the same time (ie on platforms with coarse clocks) get different IDs.
+func (m *Manager) newTrashID(items []TrashItem, now time.Time) (string, error) {
+	suffix := make([]byte, 4)
+
+	for {
+		if _, err := rand.Read(suffix); err != nil {
+			return "", fmt.Errorf("could not generate trash id: %w", err)
+		}
+
+		id := strconv.FormatInt(now.UnixNano(), 36) + "-" + hex.EncodeToString(suffix)
+
+		if slices.ContainsFunc(items, func(item TrashItem) bool { return item.ID == id }) {
+			continue
+		}
+
+		if _, err := os.Stat(path.Join(m.trashDir(), id)); err == nil {
+			continue
+		}
+
+		return id, nil
+	}
+}
+
+// trash moves the file at p into the trash, along with the meeting's assets if withAssets is set, and prunes any
+// directories left empty.
+func (m *Manager) trash(meeting Meeting, p string, withAssets bool) (TrashItem, error) {
+	items, err := m.loadTrashManifest()
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	item, trashErr := m.trashFile(items, meeting, p, withAssets)
+	if item.ID == "" {
+		return TrashItem{}, trashErr
+	}
+
+	if err := m.saveTrashManifest(append(items, item)); err != nil {
+		return TrashItem{}, err
+	}
+
+	return item, trashErr
+}
+
+// trashMeetings moves each meeting and its assets into the trash like trash, saving the manifest once. Meetings are
+// trashed until one fails, and the meetings trashed before it are kept in the manifest.
+func (m *Manager) trashMeetings(meetings []Meeting) error {
+	items, err := m.loadTrashManifest()
+	if err != nil {
+		return err
+	}
+
+	var trashErr error
+	trashed := 0
+
+	for _, meeting := range meetings {
+		p := m.pathForMeeting(meeting)
+
+		if _, err := os.Stat(p); err != nil {
+			trashErr = fmt.Errorf("could not delete meeting '%s': %w", meeting, err)
+			break
+		}
+
+		item, err := m.trashFile(items, meeting, p, true)
+		if item.ID != "" {
+			items = append(items, item)
+			trashed++
+		}
+
+		if err != nil {
+			trashErr = fmt.Errorf("could not delete meeting '%s': %w", meeting, err)
+			break
+		}
+	}
+
+	if trashed > 0 {
+		if err := m.saveTrashManifest(items); err != nil {
+			return errors.Join(trashErr, err)
+		}
+	}
+
+	return trashErr
+}
+
+// trashFile moves the file at p into the trash without updating the manifest, and returns the item to add to it. The
+// returned item has no ID if the file was not moved.
+func (m *Manager) trashFile(items []TrashItem, meeting Meeting, p string, withAssets bool) (TrashItem, error) {
+	now := time.Now()
+
+	id, err := m.newTrashID(items, now)
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	item := TrashItem{
+		ID:        id,
+		Meeting:   meeting,
+		Path:      strings.TrimPrefix(p, m.RootDir+"/"),
+		RemovedAt: now,
+	}
+
+	if err := os.MkdirAll(m.trashDir(), 0755); err != nil {
+		return TrashItem{}, fmt.Errorf("could not create trash: %w", err)
+	}
+
+	if err := os.Rename(p, path.Join(m.trashDir(), item.ID)); err != nil {
+		return TrashItem{}, fmt.Errorf("could not move meeting to trash: %w", err)
+	}
+
+	assets := m.assetsPath(meeting)
+
+	if _, err := os.Stat(assets); err == nil && withAssets {
+		if err := os.Rename(assets, path.Join(m.trashDir(), item.ID+AssetsDirSuffix)); err != nil {
+			return item, fmt.Errorf("could not move meeting assets to trash: %w", err)
+		}
+
+		item.Assets = true
+	}
+
+	_, err = m.pruneDirs(path.Dir(p))
+
+	return item, err
+}
+
+// ListTrash returns all meetings in the trash, from oldest to most recently removed.
+func (m *Manager) ListTrash() ([]TrashItem, error) {
+	items, err := m.loadTrashManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(items, func(a, b TrashItem) int {
+		return a.RemovedAt.Compare(b.RemovedAt)
+	})
+
+	return items, nil
+}
+
+// RestoreTrash moves the trashed meeting with the given id back into the meetup directory, using the current group by
+// strategy and recreating any directories which were removed when it was trashed. Restoring will fail rather than
+// overwrite an existing meeting.
+func (m *Manager) RestoreTrash(id string) (Meeting, error) {
+	items, err := m.loadTrashManifest()
+	if err != nil {
+		return Meeting{}, err
+	}
+
+	i := slices.IndexFunc(items, func(item TrashItem) bool {
+		return item.ID == id
+	})
+	if i == -1 {
+		return Meeting{}, fmt.Errorf("no such item in trash '%s'", id)
+	}
+
+	item := items[i]
+	dst := item.Meeting.GetPath(m.RootDir, m.metadata.GroupBy) + path.Ext(item.Path)
+
+	if _, err := os.Stat(dst); err == nil {
+		return Meeting{}, fmt.Errorf("could not restore '%s': meeting already exists", item.Meeting)
+	}
+
+	if err := os.MkdirAll(path.Dir(dst), 0755); err != nil {
+		return Meeting{}, fmt.Errorf("could not create meeting directory: %w", err)
+	}
+
+	if err := os.Rename(path.Join(m.trashDir(), item.ID), dst); err != nil {
+		return Meeting{}, fmt.Errorf("could not restore meeting: %w", err)
+	}
+
+	if item.Assets {
+		if err := os.Rename(path.Join(m.trashDir(), item.ID+AssetsDirSuffix), m.assetsPath(item.Meeting)); err != nil {
+			return Meeting{}, fmt.Errorf("could not restore meeting assets: %w", err)
+		}
+	}
+
+	if err := m.saveTrashManifest(slices.Delete(items, i, i+1)); err != nil {
+		return Meeting{}, err
+	}
+
+	return item.Meeting, nil
+}
+
+// EmptyTrash permanently deletes every trashed meeting removed more than olderThan ago, or every trashed meeting if
+// olderThan is 0. The deleted items are returned.
+func (m *Manager) EmptyTrash(olderThan Duration) ([]TrashItem, error) {
+	items, err := m.loadTrashManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-time.Duration(olderThan))
+
+	var kept, deleted []TrashItem
+
+	for _, item := range items {
+		if olderThan != 0 && item.RemovedAt.After(cutoff) {
+			kept = append(kept, item)
+			continue
+		}
+
+		for _, name := range []string{item.ID, item.ID + AssetsDirSuffix} {
+			if err := os.RemoveAll(path.Join(m.trashDir(), name)); err != nil {
+				return nil, fmt.Errorf("could not delete '%s': %w", item.Meeting, err)
+			}
+		}
+
+		deleted = append(deleted, item)
+	}
+
+	if len(deleted) == 0 {
+		return nil, nil
+	}
+
+	if err := m.saveTrashManifest(kept); err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// purgeTrash deletes trashed meetings which are older than the configured trash retention.
+func (m *Manager) purgeTrash() error {
+	if m.metadata.TrashRetention == nil || *m.metadata.TrashRetention == 0 {
+		return nil
+	}
+
+	_, err := m.EmptyTrash(*m.metadata.TrashRetention)
+
+	return err
+}
@@ -1,9 +1,15 @@
 package meetup
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // MeetingFromPath attemps to construct a Meeting from its path in the meetup dir.
@@ -15,9 +21,8 @@ func MeetingFromPath(gs GroupStrategy, p string) (Meeting, error) {
 		return Meeting{}, fmt.Errorf("path does not have enough components '%s'", p)
 	}
 
-	meeting := Meeting{
-		Name: components[len(components)-1],
-	}
+	meeting := Meeting{}
+	meeting.Name, meeting.Time, meeting.Sequence = SplitMeetingName(components[len(components)-1])
 
 	switch gs {
 	case GroupByDomain:
@@ -30,3 +35,122 @@ func MeetingFromPath(gs GroupStrategy, p string) (Meeting, error) {
 
 	return meeting, nil
 }
+
+// Duration is a time.Duration which additionally supports day ("d") and week ("w") units (ie "2w3d" or "1d12h").
+type Duration time.Duration
+
+var (
+	durationUnits = map[string]time.Duration{
+		"ns": time.Nanosecond,
+		"us": time.Microsecond,
+		"µs": time.Microsecond,
+		"ms": time.Millisecond,
+		"s":  time.Second,
+		"m":  time.Minute,
+		"h":  time.Hour,
+		"d":  time.Hour * 24,
+		"w":  time.Hour * 24 * 7,
+	}
+
+	durationComponentRegex = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)([a-zµ]+)`)
+)
+
+// ParseDuration parses a duration string as accepted by time.ParseDuration, with additional support for day and week
+// units.
+func ParseDuration(s string) (Duration, error) {
+	raw := s
+	sign := 1.0
+
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	if s == "0" {
+		return 0, nil
+	}
+
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration '%s'", raw)
+	}
+
+	var total float64
+
+	for s != "" {
+		match := durationComponentRegex.FindStringSubmatch(s)
+		if match == nil {
+			return 0, fmt.Errorf("invalid duration '%s'", raw)
+		}
+
+		unit, found := durationUnits[match[2]]
+		if !found {
+			return 0, fmt.Errorf("invalid duration '%s': unknown unit '%s'", raw, match[2])
+		}
+
+		n, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s': %w", raw, err)
+		}
+
+		total += n * float64(unit)
+		s = s[len(match[0]):]
+	}
+
+	return Duration(sign * total), nil
+}
+
+func (d Duration) String() string {
+	if d == 0 {
+		return "0"
+	}
+
+	day := Duration(time.Hour * 24)
+
+	if d%day == 0 {
+		if d%(day*7) == 0 {
+			return fmt.Sprintf("%dw", d/(day*7))
+		}
+
+		return fmt.Sprintf("%dd", d/day)
+	}
+
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	parsed, err := ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+func (d Duration) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := ParseDuration(node.Value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package meetup
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ViewKind is whether a view lists meetings or tasks.
+type ViewKind string
+
+const (
+	ViewMeetings ViewKind = "meetings"
+	ViewTasks    ViewKind = "tasks"
+)
+
+// OutputFormat is how listings are printed.
+type OutputFormat string
+
+const (
+	FormatText OutputFormat = "text"
+	FormatJSON OutputFormat = "json"
+)
+
+func (f OutputFormat) Validate() error {
+	switch f {
+	case "", FormatText, FormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format: %s", f)
+	}
+}
+
+// View is a saved meeting or task query along with how to sort and print the results. Queries and ranges are
+// evaluated each time the view is run, so relative dates stay relative.
+type View struct {
+	Kind ViewKind `yaml:"kind"`
+
+	// Query is a meeting or task query depending on Kind, see ParseMeetingQuery and ParseTaskQuery.
+	Query string `yaml:"query,omitempty"`
+
+	// Range is one of DateRanges.
+	Range string `yaml:"range,omitempty"`
+
+	// Sort is a comma separated list of sort keys, see ParseSortKeys.
+	Sort            string       `yaml:"sort,omitempty"`
+	Limit           int          `yaml:"limit,omitempty"`
+	Latest          bool         `yaml:"latest,omitempty"`
+	IncludeArchived bool         `yaml:"include_archived,omitempty"`
+	Format          OutputFormat `yaml:"format,omitempty"`
+}
+
+// ParseQuery parses the view's query for its kind, or returns nil if it has no query.
+func (v View) ParseQuery() (*Query, error) {
+	if v.Query == "" {
+		return nil, nil
+	}
+
+	if v.Kind == ViewTasks {
+		return ParseTaskQuery(v.Query)
+	}
+
+	return ParseMeetingQuery(v.Query)
+}
+
+// ListOptions returns the sort and limits of the view.
+func (v View) ListOptions() (ListOptions, error) {
+	keys, err := ParseSortKeys(v.Sort)
+	if err != nil {
+		return ListOptions{}, err
+	}
+
+	return ListOptions{Sort: keys, Limit: v.Limit, Latest: v.Latest}, nil
+}
+
+// NarrowMeetings restricts the query to the meetings matched by the view at the given time. The view's range is
+// intersected with the query's, and its query is combined with the query's query.
+func (v View) NarrowMeetings(query MeetingQuery, now time.Time) (MeetingQuery, error) {
+	if v.Range != "" {
+		since, until, err := ParseDateRange(v.Range, now)
+		if err != nil {
+			return MeetingQuery{}, err
+		}
+
+		if since.After(query.Since) {
+			query.Since = since
+		}
+
+		if query.Until.IsZero() || until.Before(query.Until) {
+			query.Until = until
+		}
+	}
+
+	if v.Kind != ViewTasks {
+		viewQuery, err := v.ParseQuery()
+		if err != nil {
+			return MeetingQuery{}, err
+		}
+
+		query.Query = viewQuery.And(query.Query)
+	}
+
+	query.IncludeArchived = query.IncludeArchived || v.IncludeArchived
+
+	return query, nil
+}
+
+// NarrowTasks restricts the query to the tasks matched by the view at the given time, see NarrowMeetings.
+func (v View) NarrowTasks(query TaskQuery, now time.Time) (TaskQuery, error) {
+	var err error
+
+	if query.Meeting, err = v.NarrowMeetings(query.Meeting, now); err != nil {
+		return TaskQuery{}, err
+	}
+
+	if v.Kind == ViewTasks {
+		viewQuery, err := v.ParseQuery()
+		if err != nil {
+			return TaskQuery{}, err
+		}
+
+		query.Query = viewQuery.And(query.Query)
+	}
+
+	return query, nil
+}
+
+func (v View) Validate() error {
+	switch v.Kind {
+	case ViewMeetings, ViewTasks:
+	default:
+		return fmt.Errorf("invalid view kind '%s', expected %s or %s", v.Kind, ViewMeetings, ViewTasks)
+	}
+
+	if _, err := v.ParseQuery(); err != nil {
+		return err
+	}
+
+	if v.Range != "" && !slices.Contains(DateRanges, v.Range) {
+		return fmt.Errorf("unknown date range '%s', expected one of %s", v.Range, strings.Join(DateRanges, ", "))
+	}
+
+	opts, err := v.ListOptions()
+	if err != nil {
+		return err
+	}
+
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	for _, key := range opts.Sort {
+		if (v.Kind == ViewMeetings && key.Field == SortDescription) || (v.Kind == ViewTasks && key.Field == SortTasks) {
+			return fmt.Errorf("%s cannot be sorted by %s", v.Kind, key.Field)
+		}
+	}
+
+	return v.Format.Validate()
+}
+
+// View returns the view with the given name.
+func (m *Manager) View(name string) (View, error) {
+	view, found := m.metadata.Views[name]
+	if !found {
+		return View{}, fmt.Errorf("view '%s' does not exist", name)
+	}
+
+	return view, nil
+}
+
+// Views returns the names of all views in sorted order.
+func (m *Manager) Views() []string {
+	names := make([]string, 0, len(m.metadata.Views))
+	for name := range m.metadata.Views {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// SaveView adds or replaces the view with the given name in the metadata file.
+func (m *Manager) SaveView(name string, view View) error {
+	if name == "" {
+		return fmt.Errorf("view name cannot be empty")
+	}
+
+	if err := view.Validate(); err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := node.Encode(view); err != nil {
+		return fmt.Errorf("could not encode view: %w", err)
+	}
+
+	return m.editMetadata(func(doc *yamlDocument) error {
+		return doc.set([]string{"views", name}, &node)
+	})
+}
+
+// RemoveView removes the view with the given name from the metadata file.
+func (m *Manager) RemoveView(name string) error {
+	if _, err := m.View(name); err != nil {
+		return err
+	}
+
+	return m.editMetadata(func(doc *yamlDocument) error {
+		doc.unset([]string{"views", name})
+		return nil
+	})
+}
@@ -0,0 +1,336 @@
+package meetup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// wikiLinkRegex matches wiki links (ie "[[2024-03-01 work.team standup|the standup]]"), capturing the target and the
+// optional label.
+var wikiLinkRegex = regexp.MustCompile(`\[\[([^\[\]|\n]+)(?:\|([^\[\]\n]*))?\]\]`)
+
+// WikiLast is the suffix of a wiki link target referring to the latest meeting in a series before the linking meeting.
+const WikiLast = "last"
+
+// wikiTarget is a parsed wiki link target.
+type wikiTarget struct {
+	// meeting is the linked meeting, whose date is empty when linking to the last meeting.
+	meeting Meeting
+
+	// dotted is set for targets written as <domain>.<name>@<date|last> rather than <date> <domain> <name>.
+	dotted bool
+}
+
+// parseWikiTarget parses a wiki link target, which is either a meeting as printed by Meeting.String (ie
+// "2024-03-01 work.team standup@09:30") or a dotted meeting name followed by a date or "last" (ie
+// "work.team.standup@last" or "work.team.standup@2024-03-01"). A dotted name without a date links to the last meeting.
+func parseWikiTarget(raw string) (wikiTarget, error) {
+	fields := strings.Fields(raw)
+
+	if len(fields) == 3 && isValidDate(fields[0]) {
+		meeting := Meeting{Date: fields[0], Domain: fields[1]}
+		meeting.Name, meeting.Time, meeting.Sequence = SplitMeetingName(fields[2])
+
+		return wikiTarget{meeting: meeting}, nil
+	}
+
+	if len(fields) != 1 {
+		return wikiTarget{}, fmt.Errorf("invalid link '%s': expected '<date> <domain> <name>' or '<domain>.<name>@<date|last>'", raw)
+	}
+
+	name, date := fields[0], ""
+
+	if i := strings.LastIndex(name, "@"); i >= 0 && (name[i+1:] == WikiLast || isValidDate(name[i+1:])) {
+		name, date = name[:i], name[i+1:]
+	}
+
+	if date == WikiLast {
+		date = ""
+	}
+
+	lastSep := strings.LastIndex(name, ".")
+	if lastSep <= 0 || lastSep == len(name)-1 {
+		return wikiTarget{}, fmt.Errorf("invalid link '%s': expected '<date> <domain> <name>' or '<domain>.<name>@<date|last>'", raw)
+	}
+
+	meeting := Meeting{Date: date, Domain: name[:lastSep]}
+	meeting.Name, meeting.Time, meeting.Sequence = SplitMeetingName(name[lastSep+1:])
+
+	return wikiTarget{meeting: meeting, dotted: true}, nil
+}
+
+// format writes the target in the same form it was parsed from.
+func (t wikiTarget) format() string {
+	if !t.dotted {
+		return t.meeting.String()
+	}
+
+	date := t.meeting.Date
+	if date == "" {
+		date = WikiLast
+	}
+
+	return t.meeting.Domain + "." + t.meeting.FullName() + "@" + date
+}
+
+// meetingKey identifies a meeting by its path, ignoring fields like its template.
+func meetingKey(meeting Meeting) string {
+	key := meeting.GetPath("", GroupByDate)
+	if meeting.Archived {
+		key += "\x00archived"
+	}
+
+	return key
+}
+
+// resolveWikiTarget returns the meeting a target written in source refers to. Links to the last meeting resolve to the
+// latest meeting with the same domain and name (and time, if given) before source, so that they keep referring to the
+// same meeting as the series continues.
+func resolveWikiTarget(target wikiTarget, source Meeting, meetings []Meeting) (Meeting, error) {
+	want := target.meeting
+
+	if want.Date != "" {
+		for _, meeting := range meetings {
+			if meeting.Date == want.Date && meeting.Domain == want.Domain && meeting.Name == want.Name && meeting.Time == want.Time && meeting.Sequence == want.Sequence {
+				return meeting, nil
+			}
+		}
+
+		return Meeting{}, fmt.Errorf("meeting '%s' does not exist", want)
+	}
+
+	var found *Meeting
+
+	for i, meeting := range meetings {
+		if meeting.Domain != want.Domain || meeting.Name != want.Name || (want.Time != "" && meeting.Time != want.Time) {
+			continue
+		}
+
+		if meetingKey(meeting) == meetingKey(source) || meeting.Date > source.Date {
+			continue
+		}
+
+		// earlier meetings in the same series on the same day are ordered by time and sequence
+		sameSeries := meeting.Domain == source.Domain && meeting.Name == source.Name
+		if meeting.Date == source.Date && sameSeries && compareChronological(meeting, source) > 0 {
+			continue
+		}
+
+		if found == nil || compareChronological(meeting, *found) > 0 {
+			found = &meetings[i]
+		}
+	}
+
+	if found == nil {
+		return Meeting{}, fmt.Errorf("no '%s.%s' meeting before '%s'", want.Domain, want.FullName(), source)
+	}
+
+	return *found, nil
+}
+
+// WikiLink is a reference from one meeting to another, either as a wiki link or as a relative markdown link to the
+// meeting's file.
+type WikiLink struct {
+	// Source is the meeting containing the link, and Line is the line it is on, starting from 1.
+	Source Meeting `json:"source"`
+	Line   int     `json:"line"`
+
+	// Target is the link's target as written, and Label is the text after a '|' in a wiki link.
+	Target string `json:"target"`
+	Label  string `json:"label,omitempty"`
+
+	// Markdown is set for markdown links rather than wiki links.
+	Markdown bool `json:"markdown,omitempty"`
+
+	// Meeting is the meeting the link refers to, or nil if the link is broken, in which case Error describes why.
+	Meeting *Meeting `json:"meeting,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Broken returns whether the link does not refer to a meeting.
+func (l WikiLink) Broken() bool {
+	return l.Meeting == nil
+}
+
+// MeetingLinks are the links from and to a meeting.
+type MeetingLinks struct {
+	Outgoing []WikiLink `json:"outgoing"`
+	Incoming []WikiLink `json:"incoming"`
+}
+
+// rewriteWikiLinks calls fn with each wiki link target in data, replacing the target with the returned value. Labels
+// are preserved.
+func rewriteWikiLinks(data string, fn func(target string) string) string {
+	return wikiLinkRegex.ReplaceAllStringFunc(data, func(link string) string {
+		match := wikiLinkRegex.FindStringSubmatch(link)
+
+		newTarget := fn(match[1])
+		if newTarget == match[1] {
+			return link
+		}
+
+		if strings.Contains(link, "|") {
+			return "[[" + newTarget + "|" + match[2] + "]]"
+		}
+
+		return "[[" + newTarget + "]]"
+	})
+}
+
+// findLinks returns the links in a meeting's contents, ignoring lines in fenced code blocks. Relative markdown links are
+// only included when they refer to a meeting, or to a file which does not exist.
+func (m *Manager) findLinks(source Meeting, data []byte, meetings []Meeting, byPath map[string]Meeting) ([]WikiLink, error) {
+	var links []WikiLink
+
+	noteDir := filepath.Dir(m.pathForMeeting(source))
+	inFence := ""
+
+	scanner := newLineScanner(bytes.NewReader(data))
+
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+
+		if match := fenceRegex.FindStringSubmatch(text); match != nil {
+			switch inFence {
+			case "":
+				inFence = match[1]
+			case match[1]:
+				inFence = ""
+			}
+
+			continue
+		}
+
+		if inFence != "" {
+			continue
+		}
+
+		for _, match := range wikiLinkRegex.FindAllStringSubmatch(text, -1) {
+			link := WikiLink{Source: source, Line: line, Target: match[1], Label: match[2]}
+
+			target, err := parseWikiTarget(match[1])
+			if err == nil {
+				var meeting Meeting
+				if meeting, err = resolveWikiTarget(target, source, meetings); err == nil {
+					link.Meeting = &meeting
+				}
+			}
+
+			if err != nil {
+				link.Error = err.Error()
+			}
+
+			links = append(links, link)
+		}
+
+		for _, match := range markdownLinkRegex.FindAllStringSubmatch(text, -1) {
+			target, _, _ := strings.Cut(match[2], "#")
+			if !isRelativeLink(target) || strings.HasPrefix(match[1], "!") {
+				continue
+			}
+
+			resolved := filepath.Join(noteDir, filepath.FromSlash(target))
+			link := WikiLink{Source: source, Line: line, Target: match[2], Markdown: true}
+
+			if meeting, found := byPath[resolved]; found {
+				link.Meeting = &meeting
+			} else if _, err := os.Stat(resolved); err != nil {
+				link.Error = fmt.Sprintf("'%s' does not exist", target)
+			} else {
+				continue
+			}
+
+			links = append(links, link)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// allLinks returns the links in every meeting, excluding archived meetings, though links may refer to archived
+// meetings.
+func (m *Manager) allLinks(ctx context.Context) ([]WikiLink, error) {
+	meetings, err := m.ListMeetings(MeetingQuery{IncludeArchived: true})
+	if err != nil {
+		return nil, err
+	}
+
+	byPath := make(map[string]Meeting, len(meetings))
+	for _, meeting := range meetings {
+		if !meeting.Archived {
+			byPath[m.pathForMeeting(meeting)] = meeting
+		}
+	}
+
+	sources := slices.DeleteFunc(slices.Clone(meetings), func(meeting Meeting) bool {
+		return meeting.Archived
+	})
+
+	found, err := scanMeetings(ctx, m.parallelism(), sources, func(meeting Meeting) ([]WikiLink, error) {
+		data, err := os.ReadFile(m.pathForMeeting(meeting))
+		if err != nil {
+			return nil, fmt.Errorf("could not read meeting '%s': %w", meeting, err)
+		}
+
+		return m.findLinks(meeting, data, meetings, byPath)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not search meetings for links: %w", err)
+	}
+
+	var links []WikiLink
+	for _, meetingLinks := range found {
+		links = append(links, meetingLinks...)
+	}
+
+	return links, nil
+}
+
+// Links returns the links from the meeting to other meetings, including broken links, and the links from other meetings
+// to it (its backlinks).
+func (m *Manager) Links(meeting Meeting) (MeetingLinks, error) {
+	if _, err := os.Stat(m.pathForMeeting(meeting)); err != nil {
+		return MeetingLinks{}, fmt.Errorf("could not find meeting '%s': %w", meeting, err)
+	}
+
+	links, err := m.allLinks(context.Background())
+	if err != nil {
+		return MeetingLinks{}, err
+	}
+
+	result := MeetingLinks{Outgoing: []WikiLink{}, Incoming: []WikiLink{}}
+
+	for _, link := range links {
+		if meetingKey(link.Source) == meetingKey(meeting) {
+			result.Outgoing = append(result.Outgoing, link)
+		}
+
+		if link.Meeting != nil && meetingKey(*link.Meeting) == meetingKey(meeting) {
+			result.Incoming = append(result.Incoming, link)
+		}
+	}
+
+	return result, nil
+}
+
+// BrokenLinks returns every link which does not refer to an existing meeting or file.
+func (m *Manager) BrokenLinks() ([]WikiLink, error) {
+	links, err := m.allLinks(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(links, func(link WikiLink) bool {
+		return !link.Broken()
+	}), nil
+}
@@ -0,0 +1,87 @@
+package meetup_test
+
+import (
+	"os"
+	"time"
+
+	"github.com/gobwas/glob"
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Agenda", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+
+	// a wednesday
+	now := time.Date(2026, 10, 21, 9, 0, 0, 0, time.Local)
+
+	lastWeek := meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-14"}
+	yesterday := meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-20"}
+	today := meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-21"}
+	later := meetup.Meeting{Name: "sync", Domain: "work", Date: "2026-10-21", Time: "14:30"}
+	earlier := meetup.Meeting{Name: "chores", Domain: "home", Date: "2026-10-21", Time: "08:00"}
+	friday := meetup.Meeting{Name: "retro", Domain: "work", Date: "2026-10-23"}
+
+	contents := map[meetup.Meeting]string{
+		lastWeek:  "- [ ] fix the flaky test due:2026-10-16\n- [ ] write docs due:friday\n- [ ] plan offsite\n",
+		yesterday: "- [ ] fix the flaky test due:2026-10-16\n- [x] write docs due:friday\n- [ ] review the pr due:tomorrow\n",
+		today:     "",
+		later:     "",
+		earlier:   "- [ ] mow the lawn due:+5d\n",
+		friday:    "",
+	}
+
+	BeforeAll(func() {
+		meetupDir, manager = newTestManager(contents)
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("parses due dates relative to the meeting", func() {
+		tasks, err := manager.Tasks(meetup.TaskQuery{Meeting: meetup.MeetingQuery{Date: glob.MustCompile("2026-10-14")}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(HaveLen(3))
+		Expect(tasks[0].Due).To(Equal("2026-10-16"))
+		Expect(tasks[1].Due).To(Equal("2026-10-16"))
+		Expect(tasks[2].Due).To(BeEmpty())
+	})
+
+	It("builds a daily agenda", func() {
+		agenda, err := manager.Agenda(meetup.MeetingQuery{}, meetup.AgendaOptions{Date: now, DueWithin: meetup.Duration(3 * 24 * time.Hour)})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(agenda.Start).To(Equal("2026-10-21"))
+		Expect(agenda.End).To(Equal("2026-10-21"))
+		Expect(agenda.Meetings).To(Equal([]meetup.Meeting{today, earlier, later}))
+		Expect(agenda.Previous).To(Equal([]meetup.Meeting{yesterday}))
+
+		Expect(agenda.Overdue).To(HaveLen(1))
+		Expect(agenda.Overdue[0].Description).To(Equal("fix the flaky test due:2026-10-16"))
+		Expect(agenda.Overdue[0].Meeting).To(Equal(yesterday))
+
+		Expect(agenda.Due).To(HaveLen(1))
+		Expect(agenda.Due[0].Description).To(Equal("review the pr due:tomorrow"))
+	})
+
+	It("builds a weekly agenda", func() {
+		agenda, err := manager.Agenda(meetup.MeetingQuery{}, meetup.AgendaOptions{Date: now, Week: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(agenda.Start).To(Equal("2026-10-19"))
+		Expect(agenda.End).To(Equal("2026-10-25"))
+		Expect(agenda.Meetings).To(Equal([]meetup.Meeting{yesterday, today, earlier, later, friday}))
+		Expect(agenda.Previous).To(Equal([]meetup.Meeting{lastWeek}))
+
+		var due []string
+		for _, task := range agenda.Due {
+			due = append(due, task.Due)
+		}
+
+		// the lawn is due the day after the week ends
+		Expect(due).To(Equal([]string{"2026-10-21"}))
+	})
+})
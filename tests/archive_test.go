@@ -0,0 +1,214 @@
+package meetup_test
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"github.com/gobwas/glob"
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Archive", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+	var err error
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, meeting := range testMeetings {
+			Expect(manager.OpenMeeting(meeting)).To(Succeed())
+		}
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("can archive meetings", func() {
+		Expect(manager.ArchiveMeeting(testMeetings[0])).To(Succeed())
+		Expect(path.Join(meetupDir, meetup.ArchiveDirName, "triple", "2021-01-01", "sample")).To(BeAnExistingFile())
+		Expect(path.Join(meetupDir, "triple")).ToNot(BeADirectory())
+
+		meetings, err := manager.ListMeetings(meetup.MeetingQuery{
+			Name:   glob.MustCompile("*"),
+			Domain: glob.MustCompile("*"),
+			Date:   glob.MustCompile("*"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meetings).To(ConsistOf(testMeetings[1], testMeetings[2]))
+	})
+
+	It("will not archive a meeting twice", func() {
+		Expect(manager.OpenMeeting(testMeetings[0])).To(Succeed())
+		Expect(manager.ArchiveMeeting(testMeetings[0])).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("ArchiveFormat", func() {
+	for _, format := range []meetup.ArchiveFormat{meetup.ArchiveTree, meetup.ArchiveTarGz, meetup.ArchiveZip} {
+		format := format
+
+		Describe(string(format), Ordered, func() {
+			var meetupDir string
+			var manager meetup.Manager
+			var err error
+
+			old := meetup.Meeting{Name: "standup", Domain: "work.team", Date: "2021-01-01"}
+			recent := meetup.Meeting{Name: "standup", Domain: "work.team", Date: "2021-06-01"}
+
+			all := meetup.MeetingQuery{
+				Name:   glob.MustCompile("*"),
+				Domain: glob.MustCompile("*"),
+				Date:   glob.MustCompile("*"),
+			}
+
+			BeforeAll(func() {
+				meetupDir, err = os.MkdirTemp("", "meetup-test")
+				Expect(err).ToNot(HaveOccurred())
+
+				manager, err = meetup.NewManager(meetup.Config{
+					RootDir: meetupDir,
+					Editor:  []string{"touch"},
+					DefaultMetadata: meetup.Metadata{
+						GroupBy:       meetup.GroupByDomain,
+						ArchiveFormat: format,
+						Domains: map[string]meetup.DomainConfig{
+							"work": {
+								Extension: ".md",
+								Retention: meetup.Duration(time.Hour * 24 * 90),
+							},
+						},
+					},
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(manager.OpenMeeting(old)).To(Succeed())
+				Expect(manager.OpenMeeting(recent)).To(Succeed())
+
+				notePath := path.Join(meetupDir, "work", "team", "2021-01-01", "standup.md")
+				Expect(os.WriteFile(notePath, []byte("- [ ] archived task\n"), 0644)).To(Succeed())
+
+				assets := path.Join(meetupDir, "work", "team", "2021-01-01", "standup"+meetup.AssetsDirSuffix)
+				Expect(os.MkdirAll(assets, 0755)).To(Succeed())
+				Expect(os.WriteFile(path.Join(assets, "board.png"), []byte("board"), 0644)).To(Succeed())
+			})
+
+			AfterAll(func() {
+				os.RemoveAll(meetupDir)
+			})
+
+			It("archives expired meetings", func() {
+				archived, err := manager.ArchiveExpired(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(archived).To(ConsistOf(old))
+
+				Expect(path.Join(meetupDir, "work", "team", "2021-01-01")).ToNot(BeADirectory())
+			})
+
+			It("excludes archived meetings by default", func() {
+				meetings, err := manager.ListMeetings(all)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(meetings).To(ConsistOf(recent))
+			})
+
+			It("includes archived meetings when asked", func() {
+				archived := old
+				archived.Archived = true
+
+				query := all
+				query.IncludeArchived = true
+
+				meetings, err := manager.ListMeetings(query)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(meetings).To(ConsistOf(recent, archived))
+
+				tasks, err := manager.Tasks(meetup.TaskQuery{
+					Meeting:     query,
+					Description: glob.MustCompile("*"),
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(tasks).To(ConsistOf(meetup.Task{Meeting: archived, State: meetup.TaskTodo, Description: "archived task", Line: 1}))
+			})
+
+			It("restores archived meetings with their assets", func() {
+				archived, err := manager.ListArchived(all)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(archived).To(HaveLen(1))
+
+				Expect(manager.RestoreArchived(archived[0])).To(Succeed())
+
+				data, err := os.ReadFile(path.Join(meetupDir, "work", "team", "2021-01-01", "standup.md"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(data)).To(Equal("- [ ] archived task\n"))
+
+				data, err = os.ReadFile(path.Join(meetupDir, "work", "team", "2021-01-01", "standup"+meetup.AssetsDirSuffix, "board.png"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(data)).To(Equal("board"))
+
+				Expect(path.Join(meetupDir, meetup.ArchiveDirName)).ToNot(BeADirectory())
+
+				meetings, err := manager.ListMeetings(all)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(meetings).To(ConsistOf(old, recent))
+			})
+
+			It("will not restore over an existing meeting", func() {
+				Expect(manager.ArchiveMeeting(old)).To(Succeed())
+				Expect(manager.OpenMeeting(old)).To(Succeed())
+				Expect(manager.RestoreArchived(old)).ToNot(Succeed())
+			})
+
+			It("leaves the meeting archived if it cannot be restored completely", func() {
+				Expect(manager.RemoveMeeting(old)).To(Succeed())
+
+				assets := path.Join(meetupDir, "work", "team", "2021-01-01", "standup"+meetup.AssetsDirSuffix)
+				Expect(os.MkdirAll(assets, 0755)).To(Succeed())
+
+				Expect(manager.RestoreArchived(old)).ToNot(Succeed())
+				Expect(path.Join(meetupDir, "work", "team", "2021-01-01", "standup.md")).ToNot(BeAnExistingFile())
+
+				archived, err := manager.ListArchived(all)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(archived).To(HaveLen(1))
+
+				Expect(os.RemoveAll(assets)).To(Succeed())
+				Expect(manager.OpenMeeting(old)).To(Succeed())
+			})
+
+			It("migrates archived meetings when changing group_by", func() {
+				Expect(manager.RemoveMeeting(old)).To(Succeed())
+				Expect(manager.UpdateMeetingGroupBy(meetup.GroupByDate)).To(Succeed())
+
+				archived, err := manager.ListArchived(all)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(archived).To(HaveLen(1))
+
+				Expect(manager.RestoreArchived(archived[0])).To(Succeed())
+
+				data, err := os.ReadFile(path.Join(meetupDir, "2021-01-01", "work", "team", "standup.md"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(data)).To(Equal("- [ ] archived task\n"))
+
+				data, err = os.ReadFile(path.Join(meetupDir, "2021-01-01", "work", "team", "standup"+meetup.AssetsDirSuffix, "board.png"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(data)).To(Equal("board"))
+
+				Expect(path.Join(meetupDir, meetup.ArchiveDirName)).ToNot(BeADirectory())
+			})
+		})
+	}
+})
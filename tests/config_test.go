@@ -0,0 +1,159 @@
+package meetup_test
+
+import (
+	"os"
+	"path"
+
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseKey", func() {
+	It("splits dotted keys", func() {
+		Expect(meetup.ParseKey("default_metadata.group_by")).To(Equal([]string{"default_metadata", "group_by"}))
+	})
+
+	It("supports bracketed components", func() {
+		Expect(meetup.ParseKey("domains[work.product].extension")).To(Equal([]string{"domains", "work.product", "extension"}))
+	})
+
+	It("rejects malformed keys", func() {
+		for _, key := range []string{"", ".editor", "editor..x", "domains[work"} {
+			_, err := meetup.ParseKey(key)
+			Expect(err).To(HaveOccurred(), key)
+		}
+	})
+})
+
+var _ = Describe("Config", Ordered, func() {
+	var configDir, configPath string
+	var err error
+
+	BeforeAll(func() {
+		configDir, err = os.MkdirTemp("", "meetup-config")
+		Expect(err).ToNot(HaveOccurred())
+
+		configPath = path.Join(configDir, meetup.ConfigFilename)
+		Expect(os.WriteFile(configPath, []byte("# my config\nroot_dir: /notes\n"), 0644)).To(Succeed())
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(configDir)
+	})
+
+	It("can set values", func() {
+		Expect(meetup.SetConfigValue(configPath, meetup.DefaultProfile, "editor", "[code, --wait]")).To(Succeed())
+		Expect(meetup.SetConfigValue(configPath, "work", "root_dir", "/work")).To(Succeed())
+
+		data, err := os.ReadFile(configPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(HavePrefix("# my config\n"))
+
+		file, err := meetup.LoadConfigFile(configPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(file.Editor).To(Equal([]string{"code", "--wait"}))
+		Expect(file.Profiles["work"].RootDir).To(Equal("/work"))
+	})
+
+	It("rejects unknown keys", func() {
+		Expect(meetup.SetConfigValue(configPath, meetup.DefaultProfile, "colour", "blue")).ToNot(Succeed())
+	})
+
+	It("rejects invalid values", func() {
+		Expect(meetup.SetConfigValue(configPath, meetup.DefaultProfile, "default_metadata.group_by", "name")).ToNot(Succeed())
+		Expect(meetup.SetConfigValue(configPath, meetup.DefaultProfile, "editor", "{a: b}")).ToNot(Succeed())
+
+		file, err := meetup.LoadConfigFile(configPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(file.DefaultMetadata.GroupBy).To(BeEmpty())
+	})
+
+	It("can unset values", func() {
+		Expect(meetup.UnsetConfigValue(configPath, meetup.DefaultProfile, "editor")).To(Succeed())
+		Expect(meetup.UnsetConfigValue(configPath, meetup.DefaultProfile, "editor")).ToNot(Succeed())
+
+		file, err := meetup.LoadConfigFile(configPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(file.Editor).To(BeEmpty())
+	})
+
+	It("can get values", func() {
+		config := meetup.Config{Editor: []string{"code", "--wait"}, RootDir: "/notes"}
+
+		Expect(meetup.GetValue(config, "root_dir")).To(Equal("/notes"))
+		Expect(meetup.GetValue(config, "editor")).To(Equal("[code, --wait]"))
+
+		_, err := meetup.GetValue(config, "default_metadata.domains")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("records the source of each value", func() {
+		file, err := meetup.LoadConfigFile(configPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		effective, err := file.Resolve("work",
+			meetup.ConfigLayer{Source: meetup.SourceEnv, Config: meetup.Config{Editor: []string{"nano"}}},
+			meetup.ConfigLayer{Source: meetup.SourceFlag, Config: meetup.Config{}},
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(effective.RootDir).To(Equal("/work"))
+		Expect(effective.Editor).To(Equal([]string{"nano"}))
+		Expect(effective.Sources).To(Equal(map[string]meetup.ConfigSource{
+			"root_dir":                         meetup.SourceProfile,
+			"editor":                           meetup.SourceEnv,
+			"default_metadata.group_by":        meetup.SourceDefault,
+			"default_metadata.trash_retention": meetup.SourceDefault,
+		}))
+	})
+})
+
+var _ = Describe("Metadata", Ordered, func() {
+	var manager meetup.Manager
+	var meetupDir string
+	var err error
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(manager.OpenMeeting(testMeetings[0])).To(Succeed())
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("can set values", func() {
+		Expect(manager.SetMetadataValue("domains[work.product].extension", ".md")).To(Succeed())
+		Expect(manager.Metadata().Domains["work.product"].Extension).To(Equal(".md"))
+	})
+
+	It("rejects invalid values", func() {
+		Expect(manager.SetMetadataValue("domains[work].extension", "md")).ToNot(Succeed())
+		Expect(manager.SetMetadataValue("template_rules", "[{domain: work}]")).ToNot(Succeed())
+		Expect(manager.Metadata().Domains).ToNot(HaveKey("work"))
+	})
+
+	It("moves meetings when changing group_by", func() {
+		Expect(manager.SetMetadataValue("group_by", "date")).To(Succeed())
+		Expect(manager.Metadata().GroupBy).To(Equal(meetup.GroupByDate))
+		Expect(testMeetings[0].GetPath(meetupDir, meetup.GroupByDate)).To(BeAnExistingFile())
+	})
+
+	It("can unset values", func() {
+		Expect(manager.UnsetMetadataValue("domains")).To(Succeed())
+		Expect(manager.Metadata().Domains).To(BeEmpty())
+		Expect(manager.UnsetMetadataValue("group_by")).ToNot(Succeed())
+	})
+})
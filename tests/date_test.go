@@ -0,0 +1,111 @@
+package meetup_test
+
+import (
+	"time"
+
+	"github.com/gobwas/glob"
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseDate", func() {
+	// a wednesday
+	now := time.Date(2024, 3, 13, 15, 4, 5, 0, time.UTC)
+
+	type TestCase struct {
+		Input string
+		Date  string
+		Error bool
+	}
+
+	testCases := []TestCase{
+		{Input: "2024-01-31", Date: "2024-01-31"},
+		{Input: "today", Date: "2024-03-13"},
+		{Input: "Yesterday", Date: "2024-03-12"},
+		{Input: "tomorrow", Date: "2024-03-14"},
+		{Input: "+3d", Date: "2024-03-16"},
+		{Input: "-2w", Date: "2024-02-28"},
+		{Input: "last monday", Date: "2024-03-11"},
+		{Input: "last wednesday", Date: "2024-03-06"},
+		{Input: "next wed", Date: "2024-03-20"},
+		{Input: "next friday", Date: "2024-03-15"},
+		{Input: "this sunday", Date: "2024-03-17"},
+		{Input: "monday", Date: "2024-03-11"},
+		{Input: "next week", Date: "2024-03-20"},
+		{Input: "last month", Date: "2024-02-13"},
+		{Input: "+1.5d", Error: true},
+		{Input: "2024-13-01", Error: true},
+		{Input: "someday", Error: true},
+		{Input: "last year", Error: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		It("parses '"+testCase.Input+"'", func() {
+			date, err := meetup.ParseDate(testCase.Input, now)
+			if testCase.Error {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(date.Format(meetup.DateFormat)).To(Equal(testCase.Date))
+				Expect(date.Hour()).To(BeZero())
+			}
+		})
+	}
+
+	It("keeps to the last day of shorter months", func() {
+		for _, testCase := range []struct{ Now, Input, Date string }{
+			{Now: "2024-03-31", Input: "last month", Date: "2024-02-29"},
+			{Now: "2023-01-31", Input: "next month", Date: "2023-02-28"},
+			{Now: "2024-05-31", Input: "next month", Date: "2024-06-30"},
+			{Now: "2024-02-29", Input: "next month", Date: "2024-03-29"},
+			{Now: "2024-02-29", Input: "last month", Date: "2024-01-29"},
+			{Now: "2024-12-31", Input: "next month", Date: "2025-01-31"},
+		} {
+			now, err := time.Parse(meetup.DateFormat, testCase.Now)
+			Expect(err).ToNot(HaveOccurred())
+
+			date, err := meetup.ParseDate(testCase.Input, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(date.Format(meetup.DateFormat)).To(Equal(testCase.Date), "%s on %s", testCase.Input, testCase.Now)
+		}
+	})
+
+	It("finds the week and month", func() {
+		start, end := meetup.Week(now)
+		Expect(start.Format(meetup.DateFormat)).To(Equal("2024-03-11"))
+		Expect(end.Format(meetup.DateFormat)).To(Equal("2024-03-17"))
+
+		start, end = meetup.Month(now)
+		Expect(start.Format(meetup.DateFormat)).To(Equal("2024-03-01"))
+		Expect(end.Format(meetup.DateFormat)).To(Equal("2024-03-31"))
+	})
+})
+
+var _ = Describe("MeetingQuery", func() {
+	meeting := meetup.Meeting{Name: "standup", Domain: "work", Date: "2024-03-13"}
+
+	It("matches everything with unset fields", func() {
+		Expect(meetup.MeetingQuery{}.Match(meeting)).To(BeTrue())
+	})
+
+	It("matches inclusive date ranges", func() {
+		day := func(date string) time.Time {
+			t, err := time.Parse(meetup.DateFormat, date)
+			Expect(err).ToNot(HaveOccurred())
+			return t
+		}
+
+		Expect(meetup.MeetingQuery{Since: day("2024-03-13")}.Match(meeting)).To(BeTrue())
+		Expect(meetup.MeetingQuery{Since: day("2024-03-14")}.Match(meeting)).To(BeFalse())
+		Expect(meetup.MeetingQuery{Until: day("2024-03-13")}.Match(meeting)).To(BeTrue())
+		Expect(meetup.MeetingQuery{Until: day("2024-03-12")}.Match(meeting)).To(BeFalse())
+		Expect(meetup.MeetingQuery{
+			Name:  glob.MustCompile("stand*"),
+			Since: day("2024-03-01"),
+			Until: day("2024-03-31"),
+		}.Match(meeting)).To(BeTrue())
+	})
+})
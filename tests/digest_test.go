@@ -0,0 +1,107 @@
+package meetup_test
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gobwas/glob"
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Digest", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+
+	before := meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-05"}
+	first := meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-12"}
+	second := meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-14"}
+	retro := meetup.Meeting{Name: "retro", Domain: "work", Date: "2026-10-16"}
+	chores := meetup.Meeting{Name: "chores", Domain: "home", Date: "2026-10-17"}
+
+	contents := map[meetup.Meeting]string{
+		before: "- [ ] fix the flaky test\n- [x] ship release\n",
+		first:  "- [ ] fix the flaky test\n- [x] ship release\n- [ ] write docs\n",
+		second: "- [x] Fix the flaky test!\n- [ ] write docs due:friday\n",
+		retro:  "- [ ] plan offsite #team\n",
+		chores: "- [x] mow the lawn\n",
+	}
+
+	BeforeAll(func() {
+		meetupDir, manager = newTestManager(contents)
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	opts := meetup.DigestOptions{
+		Since: time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local),
+		Until: time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local),
+	}
+
+	descriptions := func(tasks []meetup.Task) []string {
+		var descriptions []string
+		for _, task := range tasks {
+			descriptions = append(descriptions, task.Description)
+		}
+
+		return descriptions
+	}
+
+	It("finds opened and closed tasks", func() {
+		digest, err := manager.Digest(meetup.MeetingQuery{}, opts)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(digest.Since).To(Equal("2026-10-12"))
+		Expect(digest.Until).To(Equal("2026-10-18"))
+		Expect(digest.Meetings).To(ConsistOf(first, second, retro, chores))
+		Expect(descriptions(digest.Opened)).To(ConsistOf("write docs due:friday", "plan offsite #team", "mow the lawn"))
+		Expect(descriptions(digest.Closed)).To(ConsistOf("Fix the flaky test!", "mow the lawn"))
+	})
+
+	It("writes markdown", func() {
+		digest, err := manager.Digest(meetup.MeetingQuery{}, opts)
+		Expect(err).ToNot(HaveOccurred())
+
+		var b strings.Builder
+		Expect(digest.WriteMarkdown(&b)).To(Succeed())
+
+		Expect(b.String()).To(HavePrefix("## Summary for 2026-10-12 to 2026-10-18\n\n### Meetings (4)\n\n- **home**: chores\n- **work**: retro, standup (2)\n"))
+		Expect(b.String()).To(ContainSubstring("\n### Closed (2)\n\n"))
+		Expect(b.String()).To(ContainSubstring("- [x] Fix the flaky test! (work standup, 2026-10-14)\n"))
+	})
+
+	It("writes empty digests", func() {
+		digest, err := manager.Digest(meetup.MeetingQuery{}, meetup.DigestOptions{
+			Since: time.Date(2026, 9, 1, 0, 0, 0, 0, time.Local),
+			Until: time.Date(2026, 9, 30, 0, 0, 0, 0, time.Local),
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		var b strings.Builder
+		Expect(digest.WriteMarkdown(&b)).To(Succeed())
+		Expect(b.String()).To(Equal("## Summary for 2026-09-01 to 2026-09-30\n\n### Meetings (0)\n\nNo meetings.\n\n### Opened (0)\n\nNo tasks.\n\n### Closed (0)\n\nNo tasks.\n"))
+	})
+
+	It("reopens recurring tasks", func() {
+		ops := map[meetup.Meeting]string{
+			{Name: "oncall", Domain: "ops", Date: "2026-10-01"}: "- [ ] rotate keys\n",
+			{Name: "oncall", Domain: "ops", Date: "2026-10-05"}: "- [x] rotate keys\n",
+			{Name: "oncall", Domain: "ops", Date: "2026-10-13"}: "- [ ] rotate keys\n",
+			{Name: "oncall", Domain: "ops", Date: "2026-10-15"}: "- [x] rotate keys\n",
+		}
+
+		for meeting, data := range ops {
+			Expect(manager.OpenMeeting(meeting)).To(Succeed())
+			Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte(data), 0644)).To(Succeed())
+		}
+
+		digest, err := manager.Digest(meetup.MeetingQuery{Domain: glob.MustCompile("ops")}, opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(descriptions(digest.Opened)).To(ConsistOf("rotate keys"))
+		Expect(descriptions(digest.Closed)).To(ConsistOf("rotate keys"))
+	})
+})
@@ -0,0 +1,136 @@
+package meetup_test
+
+import (
+	"os"
+	"path"
+
+	"github.com/gobwas/glob"
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/otiai10/copy"
+)
+
+var _ = Describe("Doctor", Ordered, func() {
+	var manager meetup.Manager
+	var meetupDir string
+	var err error
+
+	write := func(rel string, data string) {
+		p := path.Join(meetupDir, rel)
+		Expect(os.MkdirAll(path.Dir(p), 0755)).To(Succeed())
+		Expect(os.WriteFile(p, []byte(data), 0644)).To(Succeed())
+	}
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(copy.Copy(path.Join(meetupSampleDir, "group-by-domain"), meetupDir)).To(Succeed())
+
+		write("stray", "")
+		write(path.Join("single", "notadate", "sample"), "")
+		write(path.Join("2021-02-02", "moved", "sample"), "")
+		write(path.Join(meetup.TemplateDirName, "broken.md"), "{{ .Name ")
+		write(meetup.MetadataFilename, "group_by: domain\ndomain_templates:\n  meetup.test: template.md\ncolour: blue\n")
+		Expect(os.MkdirAll(path.Join(meetupDir, "empty", "nested"), 0755)).To(Succeed())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("skips malformed meetings when listing", func() {
+		meetings, err := manager.ListMeetings(meetup.MeetingQuery{
+			Name:   glob.MustCompile("*"),
+			Domain: glob.MustCompile("*"),
+			Date:   glob.MustCompile("*"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meetings).To(ConsistOf(testMeetings))
+	})
+
+	It("diagnoses problems", func() {
+		problems, err := manager.Diagnose()
+		Expect(err).ToNot(HaveOccurred())
+
+		kinds := map[string]meetup.ProblemKind{}
+		for _, problem := range problems {
+			kinds[problem.Path+" "+problem.Message] = problem.Kind
+		}
+
+		Expect(problems).To(HaveLen(7))
+		Expect(kinds).To(HaveKeyWithValue("stray path does not have enough components 'stray'", meetup.ProblemMalformedPath))
+		Expect(kinds).To(HaveKeyWithValue("single/notadate/sample 'notadate' is not a valid date (expected 2006-01-02)", meetup.ProblemInvalidDate))
+		Expect(kinds).To(HaveKeyWithValue("2021-02-02/moved/sample meeting is laid out for group_by 'date', but group_by is 'domain'", meetup.ProblemLayout))
+		Expect(kinds).To(HaveKeyWithValue("empty directory does not contain any meetings", meetup.ProblemEmptyDir))
+		Expect(kinds).To(HaveKeyWithValue(".metadata.yaml unknown key 'colour'", meetup.ProblemMetadata))
+		Expect(kinds).To(HaveKeyWithValue(".metadata.yaml 'domain_templates' has been replaced by 'template_rules'", meetup.ProblemDeprecatedMetadata))
+	})
+
+	It("repairs problems", func() {
+		problems, err := manager.Diagnose()
+		Expect(err).ToNot(HaveOccurred())
+
+		errs := manager.Repair(problems)
+		for i, problem := range problems {
+			if problem.Kind == meetup.ProblemTemplate {
+				Expect(errs[i]).To(HaveOccurred())
+			} else {
+				Expect(errs[i]).ToNot(HaveOccurred())
+			}
+		}
+
+		Expect(path.Join(meetupDir, meetup.QuarantineDirName, "stray")).To(BeAnExistingFile())
+		Expect(path.Join(meetupDir, meetup.QuarantineDirName, "single", "notadate", "sample")).To(BeAnExistingFile())
+		Expect(path.Join(meetupDir, "single", "notadate")).ToNot(BeADirectory())
+		Expect(path.Join(meetupDir, "moved", "2021-02-02", "sample")).To(BeAnExistingFile())
+		Expect(path.Join(meetupDir, "2021-02-02")).ToNot(BeADirectory())
+		Expect(path.Join(meetupDir, "empty")).ToNot(BeADirectory())
+		Expect(path.Join(meetupDir, meetup.MetadataFilename+".bak")).To(BeAnExistingFile())
+
+		Expect(manager.Metadata().TemplateRules).To(HaveExactElements(
+			And(HaveField("Domain", "meetup.test"), HaveField("Template", "template.md")),
+		))
+
+		problems, err = manager.Diagnose()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].Kind).To(Equal(meetup.ProblemTemplate))
+	})
+
+	It("renames meetings missing their domain's extension", func() {
+		Expect(manager.SetMetadataValue("domains[triple].extension", ".md")).To(Succeed())
+
+		problems, err := manager.Diagnose()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(problems).To(ContainElement(meetup.Problem{
+			Kind:    meetup.ProblemMissingExtension,
+			Path:    "triple/2021-01-01/sample",
+			Message: "meeting is missing the extension '.md' configured for domain 'triple'",
+		}))
+
+		for _, err := range manager.Repair(problems) {
+			if err != nil {
+				Expect(err).To(MatchError(ContainSubstring("cannot automatically repair")))
+			}
+		}
+
+		Expect(path.Join(meetupDir, "triple", "2021-01-01", "sample.md")).To(BeAnExistingFile())
+		Expect(path.Join(meetupDir, meetup.QuarantineDirName, "triple")).ToNot(BeADirectory())
+
+		meetings, err := manager.ListMeetings(meetup.MeetingQuery{
+			Name:   glob.MustCompile("*"),
+			Domain: glob.MustCompile("*"),
+			Date:   glob.MustCompile("*"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meetings).To(ContainElements(testMeetings))
+	})
+})
@@ -0,0 +1,407 @@
+package meetup_test
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"github.com/gobwas/glob"
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DomainConfig", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+	var err error
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+				Domains: map[string]meetup.DomainConfig{
+					"work": {
+						Extension: ".md",
+						Retention: meetup.Duration(time.Hour * 24 * 30),
+					},
+					"work.product": {
+						Editor: []string{"true"},
+						TaskStates: []meetup.TaskState{
+							{Name: "todo", Prefix: "TODO ", Symbol: "❌"},
+							{Name: "done", Prefix: "DONE ", Symbol: "✅", Done: true},
+						},
+					},
+				},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("rejects invalid extensions", func() {
+		_, err := meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				Domains: map[string]meetup.DomainConfig{
+					"work": {Extension: "md"},
+				},
+			},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("uses defaults for unconfigured domains", func() {
+		Expect(manager.DomainConfig("personal")).To(Equal(meetup.DomainConfig{
+			Editor: []string{"touch"},
+		}))
+	})
+
+	It("inherits overrides from parent domains", func() {
+		Expect(manager.DomainConfig("work.product.team")).To(Equal(meetup.DomainConfig{
+			Editor:    []string{"true"},
+			Extension: ".md",
+			TaskStates: []meetup.TaskState{
+				{Name: "todo", Prefix: "TODO ", Symbol: "❌"},
+				{Name: "done", Prefix: "DONE ", Symbol: "✅", Done: true},
+			},
+			Retention: meetup.Duration(time.Hour * 24 * 30),
+		}))
+	})
+
+	It("validates domains", func() {
+		Expect(meetup.ValidateDomain("work.team")).To(Succeed())
+
+		for _, domain := range []string{"", "work/team", "work..team", ".work", "work."} {
+			Expect(meetup.ValidateDomain(domain)).ToNot(Succeed(), domain)
+		}
+	})
+
+	It("does not match partial domain components", func() {
+		Expect(manager.DomainConfig("workshop").Extension).To(BeEmpty())
+	})
+
+	It("opens meetings with the domain extension", func() {
+		meeting := meetup.Meeting{Name: "sync", Domain: "work.product.team", Date: "2021-01-01"}
+		Expect(manager.OpenMeeting(meeting)).ToNot(HaveOccurred())
+		Expect(path.Join(meetupDir, "work", "product", "team", "2021-01-01", "sync.md")).To(BeAnExistingFile())
+
+		meetings, err := manager.ListMeetings(meetup.MeetingQuery{
+			Name:   glob.MustCompile("*"),
+			Domain: glob.MustCompile("*"),
+			Date:   glob.MustCompile("*"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meetings).To(ConsistOf(meeting))
+	})
+
+	It("only strips the configured extension", func() {
+		Expect(os.MkdirAll(path.Join(meetupDir, "personal", "2021-01-01"), 0755)).To(Succeed())
+		Expect(os.WriteFile(path.Join(meetupDir, "personal", "2021-01-01", "notes.md"), []byte("- [ ] read\n"), 0644)).To(Succeed())
+
+		meetings, err := manager.ListMeetings(meetup.MeetingQuery{
+			Name:   glob.MustCompile("*"),
+			Domain: glob.MustCompile("personal"),
+			Date:   glob.MustCompile("*"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meetings).To(ConsistOf(meetup.Meeting{Name: "notes.md", Domain: "personal", Date: "2021-01-01"}))
+
+		tasks, err := manager.Tasks(meetup.TaskQuery{Meeting: meetup.MeetingQuery{Domain: glob.MustCompile("personal")}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(HaveLen(1))
+
+		Expect(os.RemoveAll(path.Join(meetupDir, "personal"))).To(Succeed())
+	})
+
+	It("lists meetings created before the extension was configured", func() {
+		meeting := meetup.Meeting{Name: "standup", Domain: "home", Date: "2021-01-01"}
+		Expect(manager.OpenMeeting(meeting)).To(Succeed())
+		Expect(os.WriteFile(path.Join(meetupDir, "home", "2021-01-01", "standup"), []byte("- [ ] read\n"), 0644)).To(Succeed())
+
+		Expect(manager.SetMetadataValue("domains[home].extension", ".md")).To(Succeed())
+
+		meetings, err := manager.ListMeetings(meetup.MeetingQuery{
+			Name:   glob.MustCompile("*"),
+			Domain: glob.MustCompile("home"),
+			Date:   glob.MustCompile("*"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meetings).To(ConsistOf(meeting))
+
+		tasks, err := manager.Tasks(meetup.TaskQuery{Meeting: meetup.MeetingQuery{Domain: glob.MustCompile("home")}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(ConsistOf(meetup.Task{Meeting: meeting, State: meetup.TaskTodo, Description: "read", Line: 1}))
+
+		Expect(manager.RemoveMeeting(meeting)).To(Succeed())
+		Expect(path.Join(meetupDir, "home")).ToNot(BeADirectory())
+		Expect(manager.UnsetMetadataValue("domains[home]")).To(Succeed())
+	})
+
+	It("uses the domain task syntax", func() {
+		meeting := meetup.Meeting{Name: "sync", Domain: "work.product.team", Date: "2021-01-01"}
+		data := "TODO write tests\nDONE write code\n- [ ] not a task here\n"
+		Expect(os.WriteFile(path.Join(meetupDir, "work", "product", "team", "2021-01-01", "sync.md"), []byte(data), 0644)).To(Succeed())
+
+		tasks, err := manager.Tasks(meetup.TaskQuery{
+			Meeting: meetup.MeetingQuery{
+				Name:   glob.MustCompile("*"),
+				Domain: glob.MustCompile("*"),
+				Date:   glob.MustCompile("*"),
+			},
+			Description: glob.MustCompile("*"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(ConsistOf(
+			meetup.Task{Meeting: meeting, State: meetup.TaskState{Name: "todo", Prefix: "TODO ", Symbol: "❌"}, Description: "write tests", Line: 1},
+			meetup.Task{Meeting: meeting, State: meetup.TaskState{Name: "done", Prefix: "DONE ", Symbol: "✅", Done: true}, Description: "write code", Line: 2},
+		))
+	})
+
+	It("finds expired meetings", func() {
+		expired, err := manager.ExpiredMeetings(time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expired).To(BeEmpty())
+
+		expired, err = manager.ExpiredMeetings(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expired).To(ConsistOf(meetup.Meeting{Name: "sync", Domain: "work.product.team", Date: "2021-01-01"}))
+	})
+
+	It("removes meetings with the domain extension", func() {
+		Expect(manager.RemoveMeeting(meetup.Meeting{Name: "sync", Domain: "work.product.team", Date: "2021-01-01"})).To(Succeed())
+		Expect(path.Join(meetupDir, "work")).ToNot(BeADirectory())
+	})
+})
+
+var _ = Describe("Domain management", func() {
+	for _, groupBy := range []meetup.GroupStrategy{meetup.GroupByDomain, meetup.GroupByDate} {
+		groupBy := groupBy
+
+		Describe(string(groupBy), Ordered, func() {
+			var meetupDir string
+			var manager meetup.Manager
+			var err error
+
+			meetings := []meetup.Meeting{
+				{Name: "standup", Domain: "work.team", Date: "2021-01-01"},
+				{Name: "retro", Domain: "work.team", Date: "2021-01-08"},
+				{Name: "planning", Domain: "work", Date: "2021-02-01"},
+				{Name: "standup", Domain: "work.team.infra", Date: "2021-03-01"},
+				{Name: "standup", Domain: "other", Date: "2021-01-01"},
+				{Name: "sync", Domain: "personal", Date: "2020-12-31"},
+			}
+
+			list := func() []meetup.Meeting {
+				meetings, err := manager.ListMeetings(meetup.MeetingQuery{
+					Name:   glob.MustCompile("*"),
+					Domain: glob.MustCompile("*"),
+					Date:   glob.MustCompile("*"),
+				})
+				Expect(err).ToNot(HaveOccurred())
+				return meetings
+			}
+
+			BeforeAll(func() {
+				meetupDir, err = os.MkdirTemp("", "meetup-test")
+				Expect(err).ToNot(HaveOccurred())
+
+				manager, err = meetup.NewManager(meetup.Config{
+					RootDir: meetupDir,
+					Editor:  []string{"touch"},
+					DefaultMetadata: meetup.Metadata{
+						GroupBy: groupBy,
+					},
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				for _, meeting := range meetings {
+					Expect(manager.OpenMeeting(meeting)).To(Succeed())
+				}
+			})
+
+			AfterAll(func() {
+				os.RemoveAll(meetupDir)
+			})
+
+			It("builds the domain tree", func() {
+				root, err := manager.DomainTree()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(root.Total).To(Equal(6))
+				Expect(root.Children).To(HaveLen(3))
+
+				work := root.Children[2]
+				Expect(work.Domain).To(Equal("work"))
+				Expect(work.Meetings).To(Equal(1))
+				Expect(work.Total).To(Equal(4))
+				Expect(work.FirstDate).To(Equal("2021-01-01"))
+				Expect(work.LastDate).To(Equal("2021-03-01"))
+
+				Expect(work.Children).To(HaveLen(1))
+				Expect(work.Children[0].Domain).To(Equal("work.team"))
+				Expect(work.Children[0].Meetings).To(Equal(2))
+				Expect(work.Children[0].Total).To(Equal(3))
+			})
+
+			It("refuses to rename into a non-empty domain", func() {
+				_, err := manager.RenameDomain("work.team", "other")
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("refuses to rename into a subdomain", func() {
+				_, err := manager.RenameDomain("work", "work.team.old")
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("renames a domain subtree", func() {
+				moved, err := manager.RenameDomain("work.team", "work.squad")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(moved).To(HaveLen(3))
+
+				Expect(list()).To(ConsistOf(
+					meetup.Meeting{Name: "standup", Domain: "work.squad", Date: "2021-01-01"},
+					meetup.Meeting{Name: "retro", Domain: "work.squad", Date: "2021-01-08"},
+					meetup.Meeting{Name: "planning", Domain: "work", Date: "2021-02-01"},
+					meetup.Meeting{Name: "standup", Domain: "work.squad.infra", Date: "2021-03-01"},
+					meetup.Meeting{Name: "standup", Domain: "other", Date: "2021-01-01"},
+					meetup.Meeting{Name: "sync", Domain: "personal", Date: "2020-12-31"},
+				))
+			})
+
+			It("refuses to merge conflicting meetings", func() {
+				_, err := manager.MergeDomain("other", "work.squad")
+				Expect(err).To(HaveOccurred())
+				Expect(list()).To(ContainElement(meetup.Meeting{Name: "standup", Domain: "other", Date: "2021-01-01"}))
+			})
+
+			It("merges domains", func() {
+				moved, err := manager.MergeDomain("personal", "work.squad")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(moved).To(ConsistOf(meetup.Meeting{Name: "sync", Domain: "work.squad", Date: "2020-12-31"}))
+			})
+
+			It("refuses to remove subdomains unless recursive", func() {
+				_, err := manager.RemoveDomain("work.squad", false)
+				Expect(err).To(HaveOccurred())
+				Expect(list()).To(HaveLen(6))
+			})
+
+			It("removes domains to the trash", func() {
+				removed, err := manager.RemoveDomain("work.squad", true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(removed).To(HaveLen(4))
+
+				Expect(list()).To(ConsistOf(
+					meetup.Meeting{Name: "planning", Domain: "work", Date: "2021-02-01"},
+					meetup.Meeting{Name: "standup", Domain: "other", Date: "2021-01-01"},
+				))
+
+				items, err := manager.ListTrash()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(items).To(HaveLen(4))
+			})
+		})
+	}
+})
+
+var _ = Describe("Domain metadata", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+	var err error
+
+	standup := meetup.Meeting{Name: "standup", Domain: "work.team", Date: "2021-01-01"}
+	retro := meetup.Meeting{Name: "retro", Domain: "work.team.old", Date: "2021-01-02"}
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+				Domains: map[string]meetup.DomainConfig{
+					"work.team":     {Retention: meetup.Duration(time.Hour)},
+					"work.team.old": {Extension: ".md"},
+				},
+				TemplateRules: []meetup.TemplateRule{
+					{Domain: "work.team.*", Template: "team.md"},
+					{Domain: "work.*", Template: "work.md"},
+				},
+				Views: map[string]meetup.View{
+					"team": {Kind: meetup.ViewMeetings, Query: `domain:work.team OR domain:"work.team.old" OR domain:workshop`},
+				},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(manager.OpenMeeting(standup)).To(Succeed())
+		Expect(manager.OpenMeeting(retro)).To(Succeed())
+
+		Expect(os.WriteFile(path.Join(meetupDir, "work", "team", "2021-01-01", "standup"), []byte("[[2021-01-02 work.team.old retro]]\n"), 0644)).To(Succeed())
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("renames a domain into its parent", func() {
+		moved, err := manager.RenameDomain("work.team", "work")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(moved).To(ConsistOf(
+			meetup.Meeting{Name: "standup", Domain: "work", Date: "2021-01-01"},
+			meetup.Meeting{Name: "retro", Domain: "work.old", Date: "2021-01-02"},
+		))
+
+		Expect(path.Join(meetupDir, "work", "old", "2021-01-02", "retro.md")).To(BeAnExistingFile())
+
+		data, err := os.ReadFile(path.Join(meetupDir, "work", "2021-01-01", "standup"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("[[2021-01-02 work.old retro]]\n"))
+	})
+
+	It("updates the metadata for renamed domains", func() {
+		metadata := manager.Metadata()
+		Expect(metadata.Domains).To(Equal(map[string]meetup.DomainConfig{
+			"work":     {Retention: meetup.Duration(time.Hour)},
+			"work.old": {Extension: ".md"},
+		}))
+		Expect(metadata.TemplateRules).To(HaveExactElements(
+			And(HaveField("Domain", "work.*"), HaveField("Template", "team.md")),
+			And(HaveField("Domain", "work.*"), HaveField("Template", "work.md")),
+		))
+		Expect(metadata.Views["team"].Query).To(Equal(`domain:work OR domain:"work.old" OR domain:workshop`))
+
+		reloaded, err := meetup.NewManager(manager.Config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reloaded.Metadata().Domains).To(HaveKey("work.old"))
+	})
+
+	It("merges a subdomain into its parent", func() {
+		moved, err := manager.MergeDomain("work.old", "work")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(moved).To(ConsistOf(meetup.Meeting{Name: "retro", Domain: "work", Date: "2021-01-02"}))
+
+		Expect(manager.Metadata().Domains).To(Equal(map[string]meetup.DomainConfig{
+			"work": {Retention: meetup.Duration(time.Hour)},
+		}))
+	})
+
+	It("refuses to move a domain into itself", func() {
+		_, err := manager.MergeDomain("work", "work")
+		Expect(err).To(HaveOccurred())
+
+		_, err = manager.MergeDomain("work", "work.sub")
+		Expect(err).To(HaveOccurred())
+	})
+})
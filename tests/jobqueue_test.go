@@ -1,6 +1,9 @@
 package meetup_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -87,3 +90,118 @@ var _ = Describe("JobQueue", func() {
 		})
 	})
 })
+
+var _ = Describe("JobQueue executor", func() {
+	It("returns every error from Wait", func() {
+		jq := meetup.NewJobQueue(2)
+
+		for i := 0; i < 5; i++ {
+			i := i
+
+			jq.Go(func(context.Context) error {
+				if i%2 == 0 {
+					return fmt.Errorf("job %d failed", i)
+				}
+
+				return nil
+			})
+		}
+
+		err := jq.Wait()
+		Expect(err).To(HaveOccurred())
+
+		for _, i := range []int{0, 2, 4} {
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("job %d failed", i)))
+		}
+
+		Expect(jq.Metrics()).To(Equal(meetup.JobMetrics{Completed: 5, Failed: 3}))
+	})
+
+	It("skips remaining jobs after the first failure in fail fast mode", func() {
+		jq := meetup.NewJobQueueContext(context.Background(), 1, meetup.FailFast)
+		failure := errors.New("failure")
+
+		Expect(jq.Go(func(context.Context) error { return failure })).To(BeTrue())
+
+		Eventually(func() error { return jq.Context().Err() }).Should(MatchError(context.Canceled))
+
+		ran := false
+		Expect(jq.Go(func(context.Context) error {
+			ran = true
+			return nil
+		})).To(BeFalse())
+
+		Expect(jq.Wait()).To(MatchError(failure))
+		Expect(ran).To(BeFalse())
+		Expect(jq.Metrics()).To(Equal(meetup.JobMetrics{Completed: 1, Failed: 1, Skipped: 1}))
+	})
+
+	It("recovers panicking jobs", func() {
+		jq := meetup.NewJobQueue(1)
+
+		jq.Run(func() { panic("oh no") })
+
+		var panicErr *meetup.JobPanicError
+		Expect(errors.As(jq.Wait(), &panicErr)).To(BeTrue())
+		Expect(panicErr.Value).To(Equal("oh no"))
+	})
+
+	It("returns values from submitted jobs", func() {
+		jq := meetup.NewJobQueue(3)
+
+		var results []*meetup.JobResult[int]
+		for i := 0; i < 10; i++ {
+			i := i
+
+			results = append(results, meetup.Submit(jq, func(context.Context) (int, error) {
+				return i * i, nil
+			}))
+		}
+
+		Expect(jq.Wait()).To(Succeed())
+
+		for i, result := range results {
+			Expect(result.Get()).To(Equal(i * i))
+		}
+	})
+
+	It("reports running and queued jobs", func() {
+		jq := meetup.NewJobQueue(1)
+		release := make(chan struct{})
+
+		jq.Run(func() { <-release })
+		go jq.Run(func() {})
+
+		Eventually(jq.Metrics).Should(Equal(meetup.JobMetrics{Queued: 1, Running: 1}))
+
+		close(release)
+
+		Eventually(jq.Metrics).Should(Equal(meetup.JobMetrics{Completed: 2}))
+		Expect(jq.Wait()).To(Succeed())
+	})
+
+	It("releases the context once waited on", func() {
+		jq := meetup.NewJobQueue(1)
+
+		jq.Run(func() {})
+		Expect(jq.Wait()).To(Succeed())
+
+		Expect(jq.Context().Err()).To(MatchError(context.Canceled))
+		Expect(jq.Go(func(context.Context) error { return nil })).To(BeFalse())
+	})
+
+	It("skips jobs once the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		jq := meetup.NewJobQueueContext(ctx, 1, meetup.CollectAll)
+
+		cancel()
+
+		result := meetup.Submit(jq, func(context.Context) (string, error) {
+			return "ran", nil
+		})
+
+		_, err := result.Get()
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(jq.Wait()).To(MatchError(context.Canceled))
+	})
+})
@@ -0,0 +1,94 @@
+package meetup_test
+
+import (
+	"os"
+	"path"
+
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MoveMeeting", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+	var err error
+
+	standup := meetup.Meeting{Name: "standup", Domain: "work.team", Date: "2021-01-01"}
+	retro := meetup.Meeting{Name: "retro", Domain: "work.team", Date: "2021-01-02"}
+	moved := meetup.Meeting{Name: "standup", Domain: "work.other", Date: "2021-01-03"}
+
+	read := func(meeting meetup.Meeting) string {
+		data, err := os.ReadFile(meeting.GetPath(meetupDir, meetup.GroupByDomain))
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	write := func(meeting meetup.Meeting, data string) {
+		Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte(data), 0644)).To(Succeed())
+	}
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(manager.OpenMeeting(standup)).To(Succeed())
+		Expect(manager.OpenMeeting(retro)).To(Succeed())
+
+		assets := standup.GetPath(meetupDir, meetup.GroupByDomain) + meetup.AssetsDirSuffix
+		Expect(os.MkdirAll(assets, 0755)).To(Succeed())
+		Expect(os.WriteFile(path.Join(assets, "board.png"), []byte{}, 0644)).To(Succeed())
+
+		write(standup, "![board](standup.assets/board.png)\nsee [retro](../2021-01-02/retro) and [docs](https://example.com)\n")
+		write(retro, "follow up on [standup](../2021-01-01/standup#actions) ![board](../2021-01-01/standup.assets/board.png)\n")
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("does not list assets as meetings", func() {
+		problems, err := manager.Diagnose()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(problems).To(BeEmpty())
+	})
+
+	It("moves meetings with their assets", func() {
+		Expect(manager.MoveMeeting(standup, moved)).To(Succeed())
+
+		Expect(manager.MeetingExists(standup)).To(BeFalse())
+		Expect(manager.MeetingExists(moved)).To(BeTrue())
+		Expect(path.Join(meetupDir, "work", "other", "2021-01-03", "standup.assets", "board.png")).To(BeAnExistingFile())
+		Expect(path.Join(meetupDir, "work", "team", "2021-01-01")).ToNot(BeADirectory())
+	})
+
+	It("rewrites links to the moved meeting", func() {
+		Expect(read(retro)).To(Equal("follow up on [standup](../../other/2021-01-03/standup#actions) ![board](../../other/2021-01-03/standup.assets/board.png)\n"))
+	})
+
+	It("rewrites relative links in the moved meeting", func() {
+		Expect(read(moved)).To(Equal("![board](standup.assets/board.png)\nsee [retro](../../team/2021-01-02/retro) and [docs](https://example.com)\n"))
+	})
+
+	It("only overwrites when asked", func() {
+		Expect(manager.MoveMeeting(retro, moved)).ToNot(Succeed())
+
+		Expect(manager.MoveMeetingWithOptions(retro, moved, meetup.MoveOptions{Overwrite: true})).To(Succeed())
+		Expect(manager.MeetingExists(retro)).To(BeFalse())
+		Expect(read(moved)).To(HavePrefix("follow up on"))
+
+		items, err := manager.ListTrash()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(items).To(HaveLen(1))
+		Expect(items[0].Assets).To(BeTrue())
+	})
+})
@@ -0,0 +1,114 @@
+package meetup_test
+
+import (
+	"os"
+	"time"
+
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListOptions", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+
+	standupOld := meetup.Meeting{Name: "standup", Domain: "work", Date: "2021-01-01"}
+	standupNew := meetup.Meeting{Name: "standup", Domain: "work", Date: "2021-01-02"}
+	retro := meetup.Meeting{Name: "retro", Domain: "work", Date: "2021-01-01"}
+	sync := meetup.Meeting{Name: "sync", Domain: "personal", Date: "2021-01-03"}
+
+	contents := map[meetup.Meeting]string{
+		standupOld: "- [ ] b\n- [x] a\n",
+		standupNew: "- [ ] c\n",
+		retro:      "- [ ] d\n- [ ] e\n- [ ] f\n",
+		sync:       "",
+	}
+
+	BeforeAll(func() {
+		meetupDir, manager = newTestManager(contents)
+
+		modified := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		for _, meeting := range []meetup.Meeting{sync, retro, standupNew, standupOld} {
+			p := meeting.GetPath(meetupDir, meetup.GroupByDomain)
+
+			modified = modified.Add(time.Hour)
+			Expect(os.Chtimes(p, modified, modified)).To(Succeed())
+		}
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	list := func(opts meetup.ListOptions) []meetup.Meeting {
+		meetings, err := manager.ListMeetingsWithOptions(meetup.MeetingQuery{}, opts)
+		Expect(err).ToNot(HaveOccurred())
+		return meetings
+	}
+
+	sortBy := func(raw string) meetup.ListOptions {
+		keys, err := meetup.ParseSortKeys(raw)
+		Expect(err).ToNot(HaveOccurred())
+		return meetup.ListOptions{Sort: keys}
+	}
+
+	It("orders meetings chronologically by default", func() {
+		Expect(list(meetup.ListOptions{})).To(Equal([]meetup.Meeting{retro, standupOld, standupNew, sync}))
+	})
+
+	It("sorts by multiple keys", func() {
+		Expect(list(sortBy("domain,-date"))).To(Equal([]meetup.Meeting{sync, standupNew, retro, standupOld}))
+		Expect(list(sortBy("-name"))).To(Equal([]meetup.Meeting{sync, standupOld, standupNew, retro}))
+	})
+
+	It("sorts by modification time and task count", func() {
+		Expect(list(sortBy("-modified"))).To(Equal([]meetup.Meeting{standupOld, standupNew, retro, sync}))
+		Expect(list(sortBy("-tasks"))).To(Equal([]meetup.Meeting{retro, standupOld, standupNew, sync}))
+	})
+
+	It("paginates meetings", func() {
+		Expect(list(meetup.ListOptions{Offset: 1, Limit: 2})).To(Equal([]meetup.Meeting{standupOld, standupNew}))
+		Expect(list(meetup.ListOptions{Offset: 10})).To(BeEmpty())
+	})
+
+	It("only includes the latest meetings", func() {
+		Expect(list(meetup.ListOptions{Latest: true})).To(Equal([]meetup.Meeting{retro, standupNew, sync}))
+	})
+
+	It("rejects invalid options", func() {
+		_, err := meetup.ParseSortKeys("date,size")
+		Expect(err).To(HaveOccurred())
+
+		_, err = manager.ListMeetingsWithOptions(meetup.MeetingQuery{}, meetup.ListOptions{Limit: -1})
+		Expect(err).To(HaveOccurred())
+
+		_, err = manager.ListMeetingsWithOptions(meetup.MeetingQuery{}, sortBy("description"))
+		Expect(err).To(HaveOccurred())
+
+		_, err = manager.TasksWithOptions(meetup.TaskQuery{}, sortBy("tasks"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("orders tasks deterministically", func() {
+		descriptions := func(opts meetup.ListOptions) []string {
+			tasks, err := manager.TasksWithOptions(meetup.TaskQuery{}, opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			var found []string
+			for _, task := range tasks {
+				found = append(found, task.Description)
+			}
+
+			return found
+		}
+
+		for i := 0; i < 5; i++ {
+			Expect(descriptions(meetup.ListOptions{})).To(Equal([]string{"d", "e", "f", "b", "a", "c"}))
+		}
+
+		Expect(descriptions(sortBy("description"))).To(Equal([]string{"a", "b", "c", "d", "e", "f"}))
+		Expect(descriptions(meetup.ListOptions{Latest: true, Limit: 2, Offset: 2})).To(Equal([]string{"f", "c"}))
+	})
+})
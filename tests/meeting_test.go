@@ -113,3 +113,149 @@ var _ = Describe("ManageMeeting", Ordered, func() {
 		Expect(path.Join(meetupDir, "2021-01-01")).ShouldNot(BeADirectory())
 	})
 })
+
+var _ = Describe("ModifyMeeting", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+	var err error
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(manager.AddTemplate(path.Join(exampleDir, "templates", "simple.md"))).To(Succeed())
+
+		for _, meeting := range testMeetings {
+			Expect(manager.OpenMeeting(meeting)).To(Succeed())
+		}
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("can move meetings", func() {
+		to := meetup.Meeting{Name: "renamed", Domain: "moved", Date: "2021-02-02"}
+
+		Expect(manager.MoveMeeting(testMeetings[0], to)).To(Succeed())
+		Expect(manager.MeetingExists(testMeetings[0])).To(BeFalse())
+		Expect(manager.MeetingExists(to)).To(BeTrue())
+		Expect(path.Join(meetupDir, "triple")).ToNot(BeADirectory())
+
+		Expect(manager.MoveMeeting(to, testMeetings[0])).To(Succeed())
+	})
+
+	It("will not overwrite meetings when moving", func() {
+		Expect(manager.MoveMeeting(testMeetings[1], testMeetings[2])).ToNot(Succeed())
+		Expect(manager.MeetingExists(testMeetings[1])).To(BeTrue())
+	})
+
+	It("can re-apply templates", func() {
+		meetingPath := testMeetings[1].GetPath(meetupDir, meetup.GroupByDomain)
+		Expect(os.WriteFile(meetingPath, []byte("old notes"), 0644)).To(Succeed())
+
+		Expect(manager.ApplyTemplate(testMeetings[1], "simple.md")).To(Succeed())
+
+		data, err := os.ReadFile(meetingPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("2021-01-01 single Sample"))
+
+		items, err := manager.ListTrash()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(items).To(HaveLen(1))
+		Expect(items[0].Meeting).To(Equal(testMeetings[1]))
+	})
+
+	It("cannot apply missing templates", func() {
+		Expect(manager.ApplyTemplate(testMeetings[1], "missing.md")).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("MeetingTimes", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+	var err error
+
+	untimed := meetup.Meeting{Name: "sync", Domain: "work", Date: "2021-01-01"}
+	afternoon := meetup.Meeting{Name: "sync", Domain: "work", Date: "2021-01-01", Time: "14:30"}
+	morning := meetup.Meeting{Name: "sync", Domain: "work", Date: "2021-01-01", Time: "09:00"}
+
+	list := func() []meetup.Meeting {
+		meetings, err := manager.ListMeetings(meetup.MeetingQuery{})
+		Expect(err).ToNot(HaveOccurred())
+		return meetings
+	}
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("includes the time in the path", func() {
+		Expect(manager.OpenMeeting(afternoon)).To(Succeed())
+		Expect(path.Join(meetupDir, "work", "2021-01-01", "sync@1430")).To(BeAnExistingFile())
+	})
+
+	It("lists meetings chronologically within a day", func() {
+		Expect(manager.OpenMeeting(untimed)).To(Succeed())
+		Expect(manager.OpenMeeting(morning)).To(Succeed())
+		Expect(list()).To(Equal([]meetup.Meeting{untimed, morning, afternoon}))
+	})
+
+	It("creates new meetings with the next sequence", func() {
+		second := manager.NextSequence(afternoon)
+		Expect(second.Sequence).To(Equal(2))
+		Expect(manager.OpenMeeting(second)).To(Succeed())
+		Expect(path.Join(meetupDir, "work", "2021-01-01", "sync@1430+2")).To(BeAnExistingFile())
+
+		Expect(manager.NextSequence(afternoon).Sequence).To(Equal(3))
+		Expect(list()).To(Equal([]meetup.Meeting{untimed, morning, afternoon, second}))
+	})
+
+	It("does not add a sequence to new meetings", func() {
+		meeting := meetup.Meeting{Name: "retro", Domain: "work", Date: "2021-01-01"}
+		Expect(manager.NextSequence(meeting)).To(Equal(meeting))
+	})
+
+	It("splits meeting names", func() {
+		name, t, sequence := meetup.SplitMeetingName("sync@14:30+2")
+		Expect([]any{name, t, sequence}).To(Equal([]any{"sync", "14:30", 2}))
+
+		name, t, sequence = meetup.SplitMeetingName("sync+3")
+		Expect([]any{name, t, sequence}).To(Equal([]any{"sync", "", 3}))
+
+		name, t, sequence = meetup.SplitMeetingName("sync@9999")
+		Expect([]any{name, t, sequence}).To(Equal([]any{"sync@9999", "", 0}))
+	})
+
+	It("parses times", func() {
+		for input, expected := range map[string]string{"14:30": "14:30", "0930": "09:30", "2:30pm": "14:30", "9am": "09:00"} {
+			Expect(meetup.ParseTime(input)).To(Equal(expected))
+		}
+
+		_, err := meetup.ParseTime("25:00")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,6 +1,7 @@
 package meetup_test
 
 import (
+	"os"
 	"path"
 	"testing"
 
@@ -33,6 +34,29 @@ var (
 	}
 )
 
+// newTestManager creates a manager in a new temporary directory which groups meetings by domain, and writes each of
+// the given meetings with its contents. The directory is returned along with the manager.
+func newTestManager(contents map[meetup.Meeting]string) (string, meetup.Manager) {
+	meetupDir, err := os.MkdirTemp("", "meetup-test")
+	Expect(err).ToNot(HaveOccurred())
+
+	manager, err := meetup.NewManager(meetup.Config{
+		RootDir: meetupDir,
+		Editor:  []string{"touch"},
+		DefaultMetadata: meetup.Metadata{
+			GroupBy: meetup.GroupByDomain,
+		},
+	})
+	Expect(err).ToNot(HaveOccurred())
+
+	for meeting, data := range contents {
+		Expect(manager.OpenMeeting(meeting)).To(Succeed())
+		Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte(data), 0644)).To(Succeed())
+	}
+
+	return meetupDir, manager
+}
+
 func TestPkg(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Manager Suite")
@@ -0,0 +1,93 @@
+package meetup_test
+
+import (
+	"os"
+	"path"
+
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConfigFile", Ordered, func() {
+	var configDir, configPath string
+	var err error
+
+	BeforeAll(func() {
+		configDir, err = os.MkdirTemp("", "meetup-config")
+		Expect(err).ToNot(HaveOccurred())
+
+		configPath = path.Join(configDir, "meetup", meetup.ConfigFilename)
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(configDir)
+	})
+
+	It("treats a missing file as empty", func() {
+		file, err := meetup.LoadConfigFile(configPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(file.ProfileNames()).To(Equal([]string{meetup.DefaultProfile}))
+		Expect(file.ActiveProfile("")).To(Equal(meetup.DefaultProfile))
+	})
+
+	It("can save and load profiles", func() {
+		file := meetup.ConfigFile{
+			Config: meetup.Config{
+				Editor: []string{"vim"},
+			},
+			Profile: "work",
+			Profiles: map[string]meetup.Config{
+				"work": {
+					RootDir: "/work",
+				},
+				"personal": {
+					RootDir: "/personal",
+					Editor:  []string{"nano"},
+					DefaultMetadata: meetup.Metadata{
+						GroupBy: meetup.GroupByDate,
+					},
+				},
+			},
+		}
+		Expect(file.Save(configPath)).To(Succeed())
+
+		loaded, err := meetup.LoadConfigFile(configPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(loaded).To(Equal(file))
+		Expect(loaded.ProfileNames()).To(Equal([]string{meetup.DefaultProfile, "personal", "work"}))
+	})
+
+	It("selects the active profile", func() {
+		file, err := meetup.LoadConfigFile(configPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(file.ActiveProfile("")).To(Equal("work"))
+		Expect(file.ActiveProfile("personal")).To(Equal("personal"))
+	})
+
+	It("layers profiles over the top-level config", func() {
+		file, err := meetup.LoadConfigFile(configPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		config, err := file.ProfileConfig("work")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.RootDir).To(Equal("/work"))
+		Expect(config.Editor).To(Equal([]string{"vim"}))
+		Expect(config.DefaultMetadata.GroupBy).To(Equal(meetup.GroupByDomain))
+
+		config, err = file.ProfileConfig("personal")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.RootDir).To(Equal("/personal"))
+		Expect(config.Editor).To(Equal([]string{"nano"}))
+		Expect(config.DefaultMetadata.GroupBy).To(Equal(meetup.GroupByDate))
+	})
+
+	It("fails for unknown profiles", func() {
+		file, err := meetup.LoadConfigFile(configPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = file.ProfileConfig("nope")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,175 @@
+package meetup_test
+
+import (
+	"errors"
+	"os"
+
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Query", func() {
+	standup := meetup.Meeting{Name: "standup", Domain: "work.team", Date: "2024-01-02", Time: "09:00"}
+	retro := meetup.Meeting{Name: "retro", Domain: "work.team", Date: "2023-12-20"}
+	sync := meetup.Meeting{Name: "sync", Domain: "personal", Date: "2024-02-01"}
+
+	tags := func(tags ...string) func() []string {
+		return func() []string { return tags }
+	}
+
+	matchMeetings := func(raw string) []meetup.Meeting {
+		query, err := meetup.ParseMeetingQuery(raw)
+		Expect(err).ToNot(HaveOccurred())
+
+		var matched []meetup.Meeting
+		for _, meeting := range []meetup.Meeting{standup, retro, sync} {
+			meetingTags := tags()
+			if meeting == retro {
+				meetingTags = tags("urgent")
+			}
+
+			if query.MatchMeeting(meeting, meetingTags) {
+				matched = append(matched, meeting)
+			}
+		}
+
+		return matched
+	}
+
+	It("matches fields", func() {
+		Expect(matchMeetings("domain:work.*")).To(Equal([]meetup.Meeting{standup, retro}))
+		Expect(matchMeetings("name=sync")).To(Equal([]meetup.Meeting{sync}))
+		Expect(matchMeetings("name!=sync")).To(Equal([]meetup.Meeting{standup, retro}))
+		Expect(matchMeetings("date>=2024-01-01")).To(Equal([]meetup.Meeting{standup, sync}))
+		Expect(matchMeetings("date:2023-*")).To(Equal([]meetup.Meeting{retro}))
+		Expect(matchMeetings("time<10:00")).To(Equal([]meetup.Meeting{standup}))
+		Expect(matchMeetings("tag:urg*")).To(Equal([]meetup.Meeting{retro}))
+	})
+
+	It("combines terms", func() {
+		Expect(matchMeetings("domain:work.* AND NOT name:standup AND (date>=2024-01-01 OR tag:urgent)")).To(Equal([]meetup.Meeting{retro}))
+		Expect(matchMeetings("name:sync OR name:retro")).To(Equal([]meetup.Meeting{retro, sync}))
+		Expect(matchMeetings("domain:work.* date>2024-01-01")).To(Equal([]meetup.Meeting{standup}))
+		Expect(matchMeetings("not NOT name:sync")).To(Equal([]meetup.Meeting{sync}))
+		Expect(matchMeetings(`name:"s*"`)).To(Equal([]meetup.Meeting{standup, sync}))
+	})
+
+	It("matches non-ascii values", func() {
+		for _, raw := range []string{"name:voilà", "name=Åsa", "(name:é)", "name:à\u00a0OR\u00a0name:b"} {
+			_, err := meetup.ParseMeetingQuery(raw)
+			Expect(err).ToNot(HaveOccurred(), raw)
+		}
+
+		query, err := meetup.ParseTaskQuery("desc:*café* AND is:incomplete")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(query.MatchTask(meetup.Task{Meeting: standup, Description: "order from the café"})).To(BeTrue())
+	})
+
+	It("binds AND tighter than OR", func() {
+		Expect(matchMeetings("name:sync OR name:standup AND date<2024-01-01")).To(Equal([]meetup.Meeting{sync}))
+	})
+
+	It("matches tasks", func() {
+		query, err := meetup.ParseTaskQuery("is:incomplete AND (tag:urgent OR desc:*review*)")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(query.MatchTask(meetup.Task{Meeting: standup, Description: "fix the build #urgent"})).To(BeTrue())
+		Expect(query.MatchTask(meetup.Task{Meeting: standup, Description: "review the pr"})).To(BeTrue())
+		Expect(query.MatchTask(meetup.Task{Meeting: standup, Description: "review the pr", State: meetup.TaskDone})).To(BeFalse())
+		Expect(query.MatchTask(meetup.Task{Meeting: standup, Description: "issue#urgent"})).To(BeFalse())
+	})
+
+	It("matches due dates", func() {
+		query, err := meetup.ParseTaskQuery("due<=2024-01-05")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(query.MatchTask(meetup.Task{Meeting: standup, Due: "2024-01-05"})).To(BeTrue())
+		Expect(query.MatchTask(meetup.Task{Meeting: standup, Due: "2024-01-06"})).To(BeFalse())
+		Expect(query.MatchTask(meetup.Task{Meeting: standup})).To(BeFalse())
+
+		_, err = meetup.ParseMeetingQuery("due<=2024-01-05")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("extracts task tags", func() {
+		Expect(meetup.Task{Description: "#a fix #b-c and #a again, not a#d or # e"}.Tags()).To(Equal([]string{"a", "b-c"}))
+	})
+
+	DescribeTable("reports syntax errors with positions",
+		func(raw string, pos int) {
+			_, err := meetup.ParseMeetingQuery(raw)
+
+			var syntaxErr *meetup.QuerySyntaxError
+			Expect(errors.As(err, &syntaxErr)).To(BeTrue())
+			Expect(syntaxErr.Pos).To(Equal(pos))
+		},
+		Entry("empty", "", 0),
+		Entry("bare word", "name:a standup", 7),
+		Entry("unclosed paren", "name:a AND (name:b", 11),
+		Entry("extra paren", "name:a)", 6),
+		Entry("dangling operator", "name:a AND", 10),
+		Entry("missing value", "name: AND", 5),
+		Entry("missing field", ":a", 0),
+		Entry("unknown field", "name:a OR size>3", 10),
+		Entry("unsupported operator", "name>a", 4),
+		Entry("invalid date", "date>someday", 5),
+		Entry("unterminated quote", `name:"a`, 5),
+		Entry("task field", "name:a description:b", 7),
+		Entry("unknown state", "is:done", 3),
+	)
+})
+
+var _ = Describe("QueryListing", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+	var err error
+
+	standup := meetup.Meeting{Name: "standup", Domain: "work", Date: "2024-01-02"}
+	retro := meetup.Meeting{Name: "retro", Domain: "work", Date: "2024-01-03"}
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(manager.OpenMeeting(standup)).To(Succeed())
+		Expect(manager.OpenMeeting(retro)).To(Succeed())
+
+		Expect(os.WriteFile(standup.GetPath(meetupDir, meetup.GroupByDomain), []byte("#blocked\n- [ ] unblock the build #urgent\n- [ ] lunch\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(retro.GetPath(meetupDir, meetup.GroupByDomain), []byte("- [ ] write notes\n"), 0644)).To(Succeed())
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("filters meetings by their contents' tags", func() {
+		query, err := meetup.ParseMeetingQuery("tag:blocked OR name:none")
+		Expect(err).ToNot(HaveOccurred())
+
+		meetings, err := manager.ListMeetings(meetup.MeetingQuery{Query: query})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meetings).To(Equal([]meetup.Meeting{standup}))
+	})
+
+	It("filters tasks", func() {
+		query, err := meetup.ParseTaskQuery("tag:urgent OR name:retro")
+		Expect(err).ToNot(HaveOccurred())
+
+		tasks, err := manager.Tasks(meetup.TaskQuery{Query: query})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(Equal([]meetup.Task{
+			{Meeting: standup, State: meetup.TaskTodo, Description: "unblock the build #urgent", Line: 2},
+			{Meeting: retro, State: meetup.TaskTodo, Description: "write notes", Line: 1},
+		}))
+	})
+})
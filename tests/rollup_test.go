@@ -0,0 +1,191 @@
+package meetup_test
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"time"
+
+	"github.com/gobwas/glob"
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rollup", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+
+	// a wednesday
+	date := time.Date(2026, 10, 14, 9, 0, 0, 0, time.Local)
+
+	before := meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-05"}
+	monday := meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-12"}
+	thursday := meetup.Meeting{Name: "retro", Domain: "work.team", Date: "2026-10-15"}
+	after := meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-19"}
+
+	contents := map[meetup.Meeting]string{
+		before:   "- [ ] fix the flaky test\n",
+		monday:   "# Standup\n\n## Blockers\n\n- [ ] fix the flaky test\n- [ ] write docs\n\n```\n# not a heading\n```\n",
+		thursday: "# Retro\n\n- [x] Fix the flaky test!\n- [x] write docs\n",
+		after:    "- [ ] plan offsite\n",
+	}
+
+	note := meetup.Meeting{Name: meetup.DefaultRollupName, Domain: "rollup.weekly", Date: "2026-10-12"}
+
+	read := func(meeting meetup.Meeting) string {
+		data, err := os.ReadFile(meeting.GetPath(meetupDir, meetup.GroupByDomain))
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	descriptions := func(tasks []meetup.Task) []string {
+		var descriptions []string
+		for _, task := range tasks {
+			descriptions = append(descriptions, task.Description)
+		}
+
+		return descriptions
+	}
+
+	BeforeAll(func() {
+		meetupDir, manager = newTestManager(contents)
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("gathers the meetings in the week", func() {
+		data, err := manager.Rollup(meetup.PeriodWeek, date)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(data.Meeting).To(Equal(note))
+		Expect(data.Label).To(Equal("2026-W42"))
+		Expect(data.Start).To(Equal("2026-10-12"))
+		Expect(data.End).To(Equal("2026-10-18"))
+
+		Expect(data.Meetings).To(HaveLen(2))
+		Expect(data.Meetings[0].Meeting).To(Equal(monday))
+		Expect(data.Meetings[0].Link).To(Equal("../../../work/2026-10-12/standup"))
+		Expect(data.Meetings[0].Headings).To(Equal([]string{"Standup", "Blockers"}))
+		Expect(descriptions(data.Meetings[0].Opened)).To(Equal([]string{"write docs"}))
+		Expect(data.Meetings[0].Closed).To(BeEmpty())
+
+		Expect(data.Meetings[1].Meeting).To(Equal(thursday))
+		Expect(data.Meetings[1].Link).To(Equal("../../../work/team/2026-10-15/retro"))
+		Expect(descriptions(data.Meetings[1].Opened)).To(Equal([]string{"Fix the flaky test!", "write docs"}))
+		Expect(descriptions(data.Meetings[1].Closed)).To(Equal([]string{"Fix the flaky test!", "write docs"}))
+
+		// the flaky test was opened the week before, but is a different task in work.team
+		Expect(descriptions(data.Opened)).To(Equal([]string{"write docs", "Fix the flaky test!", "write docs"}))
+		Expect(descriptions(data.Closed)).To(Equal([]string{"Fix the flaky test!", "write docs"}))
+	})
+
+	It("gathers the meetings in the month", func() {
+		data, err := manager.Rollup(meetup.PeriodMonth, date)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(data.Meeting).To(Equal(meetup.Meeting{Name: meetup.DefaultRollupName, Domain: "rollup.monthly", Date: "2026-10-01"}))
+		Expect(data.Label).To(Equal("2026-10"))
+		Expect(data.End).To(Equal("2026-10-31"))
+		Expect(data.Meetings).To(HaveLen(4))
+	})
+
+	It("rejects other periods", func() {
+		_, err := manager.Rollup(meetup.PeriodYear, date)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("creates the rollup note", func() {
+		meeting, err := manager.CreateRollup(meetup.PeriodWeek, date, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meeting).To(Equal(note))
+
+		generated := read(note)
+		Expect(generated).To(HavePrefix("# 2026-W42 rollup (2026-10-12 to 2026-10-18)\n\n2 meetings, 3 tasks opened, 2 tasks closed.\n"))
+		Expect(generated).To(ContainSubstring("## [2026-10-12 work standup](../../../work/2026-10-12/standup)\n\n- Standup\n- Blockers\n\nOpened:\n\n- ❌ write docs\n"))
+
+		// the rollup's tasks are not tasks of the note
+		tasks, err := manager.Tasks(meetup.TaskQuery{Meeting: meetup.MeetingQuery{Domain: glob.MustCompile("rollup.*")}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(BeEmpty())
+
+		// rollups are not included in other rollups
+		data, err := manager.Rollup(meetup.PeriodMonth, date)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data.Meetings).To(HaveLen(4))
+	})
+
+	It("keeps curated notes unless regenerated", func() {
+		Expect(os.WriteFile(note.GetPath(meetupDir, meetup.GroupByDomain), []byte("curated\n"), 0644)).To(Succeed())
+
+		_, err := manager.CreateRollup(meetup.PeriodWeek, date, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(read(note)).To(Equal("curated\n"))
+
+		_, err = manager.CreateRollup(meetup.PeriodWeek, date, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(read(note)).To(HavePrefix("# 2026-W42 rollup"))
+
+		trash, err := manager.ListTrash()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(trash).To(HaveLen(1))
+	})
+
+	It("uses configured templates", func() {
+		Expect(os.MkdirAll(path.Join(meetupDir, meetup.TemplateDirName), 0755)).To(Succeed())
+		Expect(os.WriteFile(path.Join(meetupDir, meetup.TemplateDirName, "weekly.md"), []byte("{{ .Label }}:{{ range .Meetings }} {{ .Name }}{{ end }}\n"), 0644)).To(Succeed())
+
+		templated, err := meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+				Rollups: map[meetup.StatsPeriod]meetup.RollupConfig{
+					meetup.PeriodWeek: {Domain: "notes.weekly", Name: "rollup", Template: "weekly.md"},
+				},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		data, err := templated.Rollup(meetup.PeriodWeek, date)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data.Meeting).To(Equal(meetup.Meeting{Name: "rollup", Domain: "notes.weekly", Date: "2026-10-12", Template: "weekly.md"}))
+
+		var buffer bytes.Buffer
+		Expect(templated.RenderRollup(&buffer, data)).To(Succeed())
+		// the earlier note in rollup.weekly is no longer a rollup
+		Expect(buffer.String()).To(Equal("2026-W42: summary standup retro\n"))
+	})
+
+	It("keeps the previous note when regenerating fails", func() {
+		broken, err := meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+				Rollups: map[meetup.StatsPeriod]meetup.RollupConfig{
+					meetup.PeriodWeek: {Domain: "rollup.weekly", Name: meetup.DefaultRollupName, Template: "missing.md"},
+				},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.WriteFile(note.GetPath(meetupDir, meetup.GroupByDomain), []byte("curated\n"), 0644)).To(Succeed())
+
+		_, err = broken.CreateRollup(meetup.PeriodWeek, date, true)
+		Expect(err).To(HaveOccurred())
+		Expect(read(note)).To(Equal("curated\n"))
+
+		trash, err := broken.ListTrash()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(trash).To(HaveLen(1))
+	})
+
+	It("validates rollup config", func() {
+		Expect(meetup.Metadata{Rollups: map[meetup.StatsPeriod]meetup.RollupConfig{meetup.PeriodMonth: {Domain: "notes.monthly"}}}.Validate()).To(Succeed())
+		Expect(meetup.Metadata{Rollups: map[meetup.StatsPeriod]meetup.RollupConfig{meetup.PeriodDay: {}}}.Validate()).ToNot(Succeed())
+		Expect(meetup.Metadata{Rollups: map[meetup.StatsPeriod]meetup.RollupConfig{meetup.PeriodWeek: {Domain: "notes..weekly"}}}.Validate()).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,93 @@
+package meetup_test
+
+import (
+	"os"
+	"time"
+
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stats", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.Local)
+
+	standup := meetup.Meeting{Name: "standup", Domain: "work", Date: "2024-03-11"}
+	retro := meetup.Meeting{Name: "retro", Domain: "work", Date: "2024-02-26"}
+	groceries := meetup.Meeting{Name: "groceries", Domain: "home", Date: "2024-03-13"}
+
+	contents := map[meetup.Meeting]string{
+		standup:   "- [ ] fix bug #urgent\n- [x] review\n",
+		retro:     "- [ ] plan\n- [x] celebrate\n- [x] retire the old board\n",
+		groceries: "- [ ] milk #urgent\n",
+	}
+
+	BeforeAll(func() {
+		meetupDir, manager = newTestManager(contents)
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("counts meetings and tasks by domain and week", func() {
+		stats, err := manager.Stats(meetup.TaskQuery{}, meetup.StatsOptions{Oldest: 2, Now: now})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(stats.Total).To(Equal(meetup.StatsRow{Meetings: 3, OpenTasks: 3, ClosedTasks: 3, AverageAge: 13}))
+		Expect(stats.Domains).To(Equal([]meetup.StatsRow{
+			{Domain: "home", Meetings: 1, OpenTasks: 1, AverageAge: 7},
+			{Domain: "work", Meetings: 2, OpenTasks: 2, ClosedTasks: 3, AverageAge: 16},
+		}))
+		Expect(stats.Periods).To(Equal([]meetup.StatsRow{
+			{Period: "2024-02-26", Meetings: 1, OpenTasks: 1, ClosedTasks: 2, AverageAge: 23},
+			{Period: "2024-03-04"},
+			{Period: "2024-03-11", Meetings: 2, OpenTasks: 2, ClosedTasks: 1, AverageAge: 8},
+		}))
+		Expect(stats.Rows).To(Equal([]meetup.StatsRow{
+			{Domain: "home", Period: "2024-03-11", Meetings: 1, OpenTasks: 1, AverageAge: 7},
+			{Domain: "work", Period: "2024-02-26", Meetings: 1, OpenTasks: 1, ClosedTasks: 2, AverageAge: 23},
+			{Domain: "work", Period: "2024-03-11", Meetings: 1, OpenTasks: 1, ClosedTasks: 1, AverageAge: 9},
+		}))
+		Expect(stats.Oldest).To(Equal([]meetup.Task{
+			{Meeting: retro, State: meetup.TaskTodo, Description: "plan", Line: 1, FirstSeen: "2024-02-26", Age: meetup.Duration(23 * 24 * time.Hour)},
+			{Meeting: standup, State: meetup.TaskTodo, Description: "fix bug #urgent", Line: 1, FirstSeen: "2024-03-11", Age: meetup.Duration(9 * 24 * time.Hour)},
+		}))
+	})
+
+	It("groups by month", func() {
+		stats, err := manager.Stats(meetup.TaskQuery{}, meetup.StatsOptions{Period: meetup.PeriodMonth, Now: now})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stats.Periods).To(Equal([]meetup.StatsRow{
+			{Period: "2024-02", Meetings: 1, OpenTasks: 1, ClosedTasks: 2, AverageAge: 23},
+			{Period: "2024-03", Meetings: 2, OpenTasks: 2, ClosedTasks: 1, AverageAge: 8},
+		}))
+		Expect(stats.Oldest).To(BeEmpty())
+	})
+
+	It("only counts matching tasks", func() {
+		query, err := meetup.ParseTaskQuery("tag:urgent")
+		Expect(err).ToNot(HaveOccurred())
+
+		stats, err := manager.Stats(meetup.TaskQuery{Query: query}, meetup.StatsOptions{Now: now})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stats.Total).To(Equal(meetup.StatsRow{Meetings: 3, OpenTasks: 2, AverageAge: 8}))
+	})
+
+	It("rejects unknown periods", func() {
+		_, err := manager.Stats(meetup.TaskQuery{}, meetup.StatsOptions{Period: "decade", Now: now})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reports nothing without meetings", func() {
+		empty, err := meetup.NewManager(meetup.Config{RootDir: GinkgoT().TempDir(), Editor: []string{"touch"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		stats, err := empty.Stats(meetup.TaskQuery{}, meetup.StatsOptions{Now: now})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stats).To(Equal(meetup.Stats{Domains: []meetup.StatsRow{}, Periods: []meetup.StatsRow{}, Rows: []meetup.StatsRow{}, Oldest: []meetup.Task{}}))
+	})
+})
@@ -1,7 +1,11 @@
 package meetup_test
 
 import (
+	"context"
+	"fmt"
+	"os"
 	"path"
+	"strings"
 
 	"github.com/gobwas/glob"
 	meetup "github.com/joshmeranda/meetup/pkg"
@@ -47,8 +51,10 @@ var _ = Describe("Task", Ordered, func() {
 					Date:   "2021-01-01",
 					Domain: "triple",
 				},
-				Complete:    false,
+				State:       meetup.TaskTodo,
 				Description: "do something for triple-sample",
+				Line:        5,
+				Section:     "Tasks",
 			},
 			{
 				Meeting: meetup.Meeting{
@@ -56,8 +62,10 @@ var _ = Describe("Task", Ordered, func() {
 					Date:   "2021-01-01",
 					Domain: "triple",
 				},
-				Complete:    true,
+				State:       meetup.TaskDone,
 				Description: "make schedule for triple-sample",
+				Line:        6,
+				Section:     "Tasks",
 			},
 
 			{
@@ -66,8 +74,10 @@ var _ = Describe("Task", Ordered, func() {
 					Date:   "2021-01-01",
 					Domain: "single",
 				},
-				Complete:    false,
+				State:       meetup.TaskTodo,
 				Description: "do something for single-sample",
+				Line:        5,
+				Section:     "Tasks",
 			},
 			{
 				Meeting: meetup.Meeting{
@@ -75,8 +85,10 @@ var _ = Describe("Task", Ordered, func() {
 					Date:   "2021-01-01",
 					Domain: "single",
 				},
-				Complete:    true,
+				State:       meetup.TaskDone,
 				Description: "make schedule for single-sample",
+				Line:        6,
+				Section:     "Tasks",
 			},
 
 			{
@@ -85,8 +97,10 @@ var _ = Describe("Task", Ordered, func() {
 					Date:   "2021-01-01",
 					Domain: "single.double",
 				},
-				Complete:    false,
+				State:       meetup.TaskTodo,
 				Description: "do something for single.double-sample",
+				Line:        5,
+				Section:     "Tasks",
 			},
 			{
 				Meeting: meetup.Meeting{
@@ -94,8 +108,10 @@ var _ = Describe("Task", Ordered, func() {
 					Date:   "2021-01-01",
 					Domain: "single.double",
 				},
-				Complete:    true,
+				State:       meetup.TaskDone,
 				Description: "make schedule for single.double-sample",
+				Line:        6,
+				Section:     "Tasks",
 			},
 		}
 
@@ -122,8 +138,10 @@ var _ = Describe("Task", Ordered, func() {
 						Date:   "2021-01-01",
 						Domain: "triple",
 					},
-					Complete:    true,
+					State:       meetup.TaskDone,
 					Description: "make schedule for triple-sample",
+					Line:        6,
+					Section:     "Tasks",
 				},
 				{
 					Meeting: meetup.Meeting{
@@ -131,8 +149,10 @@ var _ = Describe("Task", Ordered, func() {
 						Date:   "2021-01-01",
 						Domain: "single",
 					},
-					Complete:    true,
+					State:       meetup.TaskDone,
 					Description: "make schedule for single-sample",
+					Line:        6,
+					Section:     "Tasks",
 				},
 				{
 					Meeting: meetup.Meeting{
@@ -140,8 +160,10 @@ var _ = Describe("Task", Ordered, func() {
 						Date:   "2021-01-01",
 						Domain: "single.double",
 					},
-					Complete:    true,
+					State:       meetup.TaskDone,
 					Description: "make schedule for single.double-sample",
+					Line:        6,
+					Section:     "Tasks",
 				},
 			}
 
@@ -167,8 +189,10 @@ var _ = Describe("Task", Ordered, func() {
 						Date:   "2021-01-01",
 						Domain: "triple",
 					},
-					Complete:    false,
+					State:       meetup.TaskTodo,
 					Description: "do something for triple-sample",
+					Line:        5,
+					Section:     "Tasks",
 				},
 				{
 					Meeting: meetup.Meeting{
@@ -176,8 +200,10 @@ var _ = Describe("Task", Ordered, func() {
 						Date:   "2021-01-01",
 						Domain: "single",
 					},
-					Complete:    false,
+					State:       meetup.TaskTodo,
 					Description: "do something for single-sample",
+					Line:        5,
+					Section:     "Tasks",
 				},
 				{
 					Meeting: meetup.Meeting{
@@ -185,8 +211,10 @@ var _ = Describe("Task", Ordered, func() {
 						Date:   "2021-01-01",
 						Domain: "single.double",
 					},
-					Complete:    false,
+					State:       meetup.TaskTodo,
 					Description: "do something for single.double-sample",
+					Line:        5,
+					Section:     "Tasks",
 				},
 			}
 
@@ -212,8 +240,10 @@ var _ = Describe("Task", Ordered, func() {
 						Date:   "2021-01-01",
 						Domain: "triple",
 					},
-					Complete:    false,
+					State:       meetup.TaskTodo,
 					Description: "do something for triple-sample",
+					Line:        5,
+					Section:     "Tasks",
 				},
 				{
 					Meeting: meetup.Meeting{
@@ -221,8 +251,10 @@ var _ = Describe("Task", Ordered, func() {
 						Date:   "2021-01-01",
 						Domain: "single",
 					},
-					Complete:    false,
+					State:       meetup.TaskTodo,
 					Description: "do something for single-sample",
+					Line:        5,
+					Section:     "Tasks",
 				},
 				{
 					Meeting: meetup.Meeting{
@@ -230,8 +262,10 @@ var _ = Describe("Task", Ordered, func() {
 						Date:   "2021-01-01",
 						Domain: "single.double",
 					},
-					Complete:    false,
+					State:       meetup.TaskTodo,
 					Description: "do something for single.double-sample",
+					Line:        5,
+					Section:     "Tasks",
 				},
 			}
 
@@ -239,3 +273,200 @@ var _ = Describe("Task", Ordered, func() {
 		})
 	})
 })
+
+var _ = Describe("TaskScanning", Ordered, func() {
+	var meetupDir string
+	var err error
+
+	var meetings []meetup.Meeting
+
+	newManager := func(parallelism int) meetup.Manager {
+		manager, err := meetup.NewManager(meetup.Config{
+			RootDir:     meetupDir,
+			Editor:      []string{"touch"},
+			Parallelism: parallelism,
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		return manager
+	}
+
+	write := func(meeting meetup.Meeting, data string) {
+		Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte(data), 0644)).To(Succeed())
+	}
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager := newManager(0)
+
+		for i := 0; i < 20; i++ {
+			meeting := meetup.Meeting{Name: fmt.Sprintf("meeting-%02d", i), Domain: "work", Date: "2021-01-01"}
+			meetings = append(meetings, meeting)
+
+			Expect(manager.OpenMeeting(meeting)).To(Succeed())
+			write(meeting, fmt.Sprintf("- [ ] first %d\n- [x] second %d\n", i, i))
+		}
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("rejects negative parallelism", func() {
+		_, err := meetup.NewManager(meetup.Config{RootDir: meetupDir, Editor: []string{"touch"}, Parallelism: -1})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns tasks in the same order regardless of parallelism", func() {
+		expected := []meetup.Task{}
+		for i, meeting := range meetings {
+			expected = append(expected,
+				meetup.Task{Meeting: meeting, State: meetup.TaskTodo, Description: fmt.Sprintf("first %d", i), Line: 1},
+				meetup.Task{Meeting: meeting, State: meetup.TaskDone, Description: fmt.Sprintf("second %d", i), Line: 2},
+			)
+		}
+
+		for _, parallelism := range []int{1, 3, 50} {
+			manager := newManager(parallelism)
+
+			tasks, err := manager.Tasks(meetup.TaskQuery{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tasks).To(Equal(expected))
+		}
+	})
+
+	It("stops when the context is cancelled", func() {
+		manager := newManager(2)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := manager.TasksContext(ctx, meetup.TaskQuery{})
+		Expect(err).To(MatchError(context.Canceled))
+	})
+
+	It("reads meetings with long lines", func() {
+		manager := newManager(4)
+
+		long := strings.Repeat("x", 128*1024)
+		write(meetings[3], "- [ ] "+long+"\n")
+
+		tasks, err := manager.Tasks(meetup.TaskQuery{Meeting: meetup.MeetingQuery{Name: glob.MustCompile(meetings[3].Name)}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(ConsistOf(meetup.Task{Meeting: meetings[3], State: meetup.TaskTodo, Description: long, Line: 1}))
+	})
+
+	It("returns the errors from every meeting which could not be read", func() {
+		manager := newManager(4)
+
+		// a meeting linked to a directory can be opened but not read
+		for _, meeting := range []meetup.Meeting{meetings[3], meetings[11]} {
+			meetingPath := meeting.GetPath(meetupDir, meetup.GroupByDomain)
+			Expect(os.Remove(meetingPath)).To(Succeed())
+			Expect(os.Symlink(meetupDir, meetingPath)).To(Succeed())
+		}
+
+		_, err := manager.Tasks(meetup.TaskQuery{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(meetings[3].String()))
+		Expect(err.Error()).To(ContainSubstring(meetings[11].String()))
+		Expect(strings.Index(err.Error(), meetings[3].String())).To(BeNumerically("<", strings.Index(err.Error(), meetings[11].String())))
+	})
+})
+
+var _ = Describe("TaskParsing", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+	var err error
+
+	meeting := meetup.Meeting{Name: "standup", Domain: "work", Date: "2021-01-01"}
+
+	contents := strings.Join([]string{
+		"# Standup",
+		"",
+		"## Action Items",
+		"",
+		"- [ ] release #urgent",
+		"  - [X] tag the release",
+		"  - [ ] write the",
+		"    changelog",
+		"    1. [x] numbered subtask",
+		"* [ ] star item",
+		"+ [ ] plus item",
+		"",
+		"```",
+		"- [ ] in a code block",
+		"```",
+		"",
+		"## Notes ##",
+		"- plain item",
+		"\t- [ ] under a plain item",
+		"- [ ]",
+		"not a [ ] task",
+	}, "\n")
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(manager.OpenMeeting(meeting)).To(Succeed())
+		Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte(contents), 0644)).To(Succeed())
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	release := meetup.Task{Meeting: meeting, State: meetup.TaskTodo, Description: "release #urgent", Line: 5, Section: "Action Items"}
+	tag := meetup.Task{Meeting: meeting, State: meetup.TaskDone, Description: "tag the release", Line: 6, Section: "Action Items", Depth: 1, ParentLine: 5}
+	changelog := meetup.Task{Meeting: meeting, State: meetup.TaskTodo, Description: "write the changelog", Line: 7, Section: "Action Items", Depth: 1, ParentLine: 5}
+	numbered := meetup.Task{Meeting: meeting, State: meetup.TaskDone, Description: "numbered subtask", Line: 9, Section: "Action Items", Depth: 2, ParentLine: 7}
+	star := meetup.Task{Meeting: meeting, State: meetup.TaskTodo, Description: "star item", Line: 10, Section: "Action Items"}
+	plus := meetup.Task{Meeting: meeting, State: meetup.TaskTodo, Description: "plus item", Line: 11, Section: "Action Items"}
+	underPlain := meetup.Task{Meeting: meeting, State: meetup.TaskTodo, Description: "under a plain item", Line: 19, Section: "Notes"}
+	empty := meetup.Task{Meeting: meeting, State: meetup.TaskTodo, Line: 20, Section: "Notes"}
+
+	It("parses nested tasks with their lines and sections", func() {
+		tasks, err := manager.Tasks(meetup.TaskQuery{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(Equal([]meetup.Task{release, tag, changelog, numbered, star, plus, underPlain, empty}))
+	})
+
+	It("filters tasks by section", func() {
+		query, err := meetup.ParseTaskQuery("section:Notes")
+		Expect(err).ToNot(HaveOccurred())
+
+		tasks, err := manager.Tasks(meetup.TaskQuery{Query: query})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(Equal([]meetup.Task{underPlain, empty}))
+	})
+
+	It("builds a task tree", func() {
+		tree := meetup.TaskTree([]meetup.Task{release, tag, changelog, numbered, star})
+
+		Expect(tree).To(Equal([]*meetup.TaskNode{
+			{Task: release, Children: []*meetup.TaskNode{
+				{Task: tag},
+				{Task: changelog, Children: []*meetup.TaskNode{{Task: numbered}}},
+			}},
+			{Task: star},
+		}))
+	})
+
+	It("keeps subtasks whose parent was filtered out", func() {
+		Expect(meetup.TaskTree([]meetup.Task{numbered, star})).To(Equal([]*meetup.TaskNode{{Task: numbered}, {Task: star}}))
+	})
+})
@@ -0,0 +1,98 @@
+package meetup_test
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TaskAges", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+
+	day := meetup.Duration(24 * time.Hour)
+	daysAgo := func(days int) string {
+		return time.Now().AddDate(0, 0, -days).Format(meetup.DateFormat)
+	}
+
+	first := meetup.Meeting{Name: "standup", Domain: "work", Date: daysAgo(21)}
+	second := meetup.Meeting{Name: "standup", Domain: "work", Date: daysAgo(7)}
+	latest := meetup.Meeting{Name: "standup", Domain: "work", Date: daysAgo(0)}
+	other := meetup.Meeting{Name: "chores", Domain: "home", Date: daysAgo(30)}
+	opened := meetup.Meeting{Name: "oncall", Domain: "ops", Date: daysAgo(14)}
+	closed := meetup.Meeting{Name: "oncall", Domain: "ops", Date: daysAgo(10)}
+	reopened := meetup.Meeting{Name: "oncall", Domain: "ops", Date: daysAgo(3)}
+
+	contents := map[meetup.Meeting]string{
+		first:    "- [ ] fix flaky test\n- [ ] write docs\n",
+		second:   "- [ ] Fix the flaky test!\n- [x] write docs\n",
+		latest:   "- [ ] fix  FLAKY test #urgent\n- [ ] fix the flaky test\n- [ ] review the pr\n",
+		other:    "- [ ] fix flaky test\n",
+		opened:   "- [ ] rotate keys\n",
+		closed:   "- [x] rotate keys\n",
+		reopened: "- [ ] rotate keys\n",
+	}
+
+	BeforeAll(func() {
+		meetupDir, manager = newTestManager(contents)
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	latestOnly := meetup.MeetingQuery{Since: time.Now().AddDate(0, 0, -1)}
+
+	It("only tracks ages when asked", func() {
+		tasks, err := manager.Tasks(meetup.TaskQuery{Meeting: latestOnly})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(HaveLen(3))
+		Expect(tasks[0].FirstSeen).To(BeEmpty())
+	})
+
+	It("tracks ages across meetings in the same domain", func() {
+		tasks, err := manager.Tasks(meetup.TaskQuery{Meeting: latestOnly, TrackAge: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(Equal([]meetup.Task{
+			{Meeting: latest, State: meetup.TaskTodo, Description: "fix  FLAKY test #urgent", Line: 1, FirstSeen: first.Date, Age: 21 * day},
+			{Meeting: latest, State: meetup.TaskTodo, Description: "fix the flaky test", Line: 2, FirstSeen: second.Date, Age: 7 * day},
+			{Meeting: latest, State: meetup.TaskTodo, Description: "review the pr", Line: 3, FirstSeen: latest.Date},
+		}))
+	})
+
+	It("tracks recurring tasks from when they were reopened", func() {
+		tasks, err := manager.Tasks(meetup.TaskQuery{Meeting: meetup.MeetingQuery{Since: time.Now().AddDate(0, 0, -4)}, TrackAge: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(ContainElement(meetup.Task{Meeting: reopened, State: meetup.TaskTodo, Description: "rotate keys", Line: 1, FirstSeen: reopened.Date, Age: 3 * day}))
+	})
+
+	It("finds stale tasks", func() {
+		tasks, err := manager.Tasks(meetup.TaskQuery{Meeting: latestOnly, Stale: 14 * day})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(HaveLen(1))
+		Expect(tasks[0].Description).To(Equal("fix  FLAKY test #urgent"))
+
+		tasks, err = manager.Tasks(meetup.TaskQuery{Stale: 20 * day})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(HaveLen(4))
+
+		for _, task := range tasks {
+			Expect(task.State.Done).To(BeFalse())
+			Expect(task.Age).To(BeNumerically(">", 20*day))
+		}
+	})
+
+	It("encodes ages as durations", func() {
+		data, err := json.Marshal(meetup.Task{FirstSeen: first.Date, Age: 21 * day})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`"age":"3w"`))
+
+		var task meetup.Task
+		Expect(json.Unmarshal(data, &task)).To(Succeed())
+		Expect(task.Age).To(Equal(21 * day))
+	})
+})
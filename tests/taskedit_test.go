@@ -0,0 +1,105 @@
+package meetup_test
+
+import (
+	"os"
+
+	"github.com/gobwas/glob"
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TaskEdit", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+	var err error
+
+	meeting := meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-05"}
+
+	read := func() string {
+		data, err := os.ReadFile(meeting.GetPath(meetupDir, meetup.GroupByDomain))
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+				TaskStates: []meetup.TaskState{
+					{Name: "cancelled", Marker: "-"},
+					{Name: "later", Prefix: "LATER "},
+				},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(manager.OpenMeeting(meeting)).To(Succeed())
+		Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte("# Standup\r\n\r\n  * [ ] write docs\r\n- [ ] review the pr"), 0644)).To(Succeed())
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("changes checkbox markers in place", func() {
+		tasks, err := manager.Tasks(meetup.TaskQuery{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(HaveLen(2))
+
+		task, err := manager.SetTaskState(tasks[0], "done")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(task.State).To(Equal(meetup.TaskDone))
+		Expect(task.ID()).To(Equal(tasks[0].ID()))
+		Expect(read()).To(Equal("# Standup\r\n\r\n  * [x] write docs\r\n- [ ] review the pr"))
+	})
+
+	It("switches between markers and prefixes", func() {
+		tasks, err := manager.Tasks(meetup.TaskQuery{Description: glob.MustCompile("review*")})
+		Expect(err).ToNot(HaveOccurred())
+
+		task, err := manager.SetTaskState(tasks[0], "later")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(read()).To(Equal("# Standup\r\n\r\n  * [x] write docs\r\nLATER review the pr"))
+
+		_, err = manager.SetTaskState(task, "cancelled")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(read()).To(Equal("# Standup\r\n\r\n  * [x] write docs\r\n- [-] review the pr"))
+	})
+
+	It("finds tasks which have moved", func() {
+		tasks, err := manager.Tasks(meetup.TaskQuery{Description: glob.MustCompile("write*")})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte("- [ ] new task\n"+read()), 0644)).To(Succeed())
+
+		task, err := manager.SetTaskState(tasks[0], "todo")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(task.Line).To(Equal(tasks[0].Line + 1))
+		Expect(read()).To(ContainSubstring("  * [ ] write docs\r\n"))
+	})
+
+	It("rejects unknown states", func() {
+		tasks, err := manager.Tasks(meetup.TaskQuery{})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = manager.SetTaskState(tasks[0], "blocked")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("adds tasks", func() {
+		task, err := manager.AddTask(meeting, "deploy", "todo")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(task.Description).To(Equal("deploy"))
+		Expect(task.State).To(Equal(meetup.TaskTodo))
+		Expect(read()).To(HaveSuffix("- [-] review the pr\n- [ ] deploy\n"))
+
+		_, err = manager.AddTask(meetup.Meeting{Name: "missing", Domain: "work", Date: "2026-10-05"}, "deploy", "todo")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,147 @@
+package meetup_test
+
+import (
+	"os"
+	"strings"
+
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TaskStates", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+	var err error
+
+	cancelled := meetup.TaskState{Name: "cancelled", Marker: "-", Symbol: "🚫", Done: true}
+	deferred := meetup.TaskState{Name: "deferred", Marker: ">", Symbol: "⏩"}
+	question := meetup.TaskState{Name: "question", Marker: "?"}
+	blocked := meetup.TaskState{Name: "blocked", Prefix: "BLOCKED "}
+
+	standup := meetup.Meeting{Name: "standup", Domain: "work", Date: "2021-01-01"}
+	oneOnOne := meetup.Meeting{Name: "one-on-one", Domain: "work.manager", Date: "2021-01-01"}
+
+	contents := strings.Join([]string{
+		"- [ ] write tests",
+		"- [x] write code",
+		"- [-] rewrite everything",
+		"- [>] update the docs",
+		"- [?] who owns the release",
+		"- [!] unknown marker",
+		"BLOCKED waiting on review",
+	}, "\n")
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy:    meetup.GroupByDomain,
+				TaskStates: []meetup.TaskState{cancelled, deferred, question},
+				Domains: map[string]meetup.DomainConfig{
+					"work.manager": {
+						TaskStates: []meetup.TaskState{blocked, {Name: "done", Marker: "x", Symbol: "🎉", Done: true}},
+					},
+				},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, meeting := range []meetup.Meeting{standup, oneOnOne} {
+			Expect(manager.OpenMeeting(meeting)).To(Succeed())
+			Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte(contents), 0644)).To(Succeed())
+		}
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("validates task states", func() {
+		Expect(meetup.TaskState{Name: "todo", Marker: " "}.Validate()).To(Succeed())
+		Expect(meetup.TaskState{Name: "todo", Prefix: "TODO "}.Validate()).To(Succeed())
+		Expect(meetup.TaskState{Marker: " "}.Validate()).ToNot(Succeed())
+		Expect(meetup.TaskState{Name: "todo"}.Validate()).ToNot(Succeed())
+		Expect(meetup.TaskState{Name: "todo", Marker: " ", Prefix: "TODO "}.Validate()).ToNot(Succeed())
+		Expect(meetup.TaskState{Name: "todo", Marker: "ab"}.Validate()).ToNot(Succeed())
+		Expect(meetup.TaskState{Name: "todo", Marker: "]"}.Validate()).ToNot(Succeed())
+	})
+
+	It("rejects conflicting task states", func() {
+		for _, states := range [][]meetup.TaskState{
+			{{Name: "cancelled", Marker: "X"}},
+			{cancelled, cancelled},
+			{blocked, {Name: "stuck", Prefix: "BLOCKED "}},
+			{{Name: "", Marker: "-"}},
+		} {
+			Expect(meetup.Metadata{TaskStates: states}.Validate()).ToNot(Succeed())
+		}
+
+		Expect(meetup.Metadata{TaskStates: []meetup.TaskState{{Name: "done", Marker: "X"}}}.Validate()).To(Succeed())
+	})
+
+	It("merges task states by name", func() {
+		Expect(manager.TaskStates("work")).To(Equal([]meetup.TaskState{meetup.TaskTodo, meetup.TaskDone, cancelled, deferred, question}))
+		Expect(manager.TaskStates("work.manager.team")).To(Equal([]meetup.TaskState{
+			meetup.TaskTodo, {Name: "done", Marker: "x", Symbol: "🎉", Done: true}, cancelled, deferred, question, blocked,
+		}))
+	})
+
+	It("parses tasks in each state", func() {
+		tasks, err := manager.Tasks(meetup.TaskQuery{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(ConsistOf(
+			meetup.Task{Meeting: standup, State: meetup.TaskTodo, Description: "write tests", Line: 1},
+			meetup.Task{Meeting: standup, State: meetup.TaskDone, Description: "write code", Line: 2},
+			meetup.Task{Meeting: standup, State: cancelled, Description: "rewrite everything", Line: 3},
+			meetup.Task{Meeting: standup, State: deferred, Description: "update the docs", Line: 4},
+			meetup.Task{Meeting: standup, State: question, Description: "who owns the release", Line: 5},
+			meetup.Task{Meeting: oneOnOne, State: meetup.TaskTodo, Description: "write tests", Line: 1},
+			meetup.Task{Meeting: oneOnOne, State: meetup.TaskState{Name: "done", Marker: "x", Symbol: "🎉", Done: true}, Description: "write code", Line: 2},
+			meetup.Task{Meeting: oneOnOne, State: cancelled, Description: "rewrite everything", Line: 3},
+			meetup.Task{Meeting: oneOnOne, State: deferred, Description: "update the docs", Line: 4},
+			meetup.Task{Meeting: oneOnOne, State: question, Description: "who owns the release", Line: 5},
+			meetup.Task{Meeting: oneOnOne, State: blocked, Description: "waiting on review", Line: 7},
+		))
+	})
+
+	It("filters tasks by state", func() {
+		tasks, err := manager.Tasks(meetup.TaskQuery{States: []string{"deferred", "blocked"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(HaveLen(3))
+
+		complete := true
+		tasks, err = manager.Tasks(meetup.TaskQuery{Complete: &complete})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(HaveLen(4))
+
+		query, err := meetup.ParseTaskQuery("state:question AND name:standup")
+		Expect(err).ToNot(HaveOccurred())
+
+		tasks, err = manager.Tasks(meetup.TaskQuery{Query: query})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks).To(Equal([]meetup.Task{{Meeting: standup, State: question, Description: "who owns the release", Line: 5}}))
+
+		_, err = meetup.ParseMeetingQuery("state:question")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("knows the states configured in any domain", func() {
+		for _, name := range []string{"todo", "done", "cancelled", "blocked"} {
+			Expect(manager.HasTaskState(name)).To(BeTrue(), name)
+		}
+
+		Expect(manager.HasTaskState("canceled")).To(BeFalse())
+	})
+
+	It("shows each state with its symbol", func() {
+		Expect(meetup.TaskTodo.String()).To(Equal("❌"))
+		Expect(cancelled.String()).To(Equal("🚫"))
+		Expect(question.String()).To(Equal("[?]"))
+		Expect(blocked.String()).To(Equal("BLOCKED"))
+	})
+})
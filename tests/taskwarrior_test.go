@@ -0,0 +1,143 @@
+package meetup_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Taskwarrior", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+	var err error
+
+	meeting := meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-05"}
+
+	read := func() string {
+		data, err := os.ReadFile(meeting.GetPath(meetupDir, meetup.GroupByDomain))
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	exported := func() []meetup.TaskwarriorTask {
+		tasks, err := manager.Tasks(meetup.TaskQuery{})
+		Expect(err).ToNot(HaveOccurred())
+
+		var buffer bytes.Buffer
+		Expect(meetup.WriteTaskwarrior(&buffer, tasks)).To(Succeed())
+
+		tw, err := meetup.ReadTaskwarrior(&buffer)
+		Expect(err).ToNot(HaveOccurred())
+
+		return tw
+	}
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+				TaskStates: []meetup.TaskState{
+					{Name: "cancelled", Marker: "-", Done: true},
+				},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(manager.OpenMeeting(meeting)).To(Succeed())
+		Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte("- [ ] write docs #docs\n- [x] ship release\n- [ ] review the pr\n"), 0644)).To(Succeed())
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("exports tasks", func() {
+		tw := exported()
+		Expect(tw).To(HaveLen(3))
+
+		Expect(tw[0].UUID).To(MatchRegexp(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`))
+		Expect(tw[0].Description).To(Equal("write docs"))
+		Expect(tw[0].Status).To(Equal(meetup.TaskwarriorPending))
+		Expect(tw[0].Project).To(Equal("work"))
+		Expect(tw[0].Tags).To(Equal([]string{"docs"}))
+		Expect(tw[0].End).To(BeEmpty())
+
+		Expect(tw[1].Status).To(Equal(meetup.TaskwarriorCompleted))
+		Expect(tw[1].End).ToNot(BeEmpty())
+	})
+
+	It("reads line delimited tasks", func() {
+		tw, err := meetup.ReadTaskwarrior(strings.NewReader(`{"description":"a","status":"pending"}` + "\n" + `{"description":"b","status":"completed"}` + "\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tw).To(Equal([]meetup.TaskwarriorTask{
+			{Description: "a", Status: meetup.TaskwarriorPending},
+			{Description: "b", Status: meetup.TaskwarriorCompleted},
+		}))
+	})
+
+	It("updates exported tasks", func() {
+		tw := exported()
+		tw[0].Status = meetup.TaskwarriorCompleted
+		tw[1].Status = meetup.TaskwarriorPending
+		tw[2].Status = meetup.TaskwarriorDeleted
+
+		result, err := manager.ImportTaskwarrior(tw, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Updated).To(HaveLen(3))
+		Expect(result.Added).To(BeEmpty())
+		Expect(read()).To(Equal("- [x] write docs #docs\n- [ ] ship release\n- [-] review the pr\n"))
+
+		result, err = manager.ImportTaskwarrior(tw, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Updated).To(BeEmpty())
+		Expect(result.Skipped).To(HaveLen(3))
+	})
+
+	It("adds new tasks to a meeting", func() {
+		tw := []meetup.TaskwarriorTask{
+			{UUID: "11111111-2222-4333-8444-555555555555", Description: "deploy", Status: meetup.TaskwarriorPending, Tags: []string{"ops"}},
+			{Description: "gone", Status: meetup.TaskwarriorDeleted},
+		}
+
+		result, err := manager.ImportTaskwarrior(tw, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Added).To(BeEmpty())
+		Expect(result.Skipped).To(HaveLen(2))
+
+		result, err = manager.ImportTaskwarrior(tw, &meeting)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Added).To(HaveLen(1))
+		Expect(result.Added[0].Description).To(Equal("deploy #ops"))
+		Expect(read()).To(HaveSuffix("- [ ] deploy #ops\n"))
+
+		result, err = manager.ImportTaskwarrior(tw, &meeting)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Added).To(BeEmpty())
+		Expect(strings.Count(read(), "deploy")).To(Equal(1))
+	})
+
+	It("updates tasks exported before their meeting was moved", func() {
+		tw := exported()
+		tw[0].Status = meetup.TaskwarriorPending
+
+		moved := meetup.Meeting{Name: "standup", Domain: "work.team", Date: "2026-10-05"}
+		Expect(manager.MoveMeeting(meeting, moved)).To(Succeed())
+		meeting = moved
+
+		result, err := manager.ImportTaskwarrior(tw, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Added).To(BeEmpty())
+		Expect(result.Updated).To(HaveLen(1))
+		Expect(result.Updated[0].Meeting).To(Equal(moved))
+		Expect(read()).To(HavePrefix("- [ ] write docs #docs\n"))
+	})
+})
@@ -67,3 +67,99 @@ var _ = Describe("ManageTemplates", Ordered, func() {
 		Expect(path.Join(meetupDir, meetup.TemplateDirName, "simple.md")).ShouldNot(BeAnExistingFile())
 	})
 })
+
+var _ = Describe("TemplateRules", Ordered, func() {
+	var manager meetup.Manager
+	var meetupDir string
+	var err error
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+				TemplateRules: []meetup.TemplateRule{
+					{Domain: "work.*", Template: "simple.md"},
+					{Domain: "work.product.*", Template: "product.md"},
+					{Domain: "work.*", Weekday: "mon", Template: "weekly.md"},
+					{Name: "standup", Template: "missing.md"},
+				},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(manager.AddTemplate(path.Join(exampleDir, "templates", "simple.md"))).ToNot(HaveOccurred())
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("rejects invalid rules", func() {
+		_, err := meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				TemplateRules: []meetup.TemplateRule{{Domain: "work.*"}},
+			},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reports invalid patterns instead of panicking", func() {
+		_, err := meetup.TemplateRule{Domain: "work.[", Template: "work.md"}.Match(meetup.Meeting{Name: "sync", Domain: "work.team", Date: "2021-01-01"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("prefers the most specific rule", func() {
+		template, err := manager.ResolveTemplate(meetup.Meeting{Name: "sync", Domain: "work.product.team", Date: "2021-01-01"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(template).To(Equal("product.md"))
+
+		template, err = manager.ResolveTemplate(meetup.Meeting{Name: "sync", Domain: "work.team", Date: "2021-01-01"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(template).To(Equal("simple.md"))
+	})
+
+	It("matches weekdays", func() {
+		template, err := manager.ResolveTemplate(meetup.Meeting{Name: "sync", Domain: "work.team", Date: "2021-01-04"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(template).To(Equal("weekly.md"))
+	})
+
+	It("prefers an explicit template", func() {
+		template, err := manager.ResolveTemplate(meetup.Meeting{Name: "sync", Domain: "work.team", Date: "2021-01-01", Template: "other.md"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(template).To(Equal("other.md"))
+	})
+
+	It("lists matching rules by precedence", func() {
+		matches, err := manager.MatchingTemplateRules(meetup.Meeting{Name: "sync", Domain: "work.product.team", Date: "2021-01-04"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(matches).To(Equal([]int{2, 1, 0}))
+	})
+
+	It("applies the rule template when opening a meeting", func() {
+		meeting := meetup.Meeting{Name: "sync", Domain: "work.team", Date: "2021-01-01"}
+		Expect(manager.OpenMeeting(meeting)).ToNot(HaveOccurred())
+
+		data, err := os.ReadFile(meeting.GetPath(meetupDir, meetup.GroupByDomain))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("2021-01-01 work.team Sync"))
+	})
+
+	It("ignores rules referencing missing templates", func() {
+		meeting := meetup.Meeting{Name: "standup", Domain: "personal", Date: "2021-01-01"}
+		Expect(manager.OpenMeeting(meeting)).ToNot(HaveOccurred())
+		Expect(meeting.GetPath(meetupDir, meetup.GroupByDomain)).To(BeAnExistingFile())
+	})
+
+	It("fails for explicit missing templates", func() {
+		meeting := meetup.Meeting{Name: "retro", Domain: "personal", Date: "2021-01-01", Template: "missing.md"}
+		Expect(manager.OpenMeeting(meeting)).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,222 @@
+package meetup_test
+
+import (
+	"os"
+	"path"
+	"strings"
+
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TodoTxt", func() {
+	It("parses lines", func() {
+		item := meetup.ParseTodoTxt("x (A) 2026-10-12 2026-10-05 call mom +family @phone due:2026-10-20")
+		Expect(item).To(Equal(meetup.TodoTxtItem{
+			Done:        true,
+			Priority:    "A",
+			Completed:   "2026-10-12",
+			Created:     "2026-10-05",
+			Description: "call mom",
+			Projects:    []string{"family"},
+			Contexts:    []string{"phone"},
+			Tags:        []meetup.TodoTxtTag{{Key: "due", Value: "2026-10-20"}},
+		}))
+		Expect(item.String()).To(Equal("x (A) 2026-10-12 2026-10-05 call mom +family @phone due:2026-10-20"))
+
+		item = meetup.ParseTodoTxt("2026-10-05 2026-10-06 read http://example.com")
+		Expect(item.Done).To(BeFalse())
+		Expect(item.Created).To(Equal("2026-10-05"))
+		Expect(item.Description).To(Equal("2026-10-06 read http://example.com"))
+		Expect(item.Tags).To(BeEmpty())
+	})
+
+	It("converts tasks", func() {
+		task := meetup.Task{
+			Meeting:     meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-12"},
+			State:       meetup.TaskDone,
+			Description: "fix the #flaky test #urgent",
+		}
+
+		Expect(meetup.NewTodoTxtItem(task).String()).To(Equal("x 2026-10-12 2026-10-12 fix the test +work @flaky @urgent meetup:" + task.ID()))
+
+		task.State = meetup.TaskTodo
+		task.FirstSeen = "2026-10-05"
+		Expect(meetup.NewTodoTxtItem(task).String()).To(Equal("2026-10-05 fix the test +work @flaky @urgent meetup:" + task.ID()))
+
+		task.Description += " due:friday"
+		task.Due = "2026-10-16"
+		Expect(meetup.NewTodoTxtItem(task).String()).To(Equal("2026-10-05 fix the test +work @flaky @urgent due:2026-10-16 meetup:" + task.ID()))
+	})
+
+	It("keeps task ids stable", func() {
+		task := meetup.Task{
+			Meeting:     meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-12"},
+			Description: "send the report due:friday #urgent",
+		}
+
+		Expect(task.ID()).To(Equal("821580a3bd3ea881"))
+		Expect(meetup.TaskwarriorUUID(task)).To(Equal("821580a3-bd3e-5881-bbba-64559211a9ab"))
+
+		id := task.ID()
+		task.Description = "send the report due:2026-10-23 #urgent"
+		Expect(task.ID()).To(Equal(id))
+	})
+
+	Describe("Sync", Ordered, func() {
+		var meetupDir string
+		var todoTxt string
+		var manager meetup.Manager
+		var err error
+
+		meeting := meetup.Meeting{Name: "standup", Domain: "work", Date: "2026-10-05"}
+
+		BeforeAll(func() {
+			meetupDir, err = os.MkdirTemp("", "meetup-test")
+			Expect(err).ToNot(HaveOccurred())
+
+			todoTxt = path.Join(meetupDir, "todo.txt")
+
+			manager, err = meetup.NewManager(meetup.Config{
+				RootDir: meetupDir,
+				Editor:  []string{"touch"},
+				DefaultMetadata: meetup.Metadata{
+					GroupBy: meetup.GroupByDomain,
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(manager.OpenMeeting(meeting)).To(Succeed())
+			Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte("- [ ] write docs\n- [ ] review the pr\n"), 0644)).To(Succeed())
+			Expect(os.WriteFile(todoTxt, []byte("(A) buy milk @home\n"), 0644)).To(Succeed())
+		})
+
+		AfterAll(func() {
+			os.RemoveAll(meetupDir)
+		})
+
+		readLines := func() []string {
+			data, err := os.ReadFile(todoTxt)
+			Expect(err).ToNot(HaveOccurred())
+			return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		}
+
+		It("exports tasks alongside existing lines", func() {
+			sync, err := manager.SyncTodoTxt(todoTxt, meetup.TaskQuery{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sync.Completed).To(BeEmpty())
+			Expect(sync.Unmatched).To(BeEmpty())
+
+			lines := readLines()
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[0]).To(Equal("(A) buy milk @home"))
+			Expect(lines[1]).To(HavePrefix("2026-10-05 write docs +work meetup:"))
+			Expect(lines[2]).To(HavePrefix("2026-10-05 review the pr +work meetup:"))
+		})
+
+		It("completes tasks marked done", func() {
+			lines := readLines()
+			lines[1] = "x 2026-10-19 " + lines[1]
+			Expect(os.WriteFile(todoTxt, []byte(strings.Join(lines, "\n")+"\n"), 0644)).To(Succeed())
+
+			sync, err := manager.SyncTodoTxt(todoTxt, meetup.TaskQuery{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sync.Completed).To(HaveLen(1))
+			Expect(sync.Completed[0].Description).To(Equal("write docs"))
+			Expect(sync.Completed[0].State).To(Equal(meetup.TaskDone))
+
+			data, err := os.ReadFile(meeting.GetPath(meetupDir, meetup.GroupByDomain))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal("- [x] write docs\n- [ ] review the pr\n"))
+
+			Expect(readLines()[1]).To(HavePrefix("x 2026-10-05 2026-10-05 write docs +work meetup:"))
+		})
+
+		It("keeps the priority and contexts added to exported lines", func() {
+			lines := readLines()
+			lines[2] = "(B) " + lines[2] + " @laptop"
+			Expect(os.WriteFile(todoTxt, []byte(strings.Join(lines, "\n")+"\n"), 0644)).To(Succeed())
+
+			_, err := manager.SyncTodoTxt(todoTxt, meetup.TaskQuery{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(readLines()[2]).To(MatchRegexp(`^\(B\) 2026-10-05 review the pr \+work @laptop meetup:\w+$`))
+		})
+
+		It("keeps exported lines whose task was edited", func() {
+			lines := readLines()
+			lines[2] = "x 2026-10-19 " + strings.TrimPrefix(lines[2], "(B) ")
+			Expect(os.WriteFile(todoTxt, []byte(strings.Join(lines, "\n")+"\n"), 0644)).To(Succeed())
+
+			Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte("- [x] write docs\n- [ ] review the pull request\n"), 0644)).To(Succeed())
+
+			sync, err := manager.SyncTodoTxt(todoTxt, meetup.TaskQuery{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sync.Completed).To(BeEmpty())
+			Expect(sync.Unmatched).To(Equal([]string{lines[2]}))
+
+			Expect(readLines()).To(ConsistOf(
+				"(A) buy milk @home",
+				lines[2],
+				HavePrefix("x 2026-10-05 2026-10-05 write docs +work meetup:"),
+				HavePrefix("2026-10-05 review the pull request +work meetup:"),
+			))
+		})
+
+		It("matches lines exported before their meeting was moved", func() {
+			moved := meetup.Meeting{Name: "standup", Domain: "work.team", Date: "2026-10-06"}
+			Expect(manager.MoveMeeting(meeting, moved)).To(Succeed())
+			meeting = moved
+
+			lines := readLines()
+			Expect(lines[3]).To(HavePrefix("2026-10-05 review the pull request +work meetup:"))
+			lines[3] = "x 2026-10-19 " + lines[3]
+			Expect(os.WriteFile(todoTxt, []byte(strings.Join(lines, "\n")+"\n"), 0644)).To(Succeed())
+
+			sync, err := manager.SyncTodoTxt(todoTxt, meetup.TaskQuery{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sync.Unmatched).To(Equal([]string{lines[1]}))
+			Expect(sync.Completed).To(HaveLen(1))
+			Expect(sync.Completed[0].Meeting).To(Equal(moved))
+
+			Expect(readLines()).To(ConsistOf(
+				"(A) buy milk @home",
+				lines[1],
+				HavePrefix("x 2026-10-06 2026-10-06 write docs +work.team meetup:"),
+				HavePrefix("x 2026-10-06 2026-10-06 review the pull request +work.team meetup:"),
+			))
+		})
+
+		It("tells apart tasks with the same description", func() {
+			Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte("- [ ] call bob\n- [ ] call bob\n"), 0644)).To(Succeed())
+			Expect(os.WriteFile(todoTxt, nil, 0644)).To(Succeed())
+
+			_, err := manager.SyncTodoTxt(todoTxt, meetup.TaskQuery{})
+			Expect(err).ToNot(HaveOccurred())
+
+			lines := readLines()
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[0]).ToNot(Equal(lines[1]))
+
+			lines[1] = "x 2026-10-19 " + lines[1]
+			Expect(os.WriteFile(todoTxt, []byte(strings.Join(lines, "\n")+"\n"), 0644)).To(Succeed())
+
+			sync, err := manager.SyncTodoTxt(todoTxt, meetup.TaskQuery{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sync.Completed).To(HaveLen(1))
+
+			data, err := os.ReadFile(meeting.GetPath(meetupDir, meetup.GroupByDomain))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal("- [ ] call bob\n- [x] call bob\n"))
+		})
+
+		It("creates missing files", func() {
+			p := path.Join(meetupDir, "missing.txt")
+
+			_, err := manager.SyncTodoTxt(p, meetup.TaskQuery{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(p).To(BeAnExistingFile())
+		})
+	})
+})
@@ -0,0 +1,160 @@
+package meetup_test
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"github.com/gobwas/glob"
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trash", Ordered, func() {
+	var manager meetup.Manager
+	var meetupDir string
+	var err error
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, meeting := range testMeetings {
+			Expect(manager.OpenMeeting(meeting)).To(Succeed())
+		}
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("moves removed meetings to the trash", func() {
+		Expect(manager.RemoveMeeting(testMeetings[0])).To(Succeed())
+		Expect(manager.RemoveMeeting(testMeetings[1])).To(Succeed())
+
+		Expect(path.Join(meetupDir, "triple")).ToNot(BeADirectory())
+
+		items, err := manager.ListTrash()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(items).To(HaveLen(2))
+
+		Expect(items[0].Meeting).To(Equal(testMeetings[0]))
+		Expect(items[0].Path).To(Equal(path.Join("triple", "2021-01-01", "sample")))
+
+		Expect(items[1].Meeting).To(Equal(testMeetings[1]))
+		Expect(items[1].ID).ToNot(Equal(items[0].ID))
+	})
+
+	It("does not list trashed meetings", func() {
+		meetings, err := manager.ListMeetings(meetup.MeetingQuery{
+			Name:   glob.MustCompile("*"),
+			Domain: glob.MustCompile("*"),
+			Date:   glob.MustCompile("*"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meetings).To(ConsistOf(testMeetings[2]))
+	})
+
+	It("can restore meetings", func() {
+		items, err := manager.ListTrash()
+		Expect(err).ToNot(HaveOccurred())
+
+		meeting, err := manager.RestoreTrash(items[0].ID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meeting).To(Equal(testMeetings[0]))
+		Expect(testMeetings[0].GetPath(meetupDir, meetup.GroupByDomain)).To(BeAnExistingFile())
+
+		_, err = manager.RestoreTrash(items[0].ID)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("will not restore over an existing meeting", func() {
+		Expect(manager.OpenMeeting(testMeetings[1])).To(Succeed())
+
+		items, err := manager.ListTrash()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = manager.RestoreTrash(items[0].ID)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("only empties old meetings", func() {
+		deleted, err := manager.EmptyTrash(meetup.Duration(time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeEmpty())
+
+		items, err := manager.ListTrash()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(items).To(HaveLen(1))
+	})
+
+	It("can empty the trash", func() {
+		deleted, err := manager.EmptyTrash(0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(HaveLen(1))
+
+		items, err := manager.ListTrash()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(items).To(BeEmpty())
+
+		entries, err := os.ReadDir(path.Join(meetupDir, meetup.TrashDirName))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("removes meetings in bulk", func() {
+		Expect(manager.OpenMeeting(testMeetings[0])).To(Succeed())
+
+		Expect(manager.RemoveMeetings([]meetup.Meeting{testMeetings[0], testMeetings[1], testMeetings[2]})).To(Succeed())
+
+		items, err := manager.ListTrash()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(items).To(HaveLen(3))
+
+		ids := map[string]bool{}
+		for _, item := range items {
+			ids[item.ID] = true
+		}
+		Expect(ids).To(HaveLen(3))
+
+		meeting, err := manager.RestoreTrash(items[0].ID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meeting.GetPath(meetupDir, meetup.GroupByDomain)).To(BeAnExistingFile())
+	})
+
+	It("keeps an explicit zero retention", func() {
+		dir, err := os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		Expect(os.WriteFile(path.Join(dir, meetup.MetadataFilename), []byte("group_by: domain\ntrash_retention: 0\n"), 0644)).To(Succeed())
+
+		config := meetup.Config{RootDir: dir, Editor: []string{"touch"}, DefaultMetadata: meetup.DefaultMetadata()}
+
+		manager, err := meetup.NewManager(config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manager.UpdateMeetingGroupBy(meetup.GroupByDate)).To(Succeed())
+
+		manager, err = meetup.NewManager(config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*manager.Metadata().TrashRetention).To(BeZero())
+		Expect(*config.DefaultMetadata.TrashRetention).To(Equal(meetup.Duration(time.Hour * 24 * 30)))
+
+		zero := meetup.Duration(0)
+		file := meetup.ConfigFile{Config: meetup.Config{DefaultMetadata: meetup.Metadata{TrashRetention: &zero}}}
+
+		resolved, err := file.ProfileConfig(meetup.DefaultProfile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*resolved.DefaultMetadata.TrashRetention).To(BeZero())
+	})
+})
@@ -93,4 +93,24 @@ var _ = Describe("UpdateMeetingGroupBy", func() {
 
 		Expect(metadata.GroupBy).To(Equal(meetup.GroupByDomain))
 	})
+
+	It("moves meeting assets with the meetings", func() {
+		manager, err := meetup.NewManager(meetup.Config{
+			RootDir:         config.RootDir,
+			Editor:          []string{"touch"},
+			DefaultMetadata: meetup.Metadata{GroupBy: meetup.GroupByDomain},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(manager.OpenMeeting(meetup.Meeting{Name: "standup", Domain: "work.team", Date: "2021-01-01"})).To(Succeed())
+
+		assets := path.Join(config.RootDir, "work", "team", "2021-01-01", "standup"+meetup.AssetsDirSuffix)
+		Expect(os.MkdirAll(assets, 0755)).To(Succeed())
+		Expect(os.WriteFile(path.Join(assets, "x.png"), []byte{}, 0644)).To(Succeed())
+
+		Expect(manager.UpdateMeetingGroupBy(meetup.GroupByDate)).To(Succeed())
+
+		Expect(path.Join(config.RootDir, "2021-01-01", "work", "team", "standup"+meetup.AssetsDirSuffix, "x.png")).To(BeAnExistingFile())
+		Expect(path.Join(config.RootDir, "work")).ToNot(BeADirectory())
+	})
 })
@@ -2,6 +2,7 @@ package meetup_test
 
 import (
 	"fmt"
+	"time"
 
 	meetup "github.com/joshmeranda/meetup/pkg"
 
@@ -78,3 +79,44 @@ var _ = Describe("MeetingFromPath", func() {
 		})
 	}
 })
+
+var _ = Describe("ParseDuration", func() {
+	type TestCase struct {
+		Input    string
+		Duration time.Duration
+		Error    bool
+	}
+
+	testCases := []TestCase{
+		{Input: "0", Duration: 0},
+		{Input: "90m", Duration: time.Minute * 90},
+		{Input: "3d", Duration: time.Hour * 24 * 3},
+		{Input: "2w3d", Duration: time.Hour * 24 * 17},
+		{Input: "1d12h", Duration: time.Hour * 36},
+		{Input: "+1.5h", Duration: time.Minute * 90},
+		{Input: "-1w", Duration: -time.Hour * 24 * 7},
+		{Input: "", Error: true},
+		{Input: "3", Error: true},
+		{Input: "3y", Error: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		It("parses '"+testCase.Input+"'", func() {
+			d, err := meetup.ParseDuration(testCase.Input)
+			if testCase.Error {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(time.Duration(d)).To(Equal(testCase.Duration))
+			}
+		})
+	}
+
+	It("formats days and weeks", func() {
+		Expect(meetup.Duration(time.Hour * 24 * 14).String()).To(Equal("2w"))
+		Expect(meetup.Duration(time.Hour * 24 * 3).String()).To(Equal("3d"))
+		Expect(meetup.Duration(time.Hour * 36).String()).To(Equal("36h0m0s"))
+	})
+})
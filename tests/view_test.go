@@ -0,0 +1,127 @@
+package meetup_test
+
+import (
+	"os"
+	"time"
+
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Views", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+
+	now := time.Date(2024, 3, 13, 12, 0, 0, 0, time.Local)
+
+	standup := meetup.Meeting{Name: "standup", Domain: "work", Date: "2024-03-11"}
+	retro := meetup.Meeting{Name: "retro", Domain: "work", Date: "2024-03-04"}
+	groceries := meetup.Meeting{Name: "groceries", Domain: "home", Date: "2024-03-12"}
+
+	contents := map[meetup.Meeting]string{
+		standup:   "- [ ] fix bug #urgent\n- [x] review\n",
+		retro:     "- [ ] plan #urgent\n",
+		groceries: "- [ ] milk #urgent\n",
+	}
+
+	BeforeAll(func() {
+		meetupDir, manager = newTestManager(contents)
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("validates views", func() {
+		Expect(meetup.View{Kind: meetup.ViewMeetings, Query: "domain:work"}.Validate()).To(Succeed())
+		Expect(meetup.View{Kind: "notes"}.Validate()).ToNot(Succeed())
+		Expect(meetup.View{Kind: meetup.ViewMeetings, Query: "is:complete"}.Validate()).ToNot(Succeed())
+		Expect(meetup.View{Kind: meetup.ViewTasks, Query: "is:complete"}.Validate()).To(Succeed())
+		Expect(meetup.View{Kind: meetup.ViewMeetings, Range: "next-year"}.Validate()).ToNot(Succeed())
+		Expect(meetup.View{Kind: meetup.ViewMeetings, Sort: "description"}.Validate()).ToNot(Succeed())
+		Expect(meetup.View{Kind: meetup.ViewTasks, Sort: "tasks"}.Validate()).ToNot(Succeed())
+		Expect(meetup.View{Kind: meetup.ViewTasks, Format: "xml"}.Validate()).ToNot(Succeed())
+	})
+
+	It("saves, lists, and removes views", func() {
+		work := meetup.View{Kind: meetup.ViewMeetings, Query: "domain:work", Sort: "-date"}
+		urgent := meetup.View{Kind: meetup.ViewTasks, Query: "tag:urgent AND is:incomplete", Range: "this-week", Format: meetup.FormatJSON}
+
+		Expect(manager.SaveView("work", work)).To(Succeed())
+		Expect(manager.SaveView("urgent", urgent)).To(Succeed())
+		Expect(manager.SaveView("", work)).ToNot(Succeed())
+		Expect(manager.SaveView("invalid", meetup.View{Kind: meetup.ViewMeetings, Query: "("})).ToNot(Succeed())
+
+		Expect(manager.Views()).To(Equal([]string{"urgent", "work"}))
+		Expect(manager.View("urgent")).To(Equal(urgent))
+
+		reloaded, err := meetup.NewManager(meetup.Config{RootDir: meetupDir, Editor: []string{"touch"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reloaded.View("work")).To(Equal(work))
+
+		Expect(manager.RemoveView("work")).To(Succeed())
+		Expect(manager.RemoveView("work")).ToNot(Succeed())
+		Expect(manager.Views()).To(Equal([]string{"urgent"}))
+	})
+
+	It("narrows meeting queries", func() {
+		view := meetup.View{Kind: meetup.ViewMeetings, Query: "domain:work", Range: "this-week"}
+
+		query, err := view.NarrowMeetings(meetup.MeetingQuery{}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manager.ListMeetings(query)).To(Equal([]meetup.Meeting{standup}))
+
+		flagQuery, err := meetup.ParseMeetingQuery("name:retro")
+		Expect(err).ToNot(HaveOccurred())
+
+		query, err = view.NarrowMeetings(meetup.MeetingQuery{Query: flagQuery}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manager.ListMeetings(query)).To(BeEmpty())
+	})
+
+	It("intersects date ranges", func() {
+		view := meetup.View{Kind: meetup.ViewMeetings, Range: "this-month"}
+
+		query, err := view.NarrowMeetings(meetup.MeetingQuery{Until: time.Date(2024, 3, 8, 0, 0, 0, 0, time.Local)}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(query.Since).To(Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)))
+		Expect(query.Until).To(Equal(time.Date(2024, 3, 8, 0, 0, 0, 0, time.Local)))
+		Expect(manager.ListMeetings(query)).To(Equal([]meetup.Meeting{retro}))
+	})
+
+	It("narrows task queries", func() {
+		view := meetup.View{Kind: meetup.ViewTasks, Query: "tag:urgent AND is:incomplete", Range: "this-week"}
+
+		query, err := view.NarrowTasks(meetup.TaskQuery{}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manager.Tasks(query)).To(Equal([]meetup.Task{
+			{Meeting: standup, State: meetup.TaskTodo, Description: "fix bug #urgent", Line: 1},
+			{Meeting: groceries, State: meetup.TaskTodo, Description: "milk #urgent", Line: 1},
+		}))
+
+		flagQuery, err := meetup.ParseTaskQuery("domain:work")
+		Expect(err).ToNot(HaveOccurred())
+
+		query, err = view.NarrowTasks(meetup.TaskQuery{Query: flagQuery}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manager.Tasks(query)).To(Equal([]meetup.Task{
+			{Meeting: standup, State: meetup.TaskTodo, Description: "fix bug #urgent", Line: 1},
+		}))
+	})
+
+	It("parses date ranges", func() {
+		since, until, err := meetup.ParseDateRange("last-week", now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(since).To(Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)))
+		Expect(until).To(Equal(time.Date(2024, 3, 10, 0, 0, 0, 0, time.Local)))
+
+		since, until, err = meetup.ParseDateRange("last-month", now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(since).To(Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)))
+		Expect(until).To(Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local)))
+
+		_, _, err = meetup.ParseDateRange("next-year", now)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,149 @@
+package meetup_test
+
+import (
+	"os"
+
+	meetup "github.com/joshmeranda/meetup/pkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WikiLinks", Ordered, func() {
+	var meetupDir string
+	var manager meetup.Manager
+	var err error
+
+	first := meetup.Meeting{Name: "standup", Domain: "work.team", Date: "2021-01-01"}
+	second := meetup.Meeting{Name: "standup", Domain: "work.team", Date: "2021-01-02"}
+	third := meetup.Meeting{Name: "standup", Domain: "work.team", Date: "2021-01-03"}
+	retro := meetup.Meeting{Name: "retro", Domain: "work.team", Date: "2021-01-03"}
+
+	read := func(meeting meetup.Meeting) string {
+		data, err := os.ReadFile(meeting.GetPath(meetupDir, meetup.GroupByDomain))
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	write := func(meeting meetup.Meeting, data string) {
+		Expect(os.WriteFile(meeting.GetPath(meetupDir, meetup.GroupByDomain), []byte(data), 0644)).To(Succeed())
+	}
+
+	targets := func(links []meetup.WikiLink) []string {
+		var result []string
+
+		for _, link := range links {
+			if link.Broken() {
+				result = append(result, link.Target+" -> broken")
+			} else {
+				result = append(result, link.Target+" -> "+link.Meeting.String())
+			}
+		}
+
+		return result
+	}
+
+	sources := func(links []meetup.WikiLink) []string {
+		var result []string
+
+		for _, link := range links {
+			result = append(result, link.Source.String())
+		}
+
+		return result
+	}
+
+	BeforeAll(func() {
+		meetupDir, err = os.MkdirTemp("", "meetup-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		manager, err = meetup.NewManager(meetup.Config{
+			RootDir: meetupDir,
+			Editor:  []string{"touch"},
+			DefaultMetadata: meetup.Metadata{
+				GroupBy: meetup.GroupByDomain,
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, meeting := range []meetup.Meeting{first, second, third, retro} {
+			Expect(manager.OpenMeeting(meeting)).To(Succeed())
+		}
+
+		write(second, "")
+		write(third, "- see [[work.team.standup@last|yesterday]]\n- and [[2021-01-01 work.team standup]]\n\n```\n[[work.team.ignored@last]]\n```\n")
+		write(retro, "[[work.team.standup]] [[missing]] [[2020-01-01 work.team standup]] [notes](../2021-01-02/standup) [gone](../2021-01-02/gone)\n")
+	})
+
+	AfterAll(func() {
+		os.RemoveAll(meetupDir)
+	})
+
+	It("resolves outgoing links", func() {
+		links, err := manager.Links(third)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(targets(links.Outgoing)).To(Equal([]string{
+			"work.team.standup@last -> 2021-01-02 work.team standup",
+			"2021-01-01 work.team standup -> 2021-01-01 work.team standup",
+		}))
+		Expect(links.Outgoing[0].Label).To(Equal("yesterday"))
+		Expect(links.Outgoing[1].Line).To(Equal(2))
+	})
+
+	It("resolves last links from other series on the same day", func() {
+		links, err := manager.Links(retro)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(targets(links.Outgoing)).To(Equal([]string{
+			"work.team.standup -> 2021-01-03 work.team standup",
+			"missing -> broken",
+			"2020-01-01 work.team standup -> broken",
+			"../2021-01-02/standup -> 2021-01-02 work.team standup",
+			"../2021-01-02/gone -> broken",
+		}))
+	})
+
+	It("finds incoming links", func() {
+		links, err := manager.Links(second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(links.Outgoing).To(BeEmpty())
+		Expect(sources(links.Incoming)).To(ConsistOf("2021-01-03 work.team standup", "2021-01-03 work.team retro"))
+	})
+
+	It("reports broken links", func() {
+		broken, err := manager.BrokenLinks()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(targets(broken)).To(ConsistOf("missing -> broken", "2020-01-01 work.team standup -> broken", "../2021-01-02/gone -> broken"))
+	})
+
+	It("fails for meetings which do not exist", func() {
+		_, err := manager.Links(meetup.Meeting{Name: "standup", Domain: "work.team", Date: "2020-01-01"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rewrites explicit links when meetings are moved", func() {
+		moved := meetup.Meeting{Name: "kickoff", Domain: "work.team", Date: "2020-12-31"}
+		Expect(manager.MoveMeeting(first, moved)).To(Succeed())
+		first = moved
+
+		Expect(read(third)).To(Equal("- see [[work.team.standup@last|yesterday]]\n- and [[2020-12-31 work.team kickoff]]\n\n```\n[[work.team.ignored@last]]\n```\n"))
+	})
+
+	It("rewrites last links when meetings are renamed", func() {
+		moved := meetup.Meeting{Name: "sync", Domain: "work.team", Date: "2021-01-02"}
+		Expect(manager.MoveMeeting(second, moved)).To(Succeed())
+		second = moved
+
+		Expect(read(third)).To(Equal("- see [[work.team.sync@last|yesterday]]\n- and [[2020-12-31 work.team kickoff]]\n\n```\n[[work.team.ignored@last]]\n```\n"))
+		Expect(read(retro)).To(Equal("[[work.team.standup]] [[missing]] [[2020-01-01 work.team standup]] [notes](../2021-01-02/sync) [gone](../2021-01-02/gone)\n"))
+	})
+
+	It("pins last links which would refer to a different meeting", func() {
+		earlier := meetup.Meeting{Name: "sync", Domain: "work.team", Date: "2021-01-01"}
+		Expect(manager.OpenMeeting(earlier)).To(Succeed())
+		write(earlier, "")
+
+		later := meetup.Meeting{Name: "sync", Domain: "work.team", Date: "2021-01-04"}
+		Expect(manager.MoveMeeting(second, later)).To(Succeed())
+
+		Expect(read(third)).To(Equal("- see [[work.team.sync@2021-01-04|yesterday]]\n- and [[2020-12-31 work.team kickoff]]\n\n```\n[[work.team.ignored@last]]\n```\n"))
+	})
+})